@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"github.com/andibalo/meowhasiswa-be"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/pkg/db"
 	"github.com/andibalo/meowhasiswa-be/pkg/trace"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.uber.org/zap"
 	"log"
 	"net/http"
 	"os"
@@ -33,16 +35,13 @@ func main() {
 		tracer = initTracer(cfg)
 	}
 
-	awsCreds := credentials.NewStaticCredentialsProvider(cfg.GetAWSCfg().ACCESS_KEY_ID, cfg.GetAWSCfg().SECRET_ACCESS_KEY, "")
+	var client *s3.Client
 
-	awsCfg, err := awsConfig.LoadDefaultConfig(context.TODO(), awsConfig.WithRegion(cfg.GetAWSCfg().Region), awsConfig.WithCredentialsProvider(awsCreds))
-	if err != nil {
-		log.Fatal(err)
+	// AWS is only needed when the files are stored in S3
+	if cfg.GetStorageCfg().Driver == constants.STORAGE_DRIVER_S3 {
+		client = initS3Client(cfg)
 	}
 
-	// Create an Amazon S3 service client
-	client := s3.NewFromConfig(awsCfg)
-
 	server := core.NewServer(cfg, tracer, database, client)
 
 	cfg.Logger().Info(fmt.Sprintf("Server starting at port %s", cfg.AppAddress()))
@@ -53,6 +52,9 @@ func main() {
 		}
 	}()
 
+	server.StartRealtimeHub()
+	server.StartWorkers()
+
 	quit := make(chan os.Signal, 1)
 
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -67,8 +69,23 @@ func main() {
 	// the request it is currently handling
 	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	// Close the open thread streams first, otherwise the server waits on them until the timeout
+	if err := server.ShutdownRealtimeHub(); err != nil {
+		cfg.Logger().Error("Realtime hub force to shutdown")
+	}
+
+	// The workers still have to be shut down and the database closed, so a forced shutdown is only logged
 	if err := server.Shutdown(ctx); err != nil {
-		cfg.Logger().Fatal("Server force to shutdown")
+		cfg.Logger().Error("Server force to shutdown", zap.Error(err))
+	}
+
+	// Let the workers, such as the outbox dispatcher, finish their in-flight batch before closing the database.
+	// They get their own 5 seconds so a slow server shutdown does not cut them short
+	workersCtx, workersCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer workersCancel()
+
+	if err := server.ShutdownWorkers(workersCtx); err != nil {
+		cfg.Logger().Error("Workers force to shutdown", zap.Error(err))
 	}
 
 	_ = database.Close()
@@ -76,6 +93,19 @@ func main() {
 	cfg.Logger().Info("Server exiting")
 }
 
+func initS3Client(cfg config.Config) *s3.Client {
+
+	awsCreds := credentials.NewStaticCredentialsProvider(cfg.GetAWSCfg().ACCESS_KEY_ID, cfg.GetAWSCfg().SECRET_ACCESS_KEY, "")
+
+	awsCfg, err := awsConfig.LoadDefaultConfig(context.TODO(), awsConfig.WithRegion(cfg.GetAWSCfg().Region), awsConfig.WithCredentialsProvider(awsCreds))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create an Amazon S3 service client
+	return s3.NewFromConfig(awsCfg)
+}
+
 func initTracer(cfg config.Config) *trace.Tracer {
 
 	traceConfig := cfg.TraceConfig()
@@ -2,6 +2,8 @@ package v1
 
 import (
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/middleware"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
 	"github.com/andibalo/meowhasiswa-be/internal/service"
@@ -15,13 +17,15 @@ import (
 
 type AuthController struct {
 	cfg     config.Config
+	mw      *middleware.Middleware
 	authSvc service.AuthService
 }
 
-func NewAuthController(cfg config.Config, authSvc service.AuthService) *AuthController {
+func NewAuthController(cfg config.Config, mw *middleware.Middleware, authSvc service.AuthService) *AuthController {
 
 	return &AuthController{
 		cfg:     cfg,
+		mw:      mw,
 		authSvc: authSvc,
 	}
 }
@@ -29,12 +33,18 @@ func NewAuthController(cfg config.Config, authSvc service.AuthService) *AuthCont
 func (h *AuthController) AddRoutes(r *gin.Engine) {
 	ar := r.Group("/api/v1/auth")
 
-	ar.POST("/register", h.Register)
-	ar.POST("/login", h.Login)
-	ar.POST("/verify-email", h.VerifyEmail)
-	ar.PATCH("/reset-password", h.ResetPassword)
-	ar.PATCH("/reset-password/code/verify", h.VerifyResetPassword)
-	ar.POST("/reset-password/code/send", h.SendResetPasswordLink)
+	authRateLimit := h.mw.RateLimitMiddleware(constants.RATE_LIMIT_GROUP_AUTH, h.cfg.GetRateLimitCfg().Auth)
+	tokenRefreshRateLimit := h.mw.RateLimitMiddleware(constants.RATE_LIMIT_GROUP_TOKEN_REFRESH, h.cfg.GetRateLimitCfg().TokenRefresh)
+
+	ar.POST("/register", authRateLimit, h.Register)
+	ar.POST("/login", authRateLimit, h.Login)
+	ar.POST("/token/refresh", tokenRefreshRateLimit, h.RefreshToken)
+	ar.POST("/logout", h.mw.JwtMiddleware(), h.Logout)
+	ar.POST("/logout/all", h.mw.JwtMiddleware(), h.LogoutAllDevices)
+	ar.POST("/verify-email", authRateLimit, h.VerifyEmail)
+	ar.PATCH("/reset-password", authRateLimit, h.ResetPassword)
+	ar.PATCH("/reset-password/code/verify", authRateLimit, h.VerifyResetPassword)
+	ar.POST("/reset-password/code/send", authRateLimit, h.SendResetPasswordLink)
 }
 
 func (h *AuthController) Register(c *gin.Context) {
@@ -72,14 +82,96 @@ func (h *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authSvc.Login(c.Request.Context(), data)
+	data.UserAgent = c.Request.UserAgent()
+	data.IPAddress = c.ClientIP()
+
+	resp, err := h.authSvc.Login(c.Request.Context(), data)
 	if err != nil {
 		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[Login] Failed to login", zap.Error(err))
 		httpresp.HttpRespError(c, err)
 		return
 	}
 
-	httpresp.HttpRespSuccess(c, token, nil)
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *AuthController) RefreshToken(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "AuthController.RefreshToken", "controller")
+	//defer endFunc()
+
+	var data request.RefreshTokenReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[RefreshToken] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserAgent = c.Request.UserAgent()
+	data.IPAddress = c.ClientIP()
+
+	resp, err := h.authSvc.RefreshToken(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[RefreshToken] Failed to refresh token", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *AuthController) Logout(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "AuthController.Logout", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	data := request.LogoutReq{
+		SessionID: claims.SessionID,
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	err := h.authSvc.Logout(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[Logout] Failed to logout", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *AuthController) LogoutAllDevices(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "AuthController.LogoutAllDevices", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	data := request.LogoutAllDevicesReq{
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	err := h.authSvc.LogoutAllDevices(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[LogoutAllDevices] Failed to logout from all devices", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
 	return
 }
 
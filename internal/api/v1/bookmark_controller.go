@@ -0,0 +1,255 @@
+package v1
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/middleware"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type BookmarkController struct {
+	cfg         config.Config
+	mw          *middleware.Middleware
+	bookmarkSvc service.BookmarkService
+}
+
+func NewBookmarkController(cfg config.Config, mw *middleware.Middleware, bookmarkSvc service.BookmarkService) *BookmarkController {
+
+	return &BookmarkController{
+		cfg:         cfg,
+		mw:          mw,
+		bookmarkSvc: bookmarkSvc,
+	}
+}
+
+func (h *BookmarkController) AddRoutes(r *gin.Engine) {
+	br := r.Group("/api/v1/bookmark")
+
+	br.GET("", h.mw.JwtMiddleware(), h.GetBookmarkList)
+	br.POST("", h.mw.JwtMiddleware(), h.SaveBookmark)
+	br.DELETE("/:target_type/:target_id", h.mw.JwtMiddleware(), h.DeleteBookmark)
+	br.GET("/collection", h.mw.JwtMiddleware(), h.GetBookmarkCollections)
+	br.POST("/collection", h.mw.JwtMiddleware(), h.CreateBookmarkCollection)
+	br.PATCH("/collection/:collection_id", h.mw.JwtMiddleware(), h.UpdateBookmarkCollection)
+	br.DELETE("/collection/:collection_id", h.mw.JwtMiddleware(), h.DeleteBookmarkCollection)
+}
+
+func (h *BookmarkController) GetBookmarkList(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "BookmarkController.GetBookmarkList", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data := request.GetBookmarkListReq{
+		CollectionID: c.Query("collection_id"),
+		TargetType:   c.Query("target_type"),
+		Limit:        limit,
+		Cursor:       c.Query("cursor"),
+		UserID:       claims.ID,
+		UserEmail:    claims.Email,
+	}
+
+	resp, err := h.bookmarkSvc.GetBookmarkList(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetBookmarkList] Failed to get bookmark list", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *BookmarkController) SaveBookmark(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "BookmarkController.SaveBookmark", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.SaveBookmarkReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[SaveBookmark] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.bookmarkSvc.SaveBookmark(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[SaveBookmark] Failed to save bookmark", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *BookmarkController) DeleteBookmark(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "BookmarkController.DeleteBookmark", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	data := request.DeleteBookmarkReq{
+		TargetType: c.Param("target_type"),
+		TargetID:   c.Param("target_id"),
+		UserID:     claims.ID,
+		UserEmail:  claims.Email,
+	}
+
+	err := h.bookmarkSvc.DeleteBookmark(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[DeleteBookmark] Failed to delete bookmark", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *BookmarkController) GetBookmarkCollections(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "BookmarkController.GetBookmarkCollections", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	resp, err := h.bookmarkSvc.GetBookmarkCollections(c.Request.Context(), request.GetBookmarkCollectionsReq{
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	})
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetBookmarkCollections] Failed to get bookmark collections", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *BookmarkController) CreateBookmarkCollection(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "BookmarkController.CreateBookmarkCollection", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.CreateBookmarkCollectionReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[CreateBookmarkCollection] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.bookmarkSvc.CreateBookmarkCollection(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[CreateBookmarkCollection] Failed to create bookmark collection", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *BookmarkController) UpdateBookmarkCollection(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "BookmarkController.UpdateBookmarkCollection", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.UpdateBookmarkCollectionReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UpdateBookmarkCollection] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.CollectionID = c.Param("collection_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.bookmarkSvc.UpdateBookmarkCollection(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UpdateBookmarkCollection] Failed to update bookmark collection", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *BookmarkController) DeleteBookmarkCollection(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "BookmarkController.DeleteBookmarkCollection", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	data := request.DeleteBookmarkCollectionReq{
+		CollectionID: c.Param("collection_id"),
+		UserID:       claims.ID,
+		UserEmail:    claims.Email,
+	}
+
+	err := h.bookmarkSvc.DeleteBookmarkCollection(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[DeleteBookmarkCollection] Failed to delete bookmark collection", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
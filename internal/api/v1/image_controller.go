@@ -4,13 +4,13 @@ import (
 	"github.com/andibalo/meowhasiswa-be/internal/config"
 	"github.com/andibalo/meowhasiswa-be/internal/middleware"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
 	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
-	"github.com/gabriel-vasile/mimetype"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/samber/oops"
 	"go.uber.org/zap"
 	"net/http"
@@ -18,13 +18,15 @@ import (
 
 type ImageController struct {
 	cfg      config.Config
+	mw       *middleware.Middleware
 	imageSvc service.ImageService
 }
 
-func NewImageController(cfg config.Config, imageSvc service.ImageService) *ImageController {
+func NewImageController(cfg config.Config, mw *middleware.Middleware, imageSvc service.ImageService) *ImageController {
 
 	return &ImageController{
 		cfg:      cfg,
+		mw:       mw,
 		imageSvc: imageSvc,
 	}
 }
@@ -32,14 +34,23 @@ func NewImageController(cfg config.Config, imageSvc service.ImageService) *Image
 func (h *ImageController) AddRoutes(r *gin.Engine) {
 	ir := r.Group("/api/v1/image")
 
-	ir.POST("/upload", middleware.JwtMiddleware(h.cfg), h.UploadImage)
+	ir.POST("/upload", h.mw.JwtMiddleware(), h.UploadImage)
+	ir.POST("/upload/presign", h.mw.JwtMiddleware(), h.PresignImageUpload)
+	ir.POST("/upload/:upload_id/confirm", h.mw.JwtMiddleware(), h.ConfirmImageUpload)
 }
 
 func (h *ImageController) UploadImage(c *gin.Context) {
 	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ImageController.UploadImage", "controller")
 	//defer endFunc()
 
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
 	fileNameParam := c.PostForm("file_name")
+	altTextParam := c.PostForm("alt_text")
 
 	file, err := c.FormFile("image")
 
@@ -66,34 +77,95 @@ func (h *ImageController) UploadImage(c *gin.Context) {
 
 	defer srcFile.Close()
 
-	contentType, err := mimetype.DetectReader(srcFile)
-	if err != nil {
-		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UploadImage] Failed to detect mime type", zap.Error(err))
-		httpresp.HttpRespError(c, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrBadRequest))
-		return
-	}
-
 	var fileName string
 
 	fileName = file.Filename
 
 	if fileNameParam != "" {
-		fileName = fileNameParam + contentType.Extension()
+		fileName = fileNameParam
+	}
+
+	// The content type is sniffed and the storage key derived from the processed content by the image service
+	data := request.UploadImageReq{
+		File: model.File{
+			Reader:           srcFile,
+			OriginalFileName: fileName,
+			Size:             file.Size,
+		},
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
 	}
 
-	uniqueFileName := uuid.New().String() + "_" + fileName
+	if altTextParam != "" {
+		data.AltText = pkg.ToPointer(pkg.TruncateWithEllipsis(altTextParam, 495))
+	}
 
-	resp, err := h.imageSvc.UploadImage(c.Request.Context(), model.File{
-		Reader:           srcFile,
-		OriginalFileName: fileName,
-		FileName:         uniqueFileName,
-		Size:             file.Size,
-		ContentType:      contentType.String(),
-	})
+	resp, err := h.imageSvc.UploadImage(c.Request.Context(), data)
 
 	if err != nil {
 		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UploadImage] Failed to upload image", zap.Error(err))
-		httpresp.HttpRespError(c, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrBadRequest))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+
+	return
+}
+
+func (h *ImageController) PresignImageUpload(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ImageController.PresignImageUpload", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.PresignImageUploadReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[PresignImageUpload] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	resp, err := h.imageSvc.PresignImageUpload(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[PresignImageUpload] Failed to presign image upload", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+
+	return
+}
+
+func (h *ImageController) ConfirmImageUpload(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ImageController.ConfirmImageUpload", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	data := request.ConfirmImageUploadReq{
+		UploadID:  c.Param("upload_id"),
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	resp, err := h.imageSvc.ConfirmImageUpload(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[ConfirmImageUpload] Failed to confirm image upload", zap.Error(err))
+		httpresp.HttpRespError(c, err)
 		return
 	}
 
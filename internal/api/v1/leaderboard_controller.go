@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/middleware"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strings"
+)
+
+type LeaderboardController struct {
+	cfg            config.Config
+	mw             *middleware.Middleware
+	leaderboardSvc service.LeaderboardService
+}
+
+func NewLeaderboardController(cfg config.Config, mw *middleware.Middleware, leaderboardSvc service.LeaderboardService) *LeaderboardController {
+
+	return &LeaderboardController{
+		cfg:            cfg,
+		mw:             mw,
+		leaderboardSvc: leaderboardSvc,
+	}
+}
+
+func (h *LeaderboardController) AddRoutes(r *gin.Engine) {
+	lr := r.Group("/api/v1/leaderboard")
+
+	lr.GET("", h.mw.JwtMiddleware(), h.GetLeaderboard)
+}
+
+func (h *LeaderboardController) GetLeaderboard(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "LeaderboardController.GetLeaderboard", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data := request.GetLeaderboardReq{
+		Period:       strings.ToUpper(c.Query("period")),
+		UniversityID: c.Query("university_id"),
+		SubThreadID:  c.Query("subthread_id"),
+		Limit:        limit,
+		UserID:       claims.ID,
+	}
+
+	resp, err := h.leaderboardSvc.GetLeaderboard(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetLeaderboard] Failed to get leaderboard", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
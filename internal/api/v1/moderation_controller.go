@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/middleware"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ModerationController struct {
+	cfg           config.Config
+	mw            *middleware.Middleware
+	moderationSvc service.ModerationService
+}
+
+func NewModerationController(cfg config.Config, mw *middleware.Middleware, moderationSvc service.ModerationService) *ModerationController {
+
+	return &ModerationController{
+		cfg:           cfg,
+		mw:            mw,
+		moderationSvc: moderationSvc,
+	}
+}
+
+func (h *ModerationController) AddRoutes(r *gin.Engine) {
+	mr := r.Group("/api/v1/moderation")
+
+	mr.POST("/report", h.mw.JwtMiddleware(), h.CreateReport)
+	mr.GET("/queue", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MODERATE_CONTENT), h.GetModerationQueue)
+	mr.GET("/report/:content_type/:content_id", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MODERATE_CONTENT), h.GetContentReports)
+	mr.POST("/resolve", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MODERATE_CONTENT), h.ResolveReport)
+}
+
+func (h *ModerationController) CreateReport(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ModerationController.CreateReport", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.CreateReportReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[CreateReport] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.moderationSvc.CreateReport(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[CreateReport] Failed to create report", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ModerationController) GetModerationQueue(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ModerationController.GetModerationQueue", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetModerationQueueReq
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	minReportCount, err := pkg.GetIntQueryParams(c, 0, "min_report_count")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data.Limit = limit
+	data.MinReportCount = minReportCount
+	data.Cursor = c.Query("cursor")
+	data.Status = c.Query("status")
+	data.ContentType = c.Query("content_type")
+	data.Reason = c.Query("reason")
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	resp, err := h.moderationSvc.GetModerationQueue(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetModerationQueue] Failed to get moderation queue", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *ModerationController) GetContentReports(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ModerationController.GetContentReports", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetContentReportsReq
+
+	data.ContentType = c.Param("content_type")
+	data.ContentID = c.Param("content_id")
+	data.Status = c.Query("status")
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	reports, err := h.moderationSvc.GetContentReports(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetContentReports] Failed to get content reports", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, reports, nil)
+	return
+}
+
+func (h *ModerationController) ResolveReport(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ModerationController.ResolveReport", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.ResolveReportReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[ResolveReport] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.moderationSvc.ResolveReport(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[ResolveReport] Failed to resolve report", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
 	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
 	"github.com/gin-gonic/gin"
@@ -16,13 +17,15 @@ import (
 
 type NotificationController struct {
 	cfg      config.Config
+	mw       *middleware.Middleware
 	notifSvc service.NotificationService
 }
 
-func NewNotificationController(cfg config.Config, notifSvc service.NotificationService) *NotificationController {
+func NewNotificationController(cfg config.Config, mw *middleware.Middleware, notifSvc service.NotificationService) *NotificationController {
 
 	return &NotificationController{
 		cfg:      cfg,
+		mw:       mw,
 		notifSvc: notifSvc,
 	}
 }
@@ -30,7 +33,132 @@ func NewNotificationController(cfg config.Config, notifSvc service.NotificationS
 func (h *NotificationController) AddRoutes(r *gin.Engine) {
 	nr := r.Group("/api/v1/notification")
 
-	nr.POST("/push", middleware.JwtMiddleware(h.cfg), h.SendPushNotification)
+	nr.GET("", h.mw.JwtMiddleware(), h.GetNotificationList)
+	nr.GET("/unread/count", h.mw.JwtMiddleware(), h.GetUnreadNotificationCount)
+	nr.PATCH("/read/all", h.mw.JwtMiddleware(), h.MarkAllNotificationsAsRead)
+	nr.PATCH("/read/:notification_id", h.mw.JwtMiddleware(), h.MarkNotificationAsRead)
+	nr.POST("/push", h.mw.JwtMiddleware(), h.SendPushNotification)
+}
+
+func (h *NotificationController) GetNotificationList(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "NotificationController.GetNotificationList", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	isUnreadOnly, err := pkg.GetBoolQueryParams(c, "is_unread_only")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data := request.GetNotificationListReq{
+		IsUnreadOnly: isUnreadOnly,
+		Limit:        limit,
+		Cursor:       c.Query("cursor"),
+		UserID:       claims.ID,
+		UserEmail:    claims.Email,
+	}
+
+	resp, err := h.notifSvc.GetNotificationList(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetNotificationList] Failed to get notification list", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+
+	return
+}
+
+func (h *NotificationController) GetUnreadNotificationCount(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "NotificationController.GetUnreadNotificationCount", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	resp, err := h.notifSvc.GetUnreadNotificationCount(c.Request.Context(), request.GetUnreadNotificationCountReq{
+		UserID: claims.ID,
+	})
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetUnreadNotificationCount] Failed to get unread notification count", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+
+	return
+}
+
+func (h *NotificationController) MarkNotificationAsRead(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "NotificationController.MarkNotificationAsRead", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	data := request.MarkNotificationAsReadReq{
+		NotificationID: c.Param("notification_id"),
+		UserID:         claims.ID,
+		UserEmail:      claims.Email,
+	}
+
+	err := h.notifSvc.MarkNotificationAsRead(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[MarkNotificationAsRead] Failed to mark notification as read", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+
+	return
+}
+
+func (h *NotificationController) MarkAllNotificationsAsRead(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "NotificationController.MarkAllNotificationsAsRead", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	data := request.MarkAllNotificationsAsReadReq{
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	err := h.notifSvc.MarkAllNotificationsAsRead(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[MarkAllNotificationsAsRead] Failed to mark all notifications as read", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+
+	return
 }
 
 func (h *NotificationController) SendPushNotification(c *gin.Context) {
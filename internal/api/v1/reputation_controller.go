@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/middleware"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ReputationController struct {
+	cfg           config.Config
+	mw            *middleware.Middleware
+	reputationSvc service.ReputationService
+}
+
+func NewReputationController(cfg config.Config, mw *middleware.Middleware, reputationSvc service.ReputationService) *ReputationController {
+
+	return &ReputationController{
+		cfg:           cfg,
+		mw:            mw,
+		reputationSvc: reputationSvc,
+	}
+}
+
+func (h *ReputationController) AddRoutes(r *gin.Engine) {
+	rr := r.Group("/api/v1/reputation")
+
+	rr.GET("/user/:username", h.mw.OptionalJwtMiddleware(), h.GetReputationHistory)
+	rr.POST("/recompute", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MANAGE_REPUTATION), h.RecomputeReputation)
+}
+
+func (h *ReputationController) GetReputationHistory(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ReputationController.GetReputationHistory", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data := request.GetReputationHistoryReq{
+		Username:  c.Param("username"),
+		Limit:     limit,
+		Cursor:    c.Query("cursor"),
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	resp, err := h.reputationSvc.GetReputationHistory(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetReputationHistory] Failed to get reputation history", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *ReputationController) RecomputeReputation(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ReputationController.RecomputeReputation", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.RecomputeReputationReq
+
+	// The body is optional, an empty body recomputes every user
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&data); err != nil {
+			h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[RecomputeReputation] Failed to bind json", zap.Error(err))
+			httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+			return
+		}
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	resp, err := h.reputationSvc.RecomputeReputation(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[RecomputeReputation] Failed to recompute reputation", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
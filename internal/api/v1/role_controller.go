@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/middleware"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type RoleController struct {
+	cfg     config.Config
+	mw      *middleware.Middleware
+	roleSvc service.RoleService
+}
+
+func NewRoleController(cfg config.Config, mw *middleware.Middleware, roleSvc service.RoleService) *RoleController {
+
+	return &RoleController{
+		cfg:     cfg,
+		mw:      mw,
+		roleSvc: roleSvc,
+	}
+}
+
+func (h *RoleController) AddRoutes(r *gin.Engine) {
+	rr := r.Group("/api/v1/role")
+
+	rr.GET("", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MANAGE_ROLE), h.GetRoles)
+	rr.GET("/user/:user_id", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MANAGE_ROLE), h.GetUserRoles)
+	rr.POST("/grant", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MANAGE_ROLE), h.GrantRole)
+	rr.POST("/revoke", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_MANAGE_ROLE), h.RevokeRole)
+}
+
+func (h *RoleController) GetRoles(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "RoleController.GetRoles", "controller")
+	//defer endFunc()
+
+	roles, err := h.roleSvc.GetRoles(c.Request.Context())
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetRoles] Failed to get roles", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, roles, nil)
+	return
+}
+
+func (h *RoleController) GetUserRoles(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "RoleController.GetUserRoles", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetUserRolesReq
+
+	data.TargetUserID = c.Param("user_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	userRoles, err := h.roleSvc.GetUserRoles(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetUserRoles] Failed to get user roles", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, userRoles, nil)
+	return
+}
+
+func (h *RoleController) GrantRole(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "RoleController.GrantRole", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GrantRoleReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GrantRole] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.roleSvc.GrantRole(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GrantRole] Failed to grant role", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *RoleController) RevokeRole(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "RoleController.RevokeRole", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.RevokeRoleReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[RevokeRole] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.roleSvc.RevokeRole(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[RevokeRole] Failed to revoke role", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/middleware"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type SearchController struct {
+	cfg       config.Config
+	mw        *middleware.Middleware
+	searchSvc service.SearchService
+}
+
+func NewSearchController(cfg config.Config, mw *middleware.Middleware, searchSvc service.SearchService) *SearchController {
+
+	return &SearchController{
+		cfg:       cfg,
+		mw:        mw,
+		searchSvc: searchSvc,
+	}
+}
+
+func (h *SearchController) AddRoutes(r *gin.Engine) {
+	sr := r.Group("/api/v1/search")
+
+	sr.GET("", h.mw.JwtMiddleware(), h.Search)
+}
+
+func (h *SearchController) Search(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SearchController.Search", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.SearchReq
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data.Limit = limit
+	data.Query = c.Query("_q")
+	data.Type = c.Query("type")
+	data.Cursor = c.Query("cursor")
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	resp, err := h.searchSvc.Search(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[Search] Failed to search", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
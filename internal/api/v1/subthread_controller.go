@@ -2,6 +2,7 @@ package v1
 
 import (
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/middleware"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
@@ -17,13 +18,15 @@ import (
 
 type SubThreadController struct {
 	cfg          config.Config
+	mw           *middleware.Middleware
 	subThreadSvc service.SubThreadService
 }
 
-func NewSubThreadController(cfg config.Config, subThreadSvc service.SubThreadService) *SubThreadController {
+func NewSubThreadController(cfg config.Config, mw *middleware.Middleware, subThreadSvc service.SubThreadService) *SubThreadController {
 
 	return &SubThreadController{
 		cfg:          cfg,
+		mw:           mw,
 		subThreadSvc: subThreadSvc,
 	}
 }
@@ -31,13 +34,23 @@ func NewSubThreadController(cfg config.Config, subThreadSvc service.SubThreadSer
 func (h *SubThreadController) AddRoutes(r *gin.Engine) {
 	str := r.Group("/api/v1/subthread")
 
-	str.GET("", middleware.JwtMiddleware(h.cfg), h.GetListSubThread)
-	str.POST("", middleware.JwtMiddleware(h.cfg), h.CreateSubThread)
-	str.GET("/:subthread_id", middleware.JwtMiddleware(h.cfg), h.GetSubThreadByID)
-	str.PATCH("/:subthread_id", middleware.JwtMiddleware(h.cfg), h.UpdateSubThread)
-	str.DELETE("/:subthread_id", middleware.JwtMiddleware(h.cfg), h.DeleteSubThread)
-	str.POST("/follow", middleware.JwtMiddleware(h.cfg), h.FollowSubThread)
-	str.PATCH("/unfollow", middleware.JwtMiddleware(h.cfg), h.UnfollowSubThread)
+	str.GET("", h.mw.JwtMiddleware(), h.GetListSubThread)
+	str.POST("", h.mw.JwtMiddleware(), h.CreateSubThread)
+	str.GET("/:subthread_id", h.mw.JwtMiddleware(), h.GetSubThreadByID)
+	str.PATCH("/:subthread_id", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_MANAGE_SUBTHREAD, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.UpdateSubThread)
+	str.DELETE("/:subthread_id", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_DELETE_SUBTHREAD, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.DeleteSubThread)
+	str.GET("/:subthread_id/moderator", h.mw.JwtMiddleware(), h.GetSubThreadModerators)
+	str.POST("/:subthread_id/moderator", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_MANAGE_SUBTHREAD_MODERATOR, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.AddSubThreadModerator)
+	str.DELETE("/:subthread_id/moderator/:user_id", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_MANAGE_SUBTHREAD_MODERATOR, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.RemoveSubThreadModerator)
+	str.GET("/:subthread_id/rule", h.mw.JwtMiddleware(), h.GetSubThreadRules)
+	str.POST("/:subthread_id/rule", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_MANAGE_SUBTHREAD, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.CreateSubThreadRule)
+	str.PATCH("/:subthread_id/rule/:rule_id", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_MANAGE_SUBTHREAD, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.UpdateSubThreadRule)
+	str.DELETE("/:subthread_id/rule/:rule_id", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_MANAGE_SUBTHREAD, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.DeleteSubThreadRule)
+	str.GET("/:subthread_id/ban", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_BAN_USER, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.GetSubThreadBans)
+	str.POST("/:subthread_id/ban", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_BAN_USER, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.BanSubThreadUser)
+	str.DELETE("/:subthread_id/ban/:user_id", h.mw.JwtMiddleware(), h.mw.ScopedPermissionMiddleware(constants.PERMISSION_BAN_USER, constants.SCOPE_TYPE_SUBTHREAD, "subthread_id"), h.UnbanSubThreadUser)
+	str.POST("/follow", h.mw.JwtMiddleware(), h.FollowSubThread)
+	str.PATCH("/unfollow", h.mw.JwtMiddleware(), h.UnfollowSubThread)
 }
 
 func (h *SubThreadController) GetListSubThread(c *gin.Context) {
@@ -116,6 +129,7 @@ func (h *SubThreadController) CreateSubThread(c *gin.Context) {
 		return
 	}
 
+	data.UserID = claims.ID
 	data.UserEmail = claims.Email
 
 	err := h.subThreadSvc.CreateSubThread(c.Request.Context(), data)
@@ -176,6 +190,7 @@ func (h *SubThreadController) UpdateSubThread(c *gin.Context) {
 	}
 
 	data.SubThreadID = c.Param("subthread_id")
+	data.UserID = claims.ID
 	data.UserEmail = claims.Email
 
 	err := h.subThreadSvc.UpdateSubThread(c.Request.Context(), data)
@@ -279,3 +294,295 @@ func (h *SubThreadController) UnfollowSubThread(c *gin.Context) {
 	httpresp.HttpRespSuccess(c, nil, nil)
 	return
 }
+
+func (h *SubThreadController) GetSubThreadModerators(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.GetSubThreadModerators", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetSubThreadModeratorsReq
+
+	data.SubThreadID = c.Param("subthread_id")
+
+	resp, err := h.subThreadSvc.GetSubThreadModerators(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetSubThreadModerators] Failed to get subthread moderators", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *SubThreadController) AddSubThreadModerator(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.AddSubThreadModerator", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.AddSubThreadModeratorReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[AddSubThreadModerator] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.SubThreadID = c.Param("subthread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.subThreadSvc.AddSubThreadModerator(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[AddSubThreadModerator] Failed to add subthread moderator", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *SubThreadController) RemoveSubThreadModerator(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.RemoveSubThreadModerator", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.RemoveSubThreadModeratorReq
+
+	data.SubThreadID = c.Param("subthread_id")
+	data.TargetUserID = c.Param("user_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.subThreadSvc.RemoveSubThreadModerator(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[RemoveSubThreadModerator] Failed to remove subthread moderator", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *SubThreadController) GetSubThreadRules(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.GetSubThreadRules", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetSubThreadRulesReq
+
+	data.SubThreadID = c.Param("subthread_id")
+
+	resp, err := h.subThreadSvc.GetSubThreadRules(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetSubThreadRules] Failed to get subthread rules", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *SubThreadController) CreateSubThreadRule(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.CreateSubThreadRule", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.CreateSubThreadRuleReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[CreateSubThreadRule] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.SubThreadID = c.Param("subthread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.subThreadSvc.CreateSubThreadRule(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[CreateSubThreadRule] Failed to create subthread rule", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *SubThreadController) UpdateSubThreadRule(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.UpdateSubThreadRule", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.UpdateSubThreadRuleReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UpdateSubThreadRule] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.SubThreadID = c.Param("subthread_id")
+	data.RuleID = c.Param("rule_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.subThreadSvc.UpdateSubThreadRule(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UpdateSubThreadRule] Failed to update subthread rule", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *SubThreadController) DeleteSubThreadRule(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.DeleteSubThreadRule", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.DeleteSubThreadRuleReq
+
+	data.SubThreadID = c.Param("subthread_id")
+	data.RuleID = c.Param("rule_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.subThreadSvc.DeleteSubThreadRule(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[DeleteSubThreadRule] Failed to delete subthread rule", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *SubThreadController) GetSubThreadBans(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.GetSubThreadBans", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetSubThreadBansReq
+
+	data.SubThreadID = c.Param("subthread_id")
+
+	resp, err := h.subThreadSvc.GetSubThreadBans(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetSubThreadBans] Failed to get subthread bans", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *SubThreadController) BanSubThreadUser(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.BanSubThreadUser", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.BanSubThreadUserReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[BanSubThreadUser] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.SubThreadID = c.Param("subthread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.subThreadSvc.BanSubThreadUser(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[BanSubThreadUser] Failed to ban subthread user", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *SubThreadController) UnbanSubThreadUser(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "SubThreadController.UnbanSubThreadUser", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.UnbanSubThreadUserReq
+
+	data.SubThreadID = c.Param("subthread_id")
+	data.TargetUserID = c.Param("user_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.subThreadSvc.UnbanSubThreadUser(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UnbanSubThreadUser] Failed to unban subthread user", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
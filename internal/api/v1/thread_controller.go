@@ -2,6 +2,7 @@ package v1
 
 import (
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/middleware"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
@@ -12,18 +13,25 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/samber/oops"
 	"go.uber.org/zap"
+	"io"
 	"net/http"
+	"time"
 )
 
+// streamHeartbeatInterval keeps idle thread streams from being closed by proxies and load balancers
+const streamHeartbeatInterval = 25 * time.Second
+
 type ThreadController struct {
 	cfg       config.Config
+	mw        *middleware.Middleware
 	threadSvc service.ThreadService
 }
 
-func NewThreadController(cfg config.Config, threadSvc service.ThreadService) *ThreadController {
+func NewThreadController(cfg config.Config, mw *middleware.Middleware, threadSvc service.ThreadService) *ThreadController {
 
 	return &ThreadController{
 		cfg:       cfg,
+		mw:        mw,
 		threadSvc: threadSvc,
 	}
 }
@@ -31,24 +39,39 @@ func NewThreadController(cfg config.Config, threadSvc service.ThreadService) *Th
 func (h *ThreadController) AddRoutes(r *gin.Engine) {
 	tr := r.Group("/api/v1/thread")
 
-	tr.POST("", middleware.JwtMiddleware(h.cfg), h.CreateThread)
-	tr.GET("", middleware.JwtMiddleware(h.cfg), h.GetThreadList)
-	tr.GET("/:thread_id", middleware.JwtMiddleware(h.cfg), h.GetThreadDetail)
-	tr.DELETE("/:thread_id", middleware.JwtMiddleware(h.cfg), h.DeleteThread)
-	tr.PATCH("/:thread_id", middleware.JwtMiddleware(h.cfg), h.UpdateThread)
-	tr.POST("/subscribe/:thread_id", middleware.JwtMiddleware(h.cfg), h.SubscribeThread)
-	tr.PATCH("/unsubscribe/:thread_id", middleware.JwtMiddleware(h.cfg), h.UnSubscribeThread)
-	tr.PATCH("/like/:thread_id", middleware.JwtMiddleware(h.cfg), h.LikeThread)
-	tr.PATCH("/dislike/:thread_id", middleware.JwtMiddleware(h.cfg), h.DislikeThread)
-	tr.GET("/comment/:thread_id", middleware.JwtMiddleware(h.cfg), h.GetThreadComments)
-	tr.POST("/comment/:thread_id", middleware.JwtMiddleware(h.cfg), h.CommentThread)
-	tr.DELETE("/comment/:comment_id", middleware.JwtMiddleware(h.cfg), h.DeleteThreadComment)
-	tr.PATCH("/comment/:comment_id", middleware.JwtMiddleware(h.cfg), h.UpdateThreadComment)
-	tr.POST("/comment/reply/:comment_id", middleware.JwtMiddleware(h.cfg), h.ReplyComment)
-	tr.DELETE("/comment/reply/:comment_id", middleware.JwtMiddleware(h.cfg), h.DeleteThreadCommentReply)
-	tr.PATCH("/comment/reply/:comment_id", middleware.JwtMiddleware(h.cfg), h.UpdateThreadCommentReply)
-	tr.PATCH("/comment/like/:comment_id", middleware.JwtMiddleware(h.cfg), h.LikeComment)
-	tr.PATCH("/comment/dislike/:comment_id", middleware.JwtMiddleware(h.cfg), h.DislikeComment)
+	writeRateLimit := h.mw.RateLimitMiddleware(constants.RATE_LIMIT_GROUP_THREAD_WRITE, h.cfg.GetRateLimitCfg().ThreadWrite)
+
+	tr.POST("", h.mw.JwtMiddleware(), writeRateLimit, h.CreateThread)
+	tr.GET("", h.mw.JwtMiddleware(), h.GetThreadList)
+	tr.GET("/:thread_id", h.mw.JwtMiddleware(), h.GetThreadDetail)
+	tr.DELETE("/:thread_id", h.mw.JwtMiddleware(), h.DeleteThread)
+	tr.PATCH("/:thread_id", h.mw.JwtMiddleware(), h.UpdateThread)
+	tr.GET("/revision/:thread_id", h.mw.JwtMiddleware(), h.GetThreadRevisions)
+	tr.POST("/subscribe/:thread_id", h.mw.JwtMiddleware(), h.SubscribeThread)
+	tr.PATCH("/unsubscribe/:thread_id", h.mw.JwtMiddleware(), h.UnSubscribeThread)
+	tr.GET("/stream/:thread_id", h.mw.JwtMiddleware(), h.StreamThreadEvents)
+	tr.PATCH("/like/:thread_id", h.mw.JwtMiddleware(), h.LikeThread)
+	tr.PATCH("/dislike/:thread_id", h.mw.JwtMiddleware(), h.DislikeThread)
+	tr.PATCH("/pin/:thread_id", h.mw.JwtMiddleware(), h.PinThread)
+	tr.PATCH("/unpin/:thread_id", h.mw.JwtMiddleware(), h.UnpinThread)
+	tr.PATCH("/lock/:thread_id", h.mw.JwtMiddleware(), h.LockThread)
+	tr.PATCH("/unlock/:thread_id", h.mw.JwtMiddleware(), h.UnlockThread)
+	tr.PATCH("/archive/:thread_id", h.mw.JwtMiddleware(), h.ArchiveThread)
+	tr.PATCH("/unarchive/:thread_id", h.mw.JwtMiddleware(), h.UnarchiveThread)
+	tr.POST("/:thread_id/poll", h.mw.JwtMiddleware(), h.VoteThreadPoll)
+	tr.DELETE("/:thread_id/poll", h.mw.JwtMiddleware(), h.UnVoteThreadPoll)
+	tr.GET("/comment/:thread_id", h.mw.JwtMiddleware(), h.GetThreadComments)
+	tr.POST("/comment/:thread_id", h.mw.JwtMiddleware(), writeRateLimit, h.CommentThread)
+	tr.DELETE("/comment/:comment_id", h.mw.JwtMiddleware(), h.DeleteThreadComment)
+	tr.PATCH("/comment/:comment_id", h.mw.JwtMiddleware(), h.UpdateThreadComment)
+	tr.GET("/comment/revision/:comment_id", h.mw.JwtMiddleware(), h.GetThreadCommentRevisions)
+	tr.GET("/comment/reply/:comment_id", h.mw.JwtMiddleware(), h.GetThreadCommentReplies)
+	tr.POST("/comment/reply/:comment_id", h.mw.JwtMiddleware(), writeRateLimit, h.ReplyComment)
+	tr.DELETE("/comment/reply/:comment_id", h.mw.JwtMiddleware(), h.DeleteThreadCommentReply)
+	tr.PATCH("/comment/reply/:comment_id", h.mw.JwtMiddleware(), h.UpdateThreadCommentReply)
+	tr.GET("/comment/reply/revision/:comment_id", h.mw.JwtMiddleware(), h.GetThreadCommentReplyRevisions)
+	tr.PATCH("/comment/like/:comment_id", h.mw.JwtMiddleware(), h.LikeComment)
+	tr.PATCH("/comment/dislike/:comment_id", h.mw.JwtMiddleware(), h.DislikeComment)
 }
 
 func (h *ThreadController) GetThreadList(c *gin.Context) {
@@ -81,6 +104,12 @@ func (h *ThreadController) GetThreadList(c *gin.Context) {
 		return
 	}
 
+	isFeed, err := pkg.GetBoolQueryParams(c, "is_feed")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
 	includeUserActivity, err := pkg.GetBoolQueryParams(c, "include_user_activity")
 	if err != nil {
 		httpresp.HttpRespError(c, err)
@@ -90,7 +119,9 @@ func (h *ThreadController) GetThreadList(c *gin.Context) {
 	data.Limit = limit
 	data.IsTrending = isTrending
 	data.IsUserFollowing = isUserFollowing
+	data.IsFeed = isFeed
 	data.Cursor = c.Query("cursor")
+	data.SubThreadID = c.Query("subthread_id")
 	data.UserIDParam = c.Query("user_id")
 	data.Search = c.Query("_q")
 	data.IncludeUserActivity = includeUserActivity
@@ -258,6 +289,249 @@ func (h *ThreadController) LikeThread(c *gin.Context) {
 	return
 }
 
+func (h *ThreadController) PinThread(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.PinThread", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.PinThreadReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.threadSvc.PinThread(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[PinThread] Failed to pin thread", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ThreadController) UnpinThread(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.UnpinThread", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.PinThreadReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.threadSvc.UnpinThread(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UnpinThread] Failed to unpin thread", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ThreadController) LockThread(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.LockThread", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.LockThreadReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.threadSvc.LockThread(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[LockThread] Failed to lock thread", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ThreadController) UnlockThread(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.UnlockThread", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.LockThreadReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.threadSvc.UnlockThread(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UnlockThread] Failed to unlock thread", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ThreadController) ArchiveThread(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.ArchiveThread", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.ArchiveThreadReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.threadSvc.ArchiveThread(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[ArchiveThread] Failed to archive thread", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ThreadController) UnarchiveThread(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.UnarchiveThread", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.ArchiveThreadReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.threadSvc.UnarchiveThread(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UnarchiveThread] Failed to unarchive thread", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ThreadController) GetThreadRevisions(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.GetThreadRevisions", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetThreadRevisionsReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	resp, err := h.threadSvc.GetThreadRevisions(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetThreadRevisions] Failed to get thread revisions", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *ThreadController) GetThreadCommentRevisions(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.GetThreadCommentRevisions", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetThreadCommentRevisionsReq
+
+	data.CommentID = c.Param("comment_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	resp, err := h.threadSvc.GetThreadCommentRevisions(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetThreadCommentRevisions] Failed to get thread comment revisions", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *ThreadController) GetThreadCommentReplyRevisions(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.GetThreadCommentReplyRevisions", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetThreadCommentRevisionsReq
+
+	data.CommentID = c.Param("comment_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	resp, err := h.threadSvc.GetThreadCommentReplyRevisions(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetThreadCommentReplyRevisions] Failed to get thread comment reply revisions", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
 func (h *ThreadController) DislikeThread(c *gin.Context) {
 	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.DislikeThread", "controller")
 	//defer endFunc()
@@ -298,7 +572,16 @@ func (h *ThreadController) GetThreadComments(c *gin.Context) {
 
 	var data request.GetThreadCommentsReq
 
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
 	data.ThreadID = c.Param("thread_id")
+	data.SortBy = c.Query("sort_by")
+	data.Limit = limit
+	data.Cursor = c.Query("cursor")
 	data.UserID = claims.ID
 	data.UserEmail = claims.Email
 	data.Username = claims.UserName
@@ -314,6 +597,44 @@ func (h *ThreadController) GetThreadComments(c *gin.Context) {
 	return
 }
 
+func (h *ThreadController) GetThreadCommentReplies(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.GetThreadCommentReplies", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.GetThreadCommentRepliesReq
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data.CommentID = c.Param("comment_id")
+	data.ParentReplyID = c.Query("parent_reply_id")
+	data.SortBy = c.Query("sort_by")
+	data.Limit = limit
+	data.Cursor = c.Query("cursor")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+	data.Username = claims.UserName
+
+	resp, err := h.threadSvc.GetThreadCommentReplies(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetThreadCommentReplies] Failed to get thread comment replies", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
 func (h *ThreadController) CommentThread(c *gin.Context) {
 	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.CommentThread", "controller")
 	//defer endFunc()
@@ -600,6 +921,120 @@ func (h *ThreadController) SubscribeThread(c *gin.Context) {
 	return
 }
 
+func (h *ThreadController) VoteThreadPoll(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.VoteThreadPoll", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.VoteThreadPollReq
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[VoteThreadPoll] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+	data.Username = claims.UserName
+
+	err := h.threadSvc.VoteThreadPoll(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[VoteThreadPoll] Failed to vote thread poll", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *ThreadController) UnVoteThreadPoll(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.UnVoteThreadPoll", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.UnVoteThreadPollReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+	data.Username = claims.UserName
+
+	err := h.threadSvc.UnVoteThreadPoll(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UnVoteThreadPoll] Failed to unvote thread poll", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+// StreamThreadEvents pushes the events of a thread as server-sent events until the client disconnects
+func (h *ThreadController) StreamThreadEvents(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.StreamThreadEvents", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.SubscribeThreadEventsReq
+
+	data.ThreadID = c.Param("thread_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+	data.Username = claims.UserName
+
+	events, unsubscribe, err := h.threadSvc.SubscribeThreadEvents(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[StreamThreadEvents] Failed to subscribe thread events", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+
+			c.SSEvent("thread_event", string(event))
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", "")
+			return true
+		}
+	})
+}
+
 func (h *ThreadController) UnSubscribeThread(c *gin.Context) {
 	//_, endFunc := trace.Start(c.Copy().Request.Context(), "ThreadController.UnSubscribeThread", "controller")
 	//defer endFunc()
@@ -17,13 +17,15 @@ import (
 
 type UniversityController struct {
 	cfg           config.Config
+	mw            *middleware.Middleware
 	universitySvc service.UniversityService
 }
 
-func NewUniversityController(cfg config.Config, universitySvc service.UniversityService) *UniversityController {
+func NewUniversityController(cfg config.Config, mw *middleware.Middleware, universitySvc service.UniversityService) *UniversityController {
 
 	return &UniversityController{
 		cfg:           cfg,
+		mw:            mw,
 		universitySvc: universitySvc,
 	}
 }
@@ -31,10 +33,10 @@ func NewUniversityController(cfg config.Config, universitySvc service.University
 func (h *UniversityController) AddRoutes(r *gin.Engine) {
 	ur := r.Group("/api/v1/university")
 
-	ur.GET("/ratings", middleware.JwtMiddleware(h.cfg), h.GetUniversityRatingList)
-	ur.GET("/rating/:rating_id", middleware.JwtMiddleware(h.cfg), h.GetUniversityRatingDetail)
-	ur.POST("/rate/:university_id", middleware.JwtMiddleware(h.cfg), h.RateUniversity)
-	ur.PATCH("/rating/:rating_id", middleware.JwtMiddleware(h.cfg), h.UpdateUniversityRating)
+	ur.GET("/ratings", h.mw.JwtMiddleware(), h.GetUniversityRatingList)
+	ur.GET("/rating/:rating_id", h.mw.JwtMiddleware(), h.GetUniversityRatingDetail)
+	ur.POST("/rate/:university_id", h.mw.JwtMiddleware(), h.RateUniversity)
+	ur.PATCH("/rating/:rating_id", h.mw.JwtMiddleware(), h.UpdateUniversityRating)
 }
 
 func (h *UniversityController) GetUniversityRatingList(c *gin.Context) {
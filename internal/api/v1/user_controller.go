@@ -2,10 +2,12 @@ package v1
 
 import (
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/middleware"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
 	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
 	"github.com/gin-gonic/gin"
@@ -16,13 +18,15 @@ import (
 
 type UserController struct {
 	cfg     config.Config
+	mw      *middleware.Middleware
 	userSvc service.UserService
 }
 
-func NewUserController(cfg config.Config, userSvc service.UserService) *UserController {
+func NewUserController(cfg config.Config, mw *middleware.Middleware, userSvc service.UserService) *UserController {
 
 	return &UserController{
 		cfg:     cfg,
+		mw:      mw,
 		userSvc: userSvc,
 	}
 }
@@ -30,12 +34,21 @@ func NewUserController(cfg config.Config, userSvc service.UserService) *UserCont
 func (h *UserController) AddRoutes(r *gin.Engine) {
 	ur := r.Group("/api/v1/user")
 
-	ur.GET("/profile", middleware.JwtMiddleware(h.cfg), h.GetUserProfile)
-	ur.GET("/device", middleware.JwtMiddleware(h.cfg), h.GetUserDevices)
-	ur.POST("/device/:user_id", middleware.JwtMiddleware(h.cfg), h.CreateUserDevice)
-	ur.PATCH("/ban/:user_id", middleware.JwtMiddleware(h.cfg), middleware.IsAdminMiddleware(h.cfg), h.BanUser)
-	ur.PATCH("/unban/:user_id", middleware.JwtMiddleware(h.cfg), middleware.IsAdminMiddleware(h.cfg), h.UnBanUser)
+	ur.GET("/profile", h.mw.JwtMiddleware(), h.GetUserProfile)
+	ur.GET("/device", h.mw.JwtMiddleware(), h.GetUserDevices)
+	ur.POST("/device/:user_id", h.mw.JwtMiddleware(), h.CreateUserDevice)
+	ur.PATCH("/ban/:user_id", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_BAN_USER), h.BanUser)
+	ur.PATCH("/unban/:user_id", h.mw.JwtMiddleware(), h.mw.PermissionMiddleware(constants.PERMISSION_BAN_USER), h.UnBanUser)
+	ur.PATCH("/profile", h.mw.JwtMiddleware(), h.UpdateUserProfile)
+	ur.GET("/block", h.mw.JwtMiddleware(), h.GetUserBlockList)
+	ur.POST("/block", h.mw.JwtMiddleware(), h.BlockUser)
+	ur.DELETE("/block/:user_id", h.mw.JwtMiddleware(), h.UnblockUser)
+	ur.POST("/follow/:user_id", h.mw.JwtMiddleware(), h.FollowUser)
+	ur.PATCH("/unfollow/:user_id", h.mw.JwtMiddleware(), h.UnFollowUser)
 	ur.GET("/test", h.TestLog)
+	ur.GET("/:username", h.mw.OptionalJwtMiddleware(), h.GetPublicUserProfile)
+	ur.GET("/:username/followers", h.mw.OptionalJwtMiddleware(), h.GetUserFollowers)
+	ur.GET("/:username/following", h.mw.OptionalJwtMiddleware(), h.GetUserFollowing)
 }
 
 func (h *UserController) GetUserProfile(c *gin.Context) {
@@ -63,6 +76,60 @@ func (h *UserController) GetUserProfile(c *gin.Context) {
 	return
 }
 
+func (h *UserController) UpdateUserProfile(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.UpdateUserProfile", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.UpdateUserProfileReq
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UpdateUserProfile] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	user, err := h.userSvc.UpdateUserProfile(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UpdateUserProfile] Failed to update user profile", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, user, nil)
+	return
+}
+
+func (h *UserController) GetPublicUserProfile(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.GetPublicUserProfile", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+
+	var data request.GetPublicUserProfileReq
+
+	data.Username = c.Param("username")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	profile, err := h.userSvc.GetPublicUserProfile(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetPublicUserProfile] Failed to get public user profile", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, profile, nil)
+	return
+}
+
 func (h *UserController) CreateUserDevice(c *gin.Context) {
 	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.CreateUserDevice", "controller")
 	//defer endFunc()
@@ -175,6 +242,216 @@ func (h *UserController) UnBanUser(c *gin.Context) {
 	return
 }
 
+func (h *UserController) GetUserBlockList(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.GetUserBlockList", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data := request.GetUserBlockListReq{
+		Type:      c.Query("type"),
+		Limit:     limit,
+		Cursor:    c.Query("cursor"),
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	resp, err := h.userSvc.GetUserBlockList(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetUserBlockList] Failed to get user block list", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *UserController) BlockUser(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.BlockUser", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.BlockUserReq
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[BlockUser] Failed to bind json", zap.Error(err))
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.userSvc.BlockUser(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[BlockUser] Failed to block user", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *UserController) UnblockUser(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.UnblockUser", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.UnblockUserReq
+
+	data.BlockedUserID = c.Param("user_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.userSvc.UnblockUser(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UnblockUser] Failed to unblock user", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *UserController) FollowUser(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.FollowUser", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.FollowUserReq
+
+	data.FollowedUserID = c.Param("user_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.userSvc.FollowUser(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[FollowUser] Failed to follow user", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *UserController) UnFollowUser(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.UnFollowUser", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+	if len(claims.Token) == 0 {
+		httpresp.HttpRespError(c, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+		return
+	}
+
+	var data request.UnFollowUserReq
+
+	data.FollowedUserID = c.Param("user_id")
+	data.UserID = claims.ID
+	data.UserEmail = claims.Email
+
+	err := h.userSvc.UnFollowUser(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[UnFollowUser] Failed to unfollow user", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, nil, nil)
+	return
+}
+
+func (h *UserController) GetUserFollowers(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.GetUserFollowers", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data := request.GetUserFollowListReq{
+		Username:  c.Param("username"),
+		Limit:     limit,
+		Cursor:    c.Query("cursor"),
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	resp, err := h.userSvc.GetUserFollowers(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetUserFollowers] Failed to get user followers", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
+func (h *UserController) GetUserFollowing(c *gin.Context) {
+	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.GetUserFollowing", "controller")
+	//defer endFunc()
+
+	claims := middleware.ParseToken(c)
+
+	limit, err := pkg.GetIntQueryParams(c, 10, "limit")
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	data := request.GetUserFollowListReq{
+		Username:  c.Param("username"),
+		Limit:     limit,
+		Cursor:    c.Query("cursor"),
+		UserID:    claims.ID,
+		UserEmail: claims.Email,
+	}
+
+	resp, err := h.userSvc.GetUserFollowing(c.Request.Context(), data)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(c.Request.Context(), "[GetUserFollowing] Failed to get user following", zap.Error(err))
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, resp, nil)
+	return
+}
+
 func (h *UserController) TestLog(c *gin.Context) {
 	//_, endFunc := trace.Start(c.Copy().Request.Context(), "UserController.TestLog", "controller")
 	//defer endFunc()
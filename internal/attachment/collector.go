@@ -0,0 +1,160 @@
+package attachment
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/worker"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Collector deletes the uploads that were never attached to a thread or comment and the direct uploads
+// that were never confirmed
+type Collector struct {
+	cfg             config.Config
+	attachmentRepo  repository.AttachmentRepository
+	imageUploadRepo repository.ImageUploadRepository
+	fileRepo        repository.FileRepository
+	db              *bun.DB
+}
+
+// NewCollector returns the worker running the collector. When it is shut down the in-flight deletes are
+// cancelled, the remaining uploads are collected on the next run
+func NewCollector(cfg config.Config, attachmentRepo repository.AttachmentRepository, imageUploadRepo repository.ImageUploadRepository, fileRepo repository.FileRepository, db *bun.DB) *worker.Worker {
+
+	c := &Collector{
+		cfg:             cfg,
+		attachmentRepo:  attachmentRepo,
+		imageUploadRepo: imageUploadRepo,
+		fileRepo:        fileRepo,
+		db:              db,
+	}
+
+	return worker.New("attachment collector", time.Duration(cfg.GetAttachmentCfg().GCIntervalMins)*time.Minute, c.run)
+}
+
+func (c *Collector) run(ctx context.Context, stopping <-chan struct{}) {
+	c.collect(ctx, stopping)
+	c.collectImageUploads(ctx, stopping)
+}
+
+func (c *Collector) collect(ctx context.Context, stopping <-chan struct{}) {
+	attachmentCfg := c.cfg.GetAttachmentCfg()
+	createdBefore := time.Now().Add(-time.Duration(attachmentCfg.OrphanTTLMins) * time.Minute)
+
+	attachments, err := c.attachmentRepo.GetUnattachedCreatedBefore(createdBefore, attachmentCfg.GCBatchSize)
+	if err != nil {
+		c.cfg.Logger().ErrorWithContext(ctx, "[Collector.collect] Failed to get unattached attachments", zap.Error(err))
+		return
+	}
+
+	for _, a := range attachments {
+		if worker.IsStopping(stopping) {
+			return
+		}
+
+		// The row is deleted first so an upload attached in the meantime is never removed from the storage
+		isDeleted, err := c.attachmentRepo.DeleteUnattachedByID(a.ID)
+		if err != nil {
+			c.cfg.Logger().ErrorWithContext(ctx, "[Collector.collect] Failed to delete unattached attachment", zap.String("attachment_id", a.ID), zap.Error(err))
+			continue
+		}
+
+		if !isDeleted {
+			continue
+		}
+
+		c.deleteFiles(ctx, a)
+	}
+
+	if len(attachments) > 0 {
+		c.cfg.Logger().Info("[Collector.collect] Collected unattached attachments", zap.Int("count", len(attachments)))
+	}
+}
+
+// collectImageUploads deletes the pending direct uploads whose presigned url expired longer than the orphan ttl ago
+func (c *Collector) collectImageUploads(ctx context.Context, stopping <-chan struct{}) {
+	attachmentCfg := c.cfg.GetAttachmentCfg()
+	expiredBefore := time.Now().Add(-time.Duration(attachmentCfg.OrphanTTLMins) * time.Minute)
+
+	imageUploads, err := c.imageUploadRepo.GetPendingExpiredBefore(expiredBefore, attachmentCfg.GCBatchSize)
+	if err != nil {
+		c.cfg.Logger().ErrorWithContext(ctx, "[Collector.collectImageUploads] Failed to get expired image uploads", zap.Error(err))
+		return
+	}
+
+	for _, u := range imageUploads {
+		if worker.IsStopping(stopping) {
+			return
+		}
+
+		isDeleted, err := c.imageUploadRepo.DeletePendingByID(u.ID)
+		if err != nil {
+			c.cfg.Logger().ErrorWithContext(ctx, "[Collector.collectImageUploads] Failed to delete expired image upload", zap.String("upload_id", u.ID), zap.Error(err))
+			continue
+		}
+
+		if !isDeleted {
+			continue
+		}
+
+		err = c.fileRepo.Delete(ctx, u.Bucket, u.FileKey)
+		if err != nil {
+			c.cfg.Logger().ErrorWithContext(ctx, "[Collector.collectImageUploads] Failed to delete expired upload from storage", zap.String("upload_id", u.ID), zap.String("file_key", u.FileKey), zap.Error(err))
+		}
+	}
+
+	if len(imageUploads) > 0 {
+		c.cfg.Logger().Info("[Collector.collectImageUploads] Collected expired image uploads", zap.Int("count", len(imageUploads)))
+	}
+}
+
+// deleteFiles removes the stored variants of the attachment. Keys are content addressed, so the files are kept
+// while another attachment still references the same image. The content hash stays locked until the files are
+// deleted so a concurrent upload of the same image cannot store its files in between
+func (c *Collector) deleteFiles(ctx context.Context, a model.Attachment) {
+
+	if a.ContentHash != nil {
+		tx, err := c.db.Begin()
+		if err != nil {
+			c.cfg.Logger().ErrorWithContext(ctx, "[Collector.deleteFiles] Failed to begin transaction", zap.String("attachment_id", a.ID), zap.Error(err))
+			return
+		}
+
+		// The transaction only holds the lock, it is released once the files are deleted
+		defer tx.Rollback()
+
+		err = c.attachmentRepo.LockContentHashTx(*a.ContentHash, tx)
+		if err != nil {
+			c.cfg.Logger().ErrorWithContext(ctx, "[Collector.deleteFiles] Failed to lock content hash", zap.String("attachment_id", a.ID), zap.Error(err))
+			return
+		}
+
+		count, err := c.attachmentRepo.CountByContentHashTx(*a.ContentHash, tx)
+		if err != nil {
+			c.cfg.Logger().ErrorWithContext(ctx, "[Collector.deleteFiles] Failed to count attachments by content hash", zap.String("attachment_id", a.ID), zap.Error(err))
+			return
+		}
+
+		if count > 0 {
+			return
+		}
+	}
+
+	fileKeys := []string{a.FileKey}
+	for _, v := range a.Variants {
+		if v.FileKey != a.FileKey {
+			fileKeys = append(fileKeys, v.FileKey)
+		}
+	}
+
+	for _, fileKey := range fileKeys {
+		err := c.fileRepo.Delete(ctx, a.Bucket, fileKey)
+		if err != nil {
+			c.cfg.Logger().ErrorWithContext(ctx, "[Collector.deleteFiles] Failed to delete unattached file from storage", zap.String("attachment_id", a.ID), zap.String("file_key", fileKey), zap.Error(err))
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/andibalo/meowhasiswa-be/pkg/logger"
 	"github.com/andibalo/meowhasiswa-be/pkg/trace"
 	"github.com/spf13/viper"
+	"strconv"
+	"strings"
 )
 
 const (
@@ -28,6 +30,7 @@ type Config interface {
 
 	HttpExternalServiceTimeout() int64
 	HttpMaxUploadSizeMB() int
+	HttpTrustedProxies() []string
 
 	GetNotifSvcCfg() NotifSvc
 
@@ -36,20 +39,34 @@ type Config interface {
 	GetAWSCfg() AWS
 	GetBrevoSvcCfg() BrevoSvc
 	GetMailerCfg() Mailer
+	GetOutboxCfg() Outbox
+	GetRateLimitCfg() RateLimit
+	GetAttachmentCfg() Attachment
+	GetImageCfg() Image
+	GetStorageCfg() Storage
+	GetLeaderboardCfg() Leaderboard
+	GetThreadArchiveCfg() ThreadArchive
 }
 
 type AppConfig struct {
-	logger   logger.Logger
-	App      app
-	Db       db
-	Tracer   tracer
-	Http     http
-	NotifSvc NotifSvc
-	Flag     Flag
-	Auth     Auth
-	Aws      AWS
-	BrevoSvc BrevoSvc
-	Mailer   Mailer
+	logger        logger.Logger
+	App           app
+	Db            db
+	Tracer        tracer
+	Http          http
+	NotifSvc      NotifSvc
+	Flag          Flag
+	Auth          Auth
+	Aws           AWS
+	BrevoSvc      BrevoSvc
+	Mailer        Mailer
+	Outbox        Outbox
+	RateLimit     RateLimit
+	Attachment    Attachment
+	Image         Image
+	Storage       Storage
+	Leaderboard   Leaderboard
+	ThreadArchive ThreadArchive
 }
 
 type app struct {
@@ -87,6 +104,8 @@ type NotifSvc struct {
 type http struct {
 	ServiceExternalTimeout int64
 	MaxUploadSizeMB        int
+	// TrustedProxies are the proxies allowed to set the client IP through X-Forwarded-For, none by default
+	TrustedProxies []string
 }
 
 type Flag struct {
@@ -98,7 +117,10 @@ type Flag struct {
 type Auth struct {
 	UserSecretCodeExpiryMins int
 	JWTSecret                string
-	JWTStaticToken           string
+	AccessTokenExpiryMins    int
+	RefreshTokenExpiryDays   int
+	LoginMaxFailedAttempts   int
+	LoginLockoutMins         int
 }
 
 type AWS struct {
@@ -123,6 +145,75 @@ type Mailer struct {
 	DefaultSenderEmail string
 }
 
+// Outbox configures the dispatcher, a follower fan out is expanded FanOutPageSize followers at a time and its push
+// notifications are queued PushBatchSize tokens per message
+type Outbox struct {
+	PollIntervalSecs int
+	BatchSize        int
+	MaxAttempts      int
+	BaseBackoffSecs  int
+	MaxBackoffSecs   int
+	FanOutPageSize   int
+	PushBatchSize    int
+}
+
+// Attachment configures the collection of uploads that were never attached to a thread or comment
+type Attachment struct {
+	OrphanTTLMins  int
+	GCIntervalMins int
+	GCBatchSize    int
+}
+
+// Leaderboard configures how often the leaderboard scores are recomputed from the vote activity
+type Leaderboard struct {
+	RefreshIntervalMins int
+}
+
+// ThreadArchive configures after how many days without a new comment or reply a thread is archived
+type ThreadArchive struct {
+	InactiveDays int
+	IntervalMins int
+}
+
+// Storage selects the file storage backend. The local and memory drivers serve their files from the app itself,
+// so PublicBaseURL must point to this service for them and SigningSecret, distinct from the JWT secret, signs their URLs
+type Storage struct {
+	Driver            string
+	DefaultBucket     string
+	LocalDir          string
+	PublicBaseURL     string
+	SigningSecret     string
+	PresignExpiryMins int
+	// DirectUploadMaxSizeMB bounds the presigned uploads, they do not go through HttpMaxUploadSizeMB
+	DirectUploadMaxSizeMB int
+}
+
+// Image bounds the accepted uploads and configures the processed variants
+type Image struct {
+	MinWidthPx       int
+	MinHeightPx      int
+	MaxWidthPx       int
+	MaxHeightPx      int
+	MaxMegaPixels    int
+	JPEGQuality      int
+	ThumbnailSizesPx []int
+}
+
+type RateLimit struct {
+	Store        string
+	Auth         RateLimitRule
+	ThreadWrite  RateLimitRule
+	TokenRefresh RateLimitRule
+}
+
+// RateLimitRule is a token bucket per client IP and per user, a zero rate disables the bucket
+type RateLimitRule struct {
+	IPRequestsPerMin   int
+	IPBurst            int
+	UserRequestsPerMin int
+	UserBurst          int
+}
+
 func InitConfig() *AppConfig {
 	viper.SetConfigType("env")
 	viper.SetConfigName(".env") // name of Config file (without extension)
@@ -180,6 +271,7 @@ func InitConfig() *AppConfig {
 		},
 		Http: http{
 			MaxUploadSizeMB: viper.GetInt("MAX_UPLOAD_SIZE_MB"),
+			TrustedProxies:  getStringSliceOrDefault("TRUSTED_PROXIES", nil),
 		},
 		Tracer: tracer{
 			ServiceName:          ServiceName,
@@ -200,7 +292,10 @@ func InitConfig() *AppConfig {
 		Auth: Auth{
 			UserSecretCodeExpiryMins: viper.GetInt("USER_SECRET_CODE_EXPIRY_MINS"),
 			JWTSecret:                viper.GetString("JWT_SECRET"),
-			JWTStaticToken:           viper.GetString("JWT_STATIC_TOKEN"),
+			AccessTokenExpiryMins:    getIntOrDefault("ACCESS_TOKEN_EXPIRY_MINS", 15),
+			RefreshTokenExpiryDays:   getIntOrDefault("REFRESH_TOKEN_EXPIRY_DAYS", 30),
+			LoginMaxFailedAttempts:   getIntOrDefault("LOGIN_MAX_FAILED_ATTEMPTS", 5),
+			LoginLockoutMins:         getIntOrDefault("LOGIN_LOCKOUT_MINS", 15),
 		},
 		Aws: AWS{
 			Region:            viper.GetString("AWS_REGION"),
@@ -219,6 +314,62 @@ func InitConfig() *AppConfig {
 			DefaultSenderName:  viper.GetString("DEFAULT_SENDER_NAME"),
 			DefaultSenderEmail: viper.GetString("DEFAULT_SENDER_EMAIL"),
 		},
+		Outbox: Outbox{
+			PollIntervalSecs: getIntOrDefault("OUTBOX_POLL_INTERVAL_SECS", 2),
+			BatchSize:        getIntOrDefault("OUTBOX_BATCH_SIZE", 50),
+			MaxAttempts:      getIntOrDefault("OUTBOX_MAX_ATTEMPTS", 8),
+			BaseBackoffSecs:  getIntOrDefault("OUTBOX_BASE_BACKOFF_SECS", 5),
+			MaxBackoffSecs:   getIntOrDefault("OUTBOX_MAX_BACKOFF_SECS", 3600),
+			FanOutPageSize:   getIntOrDefault("OUTBOX_FAN_OUT_PAGE_SIZE", 500),
+			PushBatchSize:    getIntOrDefault("OUTBOX_PUSH_BATCH_SIZE", 500),
+		},
+		Attachment: Attachment{
+			OrphanTTLMins:  getIntOrDefault("ATTACHMENT_ORPHAN_TTL_MINS", 1440),
+			GCIntervalMins: getIntOrDefault("ATTACHMENT_GC_INTERVAL_MINS", 60),
+			GCBatchSize:    getIntOrDefault("ATTACHMENT_GC_BATCH_SIZE", 100),
+		},
+		Leaderboard: Leaderboard{
+			RefreshIntervalMins: getIntOrDefault("LEADERBOARD_REFRESH_INTERVAL_MINS", 10),
+		},
+		ThreadArchive: ThreadArchive{
+			InactiveDays: getIntOrDefault("THREAD_ARCHIVE_INACTIVE_DAYS", 180),
+			IntervalMins: getIntOrDefault("THREAD_ARCHIVE_INTERVAL_MINS", 60),
+		},
+		Storage: Storage{
+			Driver:                getStringOrDefault("STORAGE_DRIVER", "s3"),
+			DefaultBucket:         getStringOrDefault("STORAGE_DEFAULT_BUCKET", viper.GetString("AWS_S3_DEFAULT_BUCKET")),
+			LocalDir:              getStringOrDefault("STORAGE_LOCAL_DIR", "storage"),
+			PublicBaseURL:         getStringOrDefault("STORAGE_PUBLIC_BASE_URL", viper.GetString("APP_URL")),
+			SigningSecret:         viper.GetString("STORAGE_SIGNING_SECRET"),
+			PresignExpiryMins:     getIntOrDefault("STORAGE_PRESIGN_EXPIRY_MINS", 15),
+			DirectUploadMaxSizeMB: getIntOrDefault("STORAGE_DIRECT_UPLOAD_MAX_SIZE_MB", 10),
+		},
+		Image: Image{
+			MinWidthPx:       getIntOrDefault("IMAGE_MIN_WIDTH_PX", 16),
+			MinHeightPx:      getIntOrDefault("IMAGE_MIN_HEIGHT_PX", 16),
+			MaxWidthPx:       getIntOrDefault("IMAGE_MAX_WIDTH_PX", 8000),
+			MaxHeightPx:      getIntOrDefault("IMAGE_MAX_HEIGHT_PX", 8000),
+			MaxMegaPixels:    getIntOrDefault("IMAGE_MAX_MEGA_PIXELS", 25),
+			JPEGQuality:      getIntOrDefault("IMAGE_JPEG_QUALITY", 85),
+			ThumbnailSizesPx: getIntSliceOrDefault("IMAGE_THUMBNAIL_SIZES_PX", []int{128, 512, 1024}),
+		},
+		RateLimit: RateLimit{
+			Store: getStringOrDefault("RATE_LIMIT_STORE", "memory"),
+			Auth: RateLimitRule{
+				IPRequestsPerMin: getIntOrDefault("RATE_LIMIT_AUTH_IP_REQUESTS_PER_MIN", 10),
+				IPBurst:          getIntOrDefault("RATE_LIMIT_AUTH_IP_BURST", 5),
+			},
+			ThreadWrite: RateLimitRule{
+				IPRequestsPerMin:   getIntOrDefault("RATE_LIMIT_THREAD_WRITE_IP_REQUESTS_PER_MIN", 60),
+				IPBurst:            getIntOrDefault("RATE_LIMIT_THREAD_WRITE_IP_BURST", 20),
+				UserRequestsPerMin: getIntOrDefault("RATE_LIMIT_THREAD_WRITE_USER_REQUESTS_PER_MIN", 10),
+				UserBurst:          getIntOrDefault("RATE_LIMIT_THREAD_WRITE_USER_BURST", 5),
+			},
+			TokenRefresh: RateLimitRule{
+				IPRequestsPerMin: getIntOrDefault("RATE_LIMIT_TOKEN_REFRESH_IP_REQUESTS_PER_MIN", 30),
+				IPBurst:          getIntOrDefault("RATE_LIMIT_TOKEN_REFRESH_IP_BURST", 10),
+			},
+		},
 	}
 }
 
@@ -230,6 +381,59 @@ func getRequiredString(key string) string {
 	panic(fmt.Errorf("KEY %s IS MISSING", key))
 }
 
+func getIntOrDefault(key string, defaultValue int) int {
+	if viper.IsSet(key) && viper.GetInt(key) > 0 {
+		return viper.GetInt(key)
+	}
+
+	return defaultValue
+}
+
+// getIntSliceOrDefault parses a comma separated list of positive ints such as "128,512,1024"
+func getIntSliceOrDefault(key string, defaultValue []int) []int {
+	if !viper.IsSet(key) || viper.GetString(key) == "" {
+		return defaultValue
+	}
+
+	var values []int
+
+	for _, s := range strings.Split(viper.GetString(key), ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || v <= 0 {
+			return defaultValue
+		}
+
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// getStringSliceOrDefault parses a comma separated list such as "10.0.0.0/8,127.0.0.1"
+func getStringSliceOrDefault(key string, defaultValue []string) []string {
+	if !viper.IsSet(key) || viper.GetString(key) == "" {
+		return defaultValue
+	}
+
+	var values []string
+
+	for _, s := range strings.Split(viper.GetString(key), ",") {
+		if v := strings.TrimSpace(s); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func getStringOrDefault(key string, defaultValue string) string {
+	if viper.IsSet(key) && viper.GetString(key) != "" {
+		return viper.GetString(key)
+	}
+
+	return defaultValue
+}
+
 func (c *AppConfig) Logger() logger.Logger {
 	return c.logger
 }
@@ -272,6 +476,11 @@ func (c *AppConfig) HttpExternalServiceTimeout() int64 {
 	return c.Http.ServiceExternalTimeout
 }
 
+func (c *AppConfig) HttpTrustedProxies() []string {
+
+	return c.Http.TrustedProxies
+}
+
 func (c *AppConfig) HttpMaxUploadSizeMB() int {
 
 	return c.Http.MaxUploadSizeMB
@@ -300,3 +509,31 @@ func (c *AppConfig) GetBrevoSvcCfg() BrevoSvc {
 func (c *AppConfig) GetMailerCfg() Mailer {
 	return c.Mailer
 }
+
+func (c *AppConfig) GetOutboxCfg() Outbox {
+	return c.Outbox
+}
+
+func (c *AppConfig) GetRateLimitCfg() RateLimit {
+	return c.RateLimit
+}
+
+func (c *AppConfig) GetAttachmentCfg() Attachment {
+	return c.Attachment
+}
+
+func (c *AppConfig) GetImageCfg() Image {
+	return c.Image
+}
+
+func (c *AppConfig) GetLeaderboardCfg() Leaderboard {
+	return c.Leaderboard
+}
+
+func (c *AppConfig) GetThreadArchiveCfg() ThreadArchive {
+	return c.ThreadArchive
+}
+
+func (c *AppConfig) GetStorageCfg() Storage {
+	return c.Storage
+}
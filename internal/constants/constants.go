@@ -1,10 +1,28 @@
 package constants
 
 const (
-	EMAIL_ADMIN_MEOWHASISWA = "meowhasiswa.admin@meowhasiswa.com"
+	USER_ROLE                      = "USER"
+	ADMIN_ROLE                     = "ADMIN"
+	MODERATOR_ROLE                 = "MODERATOR"
+	SUBTHREAD_MODERATOR_ROLE       = "SUBTHREAD_MODERATOR"
+	SUBTHREAD_OWNER_ROLE           = "SUBTHREAD_OWNER"
+	UNIVERSITY_REPRESENTATIVE_ROLE = "UNIVERSITY_REPRESENTATIVE"
+)
+
+// rbac
+const (
+	PERMISSION_MANAGE_ROLE                = "MANAGE_ROLE"
+	PERMISSION_BAN_USER                   = "BAN_USER"
+	PERMISSION_MODERATE_CONTENT           = "MODERATE_CONTENT"
+	PERMISSION_MANAGE_SUBTHREAD           = "MANAGE_SUBTHREAD"
+	PERMISSION_MANAGE_UNIVERSITY          = "MANAGE_UNIVERSITY"
+	PERMISSION_MANAGE_REPUTATION          = "MANAGE_REPUTATION"
+	PERMISSION_DELETE_SUBTHREAD           = "DELETE_SUBTHREAD"
+	PERMISSION_MANAGE_SUBTHREAD_MODERATOR = "MANAGE_SUBTHREAD_MODERATOR"
+	PERMISSION_ACCESS_ALL_UNIVERSITIES    = "ACCESS_ALL_UNIVERSITIES"
 
-	USER_ROLE  = "USER"
-	ADMIN_ROLE = "ADMIN"
+	SCOPE_TYPE_SUBTHREAD  = "SUBTHREAD"
+	SCOPE_TYPE_UNIVERSITY = "UNIVERSITY"
 )
 
 const (
@@ -15,11 +33,43 @@ const (
 
 	UNI_RATING_PRO = "PRO"
 	UNI_RATING_CON = "CON"
+
+	COMMENT_SORT_NEWEST        = "NEWEST"
+	COMMENT_SORT_TOP           = "TOP"
+	COMMENT_SORT_CONTROVERSIAL = "CONTROVERSIAL"
 )
 
+// The leaderboard_score view weighs the votes with the same points, it has to be recreated when they change
 const (
 	DEFAULT_INCREMENT_REPUTATION = 5
 	DEFAULT_DECREMENT_REPUTATION = 5
+	DEFAULT_SIGNUP_REPUTATION    = 50
+)
+
+// reputation
+const (
+	REPUTATION_SOURCE_SIGNUP             = "SIGNUP"
+	REPUTATION_SOURCE_THREAD_VOTE        = "THREAD_VOTE"
+	REPUTATION_SOURCE_COMMENT_VOTE       = "COMMENT_VOTE"
+	REPUTATION_SOURCE_COMMENT_REPLY_VOTE = "COMMENT_REPLY_VOTE"
+
+	REPUTATION_REASON_SIGNUP_BONUS     = "SIGNUP_BONUS"
+	REPUTATION_REASON_LIKE_RECEIVED    = "LIKE_RECEIVED"
+	REPUTATION_REASON_DISLIKE_RECEIVED = "DISLIKE_RECEIVED"
+	REPUTATION_REASON_VOTE_REMOVED     = "VOTE_REMOVED"
+)
+
+// badge
+const (
+	BADGE_FIRST_THREAD                = "FIRST_THREAD"
+	BADGE_LIKES_RECEIVED_100          = "LIKES_RECEIVED_100"
+	BADGE_VERIFIED_STUDENT            = "VERIFIED_STUDENT"
+	BADGE_TOP_CONTRIBUTOR             = "TOP_CONTRIBUTOR"
+	BADGE_HELPFUL_UNIVERSITY_REVIEWER = "HELPFUL_UNIVERSITY_REVIEWER"
+
+	BADGE_LIKES_RECEIVED_THRESHOLD    = 100
+	BADGE_TOP_CONTRIBUTOR_MIN_THREADS = 10
+	BADGE_HELPFUL_REVIEWER_MIN_POINTS = 2
 )
 
 // notification
@@ -29,6 +79,70 @@ const (
 
 	COMMENT_ON_THREAD_EVENT            = "COMMENT_ON_THREAD"
 	COMMENT_ON_SUBSCRIBED_THREAD_EVENT = "COMMENT_ON_SUSBCRIBED_THREAD"
+	REPLY_ON_COMMENT_EVENT             = "REPLY_ON_COMMENT"
+	THREAD_LIKE_MILESTONE_EVENT        = "THREAD_LIKE_MILESTONE"
+	COMMENT_LIKE_MILESTONE_EVENT       = "COMMENT_LIKE_MILESTONE"
+	BADGE_EARNED_EVENT                 = "BADGE_EARNED"
+	FOLLOWED_USER_POSTED_EVENT         = "FOLLOWED_USER_POSTED"
+)
+
+// realtime
+const (
+	THREAD_EVENT_COMMENT_CREATED    = "COMMENT_CREATED"
+	THREAD_EVENT_COMMENT_EDITED     = "COMMENT_EDITED"
+	THREAD_EVENT_VOTE_COUNT_CHANGED = "VOTE_COUNT_CHANGED"
+	THREAD_EVENT_THREAD_DELETED     = "THREAD_DELETED"
+
+	THREAD_EVENT_TARGET_THREAD               = "THREAD"
+	THREAD_EVENT_TARGET_THREAD_COMMENT       = "THREAD_COMMENT"
+	THREAD_EVENT_TARGET_THREAD_COMMENT_REPLY = "THREAD_COMMENT_REPLY"
+)
+
+// rate limit
+const (
+	RATE_LIMIT_STORE_MEMORY   = "memory"
+	RATE_LIMIT_STORE_POSTGRES = "postgres"
+
+	RATE_LIMIT_GROUP_AUTH          = "auth"
+	RATE_LIMIT_GROUP_THREAD_WRITE  = "thread_write"
+	RATE_LIMIT_GROUP_TOKEN_REFRESH = "token_refresh"
+)
+
+// profile
+const (
+	PROFILE_VISIBILITY_PUBLIC     = "PUBLIC"
+	PROFILE_VISIBILITY_UNIVERSITY = "UNIVERSITY"
+	PROFILE_VISIBILITY_PRIVATE    = "PRIVATE"
+)
+
+// storage
+const (
+	STORAGE_DRIVER_S3     = "s3"
+	STORAGE_DRIVER_LOCAL  = "local"
+	STORAGE_DRIVER_MEMORY = "memory"
+)
+
+// attachment
+const (
+	ATTACHMENT_TARGET_TYPE_THREAD               = "THREAD"
+	ATTACHMENT_TARGET_TYPE_THREAD_COMMENT       = "THREAD_COMMENT"
+	ATTACHMENT_TARGET_TYPE_THREAD_COMMENT_REPLY = "THREAD_COMMENT_REPLY"
+	ATTACHMENT_TARGET_TYPE_SUBTHREAD            = "SUBTHREAD"
+	ATTACHMENT_TARGET_TYPE_USER_AVATAR          = "USER_AVATAR"
+
+	IMAGE_UPLOAD_STATUS_PENDING   = "PENDING"
+	IMAGE_UPLOAD_STATUS_CONFIRMED = "CONFIRMED"
+)
+
+// outbox
+const (
+	OUTBOX_TYPE_PUSH_NOTIFICATION = "PUSH_NOTIFICATION"
+	OUTBOX_TYPE_EMAIL             = "EMAIL"
+	OUTBOX_TYPE_FOLLOWER_FAN_OUT  = "FOLLOWER_FAN_OUT"
+
+	OUTBOX_STATUS_PENDING = "PENDING"
+	OUTBOX_STATUS_SENT    = "SENT"
+	OUTBOX_STATUS_DEAD    = "DEAD"
 )
 
 // email
@@ -36,3 +150,70 @@ const (
 	TYPE_VERIFY_EMAIL   = "VERIFY_EMAIL"
 	TYPE_RESET_PASSWORD = "RESET_PASSWORD"
 )
+
+// moderation
+const (
+	REPORT_CONTENT_TYPE_THREAD               = "THREAD"
+	REPORT_CONTENT_TYPE_THREAD_COMMENT       = "THREAD_COMMENT"
+	REPORT_CONTENT_TYPE_THREAD_COMMENT_REPLY = "THREAD_COMMENT_REPLY"
+	REPORT_CONTENT_TYPE_UNIVERSITY_RATING    = "UNIVERSITY_RATING"
+
+	REPORT_STATUS_PENDING   = "PENDING"
+	REPORT_STATUS_RESOLVED  = "RESOLVED"
+	REPORT_STATUS_DISMISSED = "DISMISSED"
+
+	MODERATION_ACTION_HIDE_CONTENT = "HIDE_CONTENT"
+	MODERATION_ACTION_WARN_AUTHOR  = "WARN_AUTHOR"
+	MODERATION_ACTION_BAN_AUTHOR   = "BAN_AUTHOR"
+	MODERATION_ACTION_DISMISS      = "DISMISS"
+)
+
+// search
+const (
+	SEARCH_TYPE_THREAD            = "THREAD"
+	SEARCH_TYPE_SUBTHREAD         = "SUBTHREAD"
+	SEARCH_TYPE_UNIVERSITY_RATING = "UNIVERSITY_RATING"
+)
+
+// leaderboard
+const (
+	LEADERBOARD_PERIOD_WEEK     = "WEEK"
+	LEADERBOARD_PERIOD_MONTH    = "MONTH"
+	LEADERBOARD_PERIOD_ALL_TIME = "ALL_TIME"
+
+	LEADERBOARD_MAX_LIMIT = 100
+)
+
+// subthread
+const (
+	SUBTHREAD_MAX_RULES          = 15
+	SUBTHREAD_MAX_PINNED_THREADS = 3
+)
+
+// thread
+const (
+	// the home list can gather the pinned threads of many subthreads
+	THREAD_LIST_MAX_PINNED_THREADS = 10
+)
+
+// revision
+const (
+	REVISION_TARGET_TYPE_THREAD               = "THREAD"
+	REVISION_TARGET_TYPE_THREAD_COMMENT       = "THREAD_COMMENT"
+	REVISION_TARGET_TYPE_THREAD_COMMENT_REPLY = "THREAD_COMMENT_REPLY"
+)
+
+// bookmark
+const (
+	BOOKMARK_TARGET_TYPE_THREAD               = "THREAD"
+	BOOKMARK_TARGET_TYPE_THREAD_COMMENT       = "THREAD_COMMENT"
+	BOOKMARK_TARGET_TYPE_THREAD_COMMENT_REPLY = "THREAD_COMMENT_REPLY"
+
+	BOOKMARK_MAX_COLLECTIONS = 50
+)
+
+// user block
+const (
+	USER_BLOCK_TYPE_BLOCK = "BLOCK"
+	USER_BLOCK_TYPE_MUTE  = "MUTE"
+)
@@ -0,0 +1,41 @@
+package leaderboard
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/worker"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Refresher periodically recomputes the leaderboard scores so the leaderboard requests only read the
+// materialized view instead of aggregating the vote activity every time
+type Refresher struct {
+	cfg             config.Config
+	leaderboardRepo repository.LeaderboardRepository
+}
+
+// NewRefresher returns the worker running the refresher. When it is shut down the in-flight refresh is
+// cancelled, the view keeps the scores of the previous refresh
+func NewRefresher(cfg config.Config, leaderboardRepo repository.LeaderboardRepository) *worker.Worker {
+
+	r := &Refresher{
+		cfg:             cfg,
+		leaderboardRepo: leaderboardRepo,
+	}
+
+	return worker.New("leaderboard refresher", time.Duration(cfg.GetLeaderboardCfg().RefreshIntervalMins)*time.Minute, r.refresh)
+}
+
+func (r *Refresher) refresh(ctx context.Context, stopping <-chan struct{}) {
+	start := time.Now()
+
+	err := r.leaderboardRepo.Refresh(ctx)
+	if err != nil {
+		r.cfg.Logger().ErrorWithContext(ctx, "[Refresher.refresh] Failed to refresh leaderboard scores", zap.Error(err))
+		return
+	}
+
+	r.cfg.Logger().Info("[Refresher.refresh] Refreshed leaderboard scores", zap.Duration("duration", time.Since(start)))
+}
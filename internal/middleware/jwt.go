@@ -1,9 +1,9 @@
 package middleware
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	"github.com/andibalo/meowhasiswa-be/internal/config"
-	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpclient"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
@@ -17,11 +17,12 @@ import (
 
 // TokenClaims : struct for validate token claims
 type TokenClaims struct {
-	ID       string `json:"id"`
-	UserName string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
-	Token    string `json:"token"`
+	ID        string `json:"id"`
+	UserName  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	Token     string `json:"token"`
 	jwt.RegisteredClaims
 }
 
@@ -29,11 +30,12 @@ type TokenClaims struct {
 const ContextClaimKey = "ctx.mw.auth.claim"
 
 // JwtMiddleware : check jwt token header bearer scheme
-func JwtMiddleware(cfg config.Config) gin.HandlerFunc {
+func (m *Middleware) JwtMiddleware() gin.HandlerFunc {
+	cfg := m.cfg
+
 	return func(ctx *gin.Context) {
 		ctx.Writer.Header().Set("Content-Type", "application/json")
 		secretKey := cfg.GetAuthCfg().JWTSecret
-		staticToken := cfg.GetAuthCfg().JWTStaticToken
 
 		// token claims
 		claims := &TokenClaims{}
@@ -43,18 +45,6 @@ func JwtMiddleware(cfg config.Config) gin.HandlerFunc {
 			return
 		}
 
-		if headerToken == staticToken {
-			ctx.Set(httpclient.XUserEmail, constants.EMAIL_ADMIN_MEOWHASISWA)
-			ctx.Set(ContextClaimKey, &TokenClaims{
-				Email:    constants.EMAIL_ADMIN_MEOWHASISWA,
-				UserName: "superadmin",
-				Role:     constants.ADMIN_ROLE,
-			})
-
-			ctx.Next()
-			return
-		}
-
 		token, err := jwt.ParseWithClaims(headerToken, claims, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok { // check signing method
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -69,6 +59,13 @@ func JwtMiddleware(cfg config.Config) gin.HandlerFunc {
 		}
 
 		if claims, ok := token.Claims.(*TokenClaims); ok && token.Valid {
+			err = m.validateSession(claims)
+			if err != nil {
+				cfg.Logger().ErrorWithContext(ctx, "[JWTMiddleware] User session is invalid", zap.String("session_id", claims.SessionID), zap.Error(err))
+				httpresp.HttpRespError(ctx, err)
+				return
+			}
+
 			claims.Token = headerToken
 			ctx.Set(httpclient.XUserEmail, claims.Email)
 			ctx.Set(ContextClaimKey, claims)
@@ -81,22 +78,39 @@ func JwtMiddleware(cfg config.Config) gin.HandlerFunc {
 	}
 }
 
-func IsAdminMiddleware(cfg config.Config) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
+// OptionalJwtMiddleware : lets anonymous requests through, a request with an Authorization header must carry a valid token
+func (m *Middleware) OptionalJwtMiddleware() gin.HandlerFunc {
+	jwtMiddleware := m.JwtMiddleware()
 
-		claims := ParseToken(ctx)
-
-		if claims.Email == constants.EMAIL_ADMIN_MEOWHASISWA {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Header.Get("Authorization") == "" {
 			ctx.Next()
 			return
 		}
 
-		if claims.Role != constants.ADMIN_ROLE {
-			cfg.Logger().ErrorWithContext(ctx, "[IsAdminMiddleware] User unauthorized")
-			httpresp.HttpRespError(ctx, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("User unauthorized"))
-			return
+		jwtMiddleware(ctx)
+	}
+}
+
+func (m *Middleware) validateSession(claims *TokenClaims) error {
+	if claims.SessionID == "" {
+		return oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("Token is not bound to any session")
+	}
+
+	userSession, err := m.sessionRepo.GetUserSessionByID(claims.SessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("Session not found")
 		}
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user session")
 	}
+
+	if !userSession.IsActive() {
+		return oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("Session is revoked or expired")
+	}
+
+	return nil
 }
 
 func ParseTokenFromHeader(ctx *gin.Context) (string, error) {
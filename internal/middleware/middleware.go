@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/ratelimit"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+)
+
+// Middleware : builds the middlewares that need more than the config to run
+type Middleware struct {
+	cfg            config.Config
+	sessionRepo    repository.UserRepository
+	roleRepo       repository.RoleRepository
+	rateLimitStore ratelimit.Store
+}
+
+func NewMiddleware(cfg config.Config, sessionRepo repository.UserRepository, roleRepo repository.RoleRepository, rateLimitStore ratelimit.Store) *Middleware {
+
+	return &Middleware{
+		cfg:            cfg,
+		sessionRepo:    sessionRepo,
+		roleRepo:       roleRepo,
+		rateLimitStore: rateLimitStore,
+	}
+}
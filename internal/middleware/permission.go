@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// PermissionMiddleware : allow the request only if the user has the permission through a global role.
+// Must be registered after JwtMiddleware
+func (m *Middleware) PermissionMiddleware(permission string) gin.HandlerFunc {
+	return m.checkPermission(permission, "", "")
+}
+
+// ScopedPermissionMiddleware : allow the request if the user has the permission through a global role
+// or through a role scoped to the resource identified by the paramKey route param.
+// Must be registered after JwtMiddleware
+func (m *Middleware) ScopedPermissionMiddleware(permission string, scopeType string, paramKey string) gin.HandlerFunc {
+	return m.checkPermission(permission, scopeType, paramKey)
+}
+
+func (m *Middleware) checkPermission(permission string, scopeType string, paramKey string) gin.HandlerFunc {
+	cfg := m.cfg
+
+	return func(ctx *gin.Context) {
+
+		claims := ParseToken(ctx)
+		if len(claims.Token) == 0 {
+			cfg.Logger().ErrorWithContext(ctx, "[PermissionMiddleware] User unauthorized")
+			httpresp.HttpRespError(ctx, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf(apperr.ErrUnauthorized))
+			return
+		}
+
+		var scopeID string
+		if paramKey != "" {
+			scopeID = ctx.Param(paramKey)
+		}
+
+		hasPermission, err := m.roleRepo.HasPermission(claims.ID, permission, scopeType, scopeID)
+		if err != nil {
+			cfg.Logger().ErrorWithContext(ctx, "[PermissionMiddleware] Failed to check user permission", zap.String("permission", permission), zap.Error(err))
+			httpresp.HttpRespError(ctx, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError))
+			return
+		}
+
+		if !hasPermission {
+			cfg.Logger().ErrorWithContext(ctx, "[PermissionMiddleware] User does not have permission", zap.String("user_id", claims.ID), zap.String("permission", permission))
+			httpresp.HttpRespError(ctx, oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("User does not have permission"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"math"
+	"net/http"
+)
+
+// RateLimitMiddleware : limit the requests to a route group per client IP, as resolved through the trusted proxies, and, when registered after JwtMiddleware, per user.
+// Requests are let through when the store is unavailable so an outage of the store does not take the API down
+func (m *Middleware) RateLimitMiddleware(group string, rule config.RateLimitRule) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		if rule.IPRequestsPerMin > 0 {
+			key := fmt.Sprintf("%s:ip:%s", group, ctx.ClientIP())
+
+			if !m.takeRateLimitToken(ctx, key, rule.IPRequestsPerMin, rule.IPBurst) {
+				return
+			}
+		}
+
+		claims := ParseToken(ctx)
+		if rule.UserRequestsPerMin > 0 && len(claims.Token) > 0 {
+			key := fmt.Sprintf("%s:user:%s", group, claims.ID)
+
+			if !m.takeRateLimitToken(ctx, key, rule.UserRequestsPerMin, rule.UserBurst) {
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+func (m *Middleware) takeRateLimitToken(ctx *gin.Context, key string, requestsPerMin int, burst int) bool {
+	cfg := m.cfg
+
+	isAllowed, retryAfter, err := m.rateLimitStore.Take(ctx, key, float64(requestsPerMin)/60, max(burst, 1))
+	if err != nil {
+		cfg.Logger().ErrorWithContext(ctx, "[RateLimitMiddleware] Failed to take rate limit token", zap.String("key", key), zap.Error(err))
+		return true
+	}
+
+	if isAllowed {
+		return true
+	}
+
+	cfg.Logger().WarnWithContext(ctx, "[RateLimitMiddleware] Rate limit exceeded", zap.String("key", key))
+	httpresp.HttpRespError(ctx, oops.Code(response.TooManyRequests.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusTooManyRequests).With(httpresp.RetryAfterCtxKey, int(math.Ceil(retryAfter.Seconds()))).Errorf(apperr.ErrRateLimited))
+
+	return false
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Attachment struct {
+	bun.BaseModel `bun:"table:attachment,alias:att"`
+
+	ID          string              `bun:",pk" json:"id"`
+	UserID      string              `bun:"user_id" json:"user_id"`
+	Bucket      string              `bun:"bucket" json:"bucket"`
+	FileKey     string              `bun:"file_key" json:"file_key"`
+	URL         string              `bun:"url" json:"url"`
+	ContentType string              `bun:"content_type" json:"content_type"`
+	SizeBytes   int64               `bun:"size_bytes" json:"size_bytes"`
+	Width       *int                `bun:"width" json:"width"`
+	Height      *int                `bun:"height" json:"height"`
+	ContentHash *string             `bun:"content_hash" json:"content_hash"`
+	Variants    []AttachmentVariant `bun:"variants,type:jsonb" json:"variants"`
+	AltText     *string             `bun:"alt_text" json:"alt_text"`
+	TargetType  *string             `bun:"target_type" json:"target_type"`
+	TargetID    *string             `bun:"target_id" json:"target_id"`
+	Position    int                 `bun:"position" json:"position"`
+	AttachedAt  bun.NullTime        `bun:"attached_at" json:"attached_at"`
+	CreatedBy   string              `bun:"created_by" json:"created_by"`
+	CreatedAt   time.Time           `bun:",nullzero,default:now()" json:"created_at"`
+}
+
+// AttachmentVariant is a processed rendition of the upload, the original variant is also stored in the attachment columns
+type AttachmentVariant struct {
+	Name      string `json:"name"`
+	FileKey   string `json:"file_key"`
+	URL       string `json:"url"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	SizeBytes int64  `json:"size_bytes"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Badge struct {
+	bun.BaseModel `bun:"table:badge,alias:b"`
+
+	ID          string    `bun:",pk" json:"id"`
+	Code        string    `bun:"code" json:"code"`
+	Name        string    `bun:"name" json:"name"`
+	Description string    `bun:"description" json:"description"`
+	IconURL     *string   `bun:"icon_url" json:"icon_url"`
+	CreatedBy   string    `bun:"created_by" json:"-"`
+	CreatedAt   time.Time `bun:",nullzero,default:now()" json:"-"`
+}
+
+type UserBadge struct {
+	bun.BaseModel `bun:"table:user_badge,alias:ub"`
+
+	ID        string    `bun:",pk" json:"id"`
+	UserID    string    `bun:"user_id" json:"user_id"`
+	BadgeID   string    `bun:"badge_id" json:"badge_id"`
+	Badge     *Badge    `bun:"rel:belongs-to,join:badge_id=id" json:"badge"`
+	ScopeType *string   `bun:"scope_type" json:"scope_type"`
+	ScopeID   *string   `bun:"scope_id" json:"scope_id"`
+	CreatedBy string    `bun:"created_by" json:"-"`
+	CreatedAt time.Time `bun:",nullzero,default:now()" json:"created_at"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type BookmarkCollection struct {
+	bun.BaseModel `bun:"table:bookmark_collection,alias:bmc"`
+
+	ID            string       `bun:",pk" json:"id"`
+	UserID        string       `bun:"user_id" json:"user_id"`
+	Name          string       `bun:"name" json:"name"`
+	BookmarkCount int64        `bun:"bookmark_count,scanonly" json:"bookmark_count"`
+	CreatedBy     string       `bun:"created_by" json:"-"`
+	CreatedAt     time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy     *string      `json:"-"`
+	UpdatedAt     bun.NullTime `json:"updated_at"`
+	DeletedBy     *string      `json:"-"`
+	DeletedAt     time.Time    `bun:",nullzero,soft_delete" json:"-"`
+}
+
+type Bookmark struct {
+	bun.BaseModel `bun:"table:bookmark,alias:bm"`
+
+	ID           string       `bun:",pk" json:"id"`
+	UserID       string       `bun:"user_id" json:"user_id"`
+	TargetType   string       `bun:"target_type" json:"target_type"`
+	TargetID     string       `bun:"target_id" json:"target_id"`
+	ThreadID     string       `bun:"thread_id" json:"thread_id"`
+	Thread       *Thread      `bun:"rel:belongs-to,join:thread_id=id" json:"thread"`
+	CollectionID *string      `bun:"collection_id" json:"collection_id"`
+	CreatedBy    string       `bun:"created_by" json:"-"`
+	CreatedAt    time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy    *string      `json:"-"`
+	UpdatedAt    bun.NullTime `json:"updated_at"`
+}
@@ -1,10 +1,15 @@
 package model
 
 import (
+	"errors"
 	"image"
 	"io"
+	"time"
 )
 
+// ErrFileNotFound is returned by the file storages when the object does not exist
+var ErrFileNotFound = errors.New("file not found")
+
 type File struct {
 	ETag             string      `json:"e_tag"`
 	VersionId        string      `json:"version_id"`
@@ -42,3 +47,16 @@ type UploadFileOutputDTO struct {
 	Tags        string
 	Bucket      string
 }
+
+// PresignedURLDTO is a time limited request to the storage, Headers must be sent as is along with the request
+type PresignedURLDTO struct {
+	URL       string
+	Method    string
+	Headers   map[string]string
+	ExpiresAt time.Time
+}
+
+type FileInfoDTO struct {
+	Size        int64
+	ContentType string
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type ImageUpload struct {
+	bun.BaseModel `bun:"table:image_upload,alias:imu"`
+
+	ID             string       `bun:",pk" json:"id"`
+	UserID         string       `bun:"user_id" json:"user_id"`
+	Bucket         string       `bun:"bucket" json:"bucket"`
+	FileKey        string       `bun:"file_key" json:"file_key"`
+	ContentType    string       `bun:"content_type" json:"content_type"`
+	SizeBytes      int64        `bun:"size_bytes" json:"size_bytes"`
+	ChecksumSHA256 string       `bun:"checksum_sha256" json:"checksum_sha256"`
+	AltText        *string      `bun:"alt_text" json:"alt_text"`
+	Status         string       `bun:"status" json:"status"`
+	AttachmentID   *string      `bun:"attachment_id" json:"attachment_id"`
+	ExpiresAt      time.Time    `bun:"expires_at" json:"expires_at"`
+	ConfirmedAt    bun.NullTime `bun:"confirmed_at" json:"confirmed_at"`
+	CreatedBy      string       `bun:"created_by" json:"created_by"`
+	CreatedAt      time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+}
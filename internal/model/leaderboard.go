@@ -0,0 +1,14 @@
+package model
+
+// LeaderboardEntry is a user ranked by the reputation they gained in a leaderboard period
+type LeaderboardEntry struct {
+	Rank                      int64   `bun:"rank" json:"rank"`
+	UserID                    string  `bun:"user_id" json:"user_id"`
+	Username                  string  `bun:"username" json:"username"`
+	AvatarURL                 *string `bun:"avatar_url" json:"avatar_url"`
+	UniversityID              *string `bun:"university_id" json:"university_id"`
+	UniversityAbbreviatedName *string `bun:"university_abbreviated_name" json:"university_abbreviated_name"`
+	Points                    int64   `bun:"points" json:"points"`
+	LikeCount                 int64   `bun:"like_count" json:"like_count"`
+	DislikeCount              int64   `bun:"dislike_count" json:"dislike_count"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Notification struct {
+	bun.BaseModel `bun:"table:notification,alias:n"`
+
+	ID      string            `bun:",pk" json:"id"`
+	UserID  string            `bun:"user_id" json:"user_id"`
+	ActorID *string           `bun:"actor_id" json:"actor_id"`
+	Type    string            `bun:"type" json:"type"`
+	Title   string            `bun:"title" json:"title"`
+	Content string            `bun:"content" json:"content"`
+	Data    map[string]string `bun:"data,type:jsonb" json:"data"`
+	// DedupeKey prevents the same one-off event (e.g. a like milestone) from being recorded twice for a user
+	DedupeKey *string      `bun:"dedupe_key" json:"-"`
+	IsRead    bool         `bun:"is_read" json:"is_read"`
+	ReadAt    bun.NullTime `bun:"read_at" json:"read_at"`
+	CreatedBy string       `bun:"created_by" json:"created_by"`
+	CreatedAt time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type OutboxMessage struct {
+	bun.BaseModel `bun:"table:outbox,alias:ob"`
+
+	ID            string          `bun:",pk" json:"id"`
+	Type          string          `bun:"type" json:"type"`
+	Payload       json.RawMessage `bun:"payload,type:jsonb" json:"payload"`
+	Status        string          `bun:"status" json:"status"`
+	Attempts      int             `bun:"attempts" json:"attempts"`
+	NextAttemptAt time.Time       `bun:",nullzero,default:now()" json:"next_attempt_at"`
+	LastError     *string         `bun:"last_error" json:"last_error"`
+	ProcessedAt   bun.NullTime    `bun:"processed_at" json:"processed_at"`
+	CreatedBy     string          `bun:"created_by" json:"created_by"`
+	CreatedAt     time.Time       `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedAt     bun.NullTime    `bun:"updated_at" json:"updated_at"`
+}
+
+// FollowerFanOutPayload is a new thread to notify the followers of its author of, Cursor is the id of the last follower
+// notified so far
+type FollowerFanOutPayload struct {
+	AuthorID     string  `json:"author_id"`
+	UniversityID *string `json:"university_id"`
+	ThreadID     string  `json:"thread_id"`
+	Title        string  `json:"title"`
+	Content      string  `json:"content"`
+	Cursor       string  `json:"cursor"`
+}
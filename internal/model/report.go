@@ -0,0 +1,51 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Report struct {
+	bun.BaseModel `bun:"table:report,alias:rep"`
+
+	ID               string       `bun:",pk" json:"id"`
+	ReporterID       string       `bun:"reporter_id" json:"reporter_id"`
+	Reporter         *User        `bun:"rel:belongs-to,join:reporter_id=id" json:"reporter,omitempty"`
+	ContentType      string       `bun:"content_type" json:"content_type"`
+	ContentID        string       `bun:"content_id" json:"content_id"`
+	ContentAuthorID  string       `bun:"content_author_id" json:"content_author_id"`
+	Reason           string       `bun:"reason" json:"reason"`
+	Description      *string      `bun:"description" json:"description"`
+	Status           string       `bun:"status" json:"status"`
+	ResolutionAction *string      `bun:"resolution_action" json:"resolution_action"`
+	ResolutionNote   *string      `bun:"resolution_note" json:"resolution_note"`
+	ResolvedAt       bun.NullTime `bun:"resolved_at" json:"resolved_at"`
+	ResolvedBy       *string      `bun:"resolved_by" json:"resolved_by"`
+	CreatedBy        string       `bun:"created_by" json:"created_by"`
+	CreatedAt        time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy        *string      `bun:"updated_by" json:"updated_by"`
+	UpdatedAt        bun.NullTime `bun:"updated_at" json:"updated_at"`
+}
+
+// ReportQueueItem is an aggregate of every report filed against a single content
+type ReportQueueItem struct {
+	ContentType     string    `bun:"content_type" json:"content_type"`
+	ContentID       string    `bun:"content_id" json:"content_id"`
+	ContentAuthorID string    `bun:"content_author_id" json:"content_author_id"`
+	ReportCount     int64     `bun:"report_count" json:"report_count"`
+	Reasons         []string  `bun:"reasons,array" json:"reasons"`
+	FirstReportedAt time.Time `bun:"first_reported_at" json:"first_reported_at"`
+	LastReportedAt  time.Time `bun:"last_reported_at" json:"last_reported_at"`
+}
+
+type UserWarning struct {
+	bun.BaseModel `bun:"table:user_warning,alias:uw"`
+
+	ID          string    `bun:",pk" json:"id"`
+	UserID      string    `bun:"user_id" json:"user_id"`
+	ContentType string    `bun:"content_type" json:"content_type"`
+	ContentID   string    `bun:"content_id" json:"content_id"`
+	Note        *string   `bun:"note" json:"note"`
+	CreatedBy   string    `bun:"created_by" json:"created_by"`
+	CreatedAt   time.Time `bun:",nullzero,default:now()" json:"created_at"`
+}
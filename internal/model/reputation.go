@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type ReputationEvent struct {
+	bun.BaseModel `bun:"table:reputation_event,alias:re"`
+
+	ID         string  `bun:",pk" json:"id"`
+	UserID     string  `bun:"user_id" json:"user_id"`
+	SourceType string  `bun:"source_type" json:"source_type"`
+	SourceID   *string `bun:"source_id" json:"source_id"`
+	// ActorID is the voter, it is kept out of the history so votes stay anonymous
+	ActorID   *string   `bun:"actor_id" json:"-"`
+	Delta     int64     `bun:"delta" json:"delta"`
+	Reason    string    `bun:"reason" json:"reason"`
+	CreatedBy string    `bun:"created_by" json:"-"`
+	CreatedAt time.Time `bun:",nullzero,default:now()" json:"created_at"`
+}
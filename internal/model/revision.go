@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// ContentRevision is a prior version of a thread, comment or reply. Title and ContentSummary are only set for threads
+type ContentRevision struct {
+	bun.BaseModel `bun:"table:content_revision,alias:crv"`
+
+	ID             string    `bun:",pk" json:"id"`
+	TargetType     string    `bun:"target_type" json:"target_type"`
+	TargetID       string    `bun:"target_id" json:"target_id"`
+	ThreadID       string    `bun:"thread_id" json:"thread_id"`
+	Title          *string   `bun:"title" json:"title"`
+	Content        string    `bun:"content" json:"content"`
+	ContentSummary *string   `bun:"content_summary" json:"content_summary"`
+	CreatedBy      string    `bun:"created_by" json:"-"`
+	CreatedAt      time.Time `bun:",nullzero,default:now()" json:"created_at"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Role struct {
+	bun.BaseModel `bun:"table:role,alias:r"`
+
+	ID              string            `bun:",pk" json:"id"`
+	Name            string            `bun:"name" json:"name"`
+	Description     *string           `bun:"description" json:"description"`
+	ScopeType       *string           `bun:"scope_type" json:"scope_type"`
+	RolePermissions []*RolePermission `bun:"rel:has-many,join:id=role_id" json:"permissions"`
+	CreatedBy       string            `bun:"created_by" json:"created_by"`
+	CreatedAt       time.Time         `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy       *string           `bun:"updated_by" json:"updated_by"`
+	UpdatedAt       bun.NullTime      `bun:"updated_at" json:"updated_at"`
+}
+
+type Permission struct {
+	bun.BaseModel `bun:"table:permission,alias:p"`
+
+	ID          string       `bun:",pk" json:"id"`
+	Name        string       `bun:"name" json:"name"`
+	Description *string      `bun:"description" json:"description"`
+	CreatedBy   string       `bun:"created_by" json:"created_by"`
+	CreatedAt   time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy   *string      `bun:"updated_by" json:"updated_by"`
+	UpdatedAt   bun.NullTime `bun:"updated_at" json:"updated_at"`
+}
+
+type RolePermission struct {
+	bun.BaseModel `bun:"table:role_permission,alias:rp"`
+
+	ID           string      `bun:",pk" json:"id"`
+	RoleID       string      `bun:"role_id" json:"role_id"`
+	PermissionID string      `bun:"permission_id" json:"permission_id"`
+	Permission   *Permission `bun:"rel:belongs-to,join:permission_id=id" json:"permission"`
+	CreatedBy    string      `bun:"created_by" json:"created_by"`
+	CreatedAt    time.Time   `bun:",nullzero,default:now()" json:"created_at"`
+}
+
+type UserRole struct {
+	bun.BaseModel `bun:"table:user_role,alias:ur"`
+
+	ID        string       `bun:",pk" json:"id"`
+	UserID    string       `bun:"user_id" json:"user_id"`
+	User      *User        `bun:"rel:belongs-to,join:user_id=id" json:"-"`
+	RoleID    string       `bun:"role_id" json:"role_id"`
+	Role      *Role        `bun:"rel:belongs-to,join:role_id=id" json:"role"`
+	ScopeType *string      `bun:"scope_type" json:"scope_type"`
+	ScopeID   *string      `bun:"scope_id" json:"scope_id"`
+	CreatedBy string       `bun:"created_by" json:"created_by"`
+	CreatedAt time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy *string      `bun:"updated_by" json:"updated_by"`
+	UpdatedAt bun.NullTime `bun:"updated_at" json:"updated_at"`
+	DeletedBy *string      `bun:"deleted_by" json:"-"`
+	DeletedAt time.Time    `bun:",nullzero,soft_delete" json:"-"`
+}
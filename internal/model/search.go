@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// SearchResult is a single match of the global search, Type tells which table the ID belongs to
+type SearchResult struct {
+	Type      string    `bun:"type" json:"type"`
+	ID        string    `bun:"id" json:"id"`
+	Title     string    `bun:"title" json:"title"`
+	Snippet   string    `bun:"snippet" json:"snippet"`
+	Rank      float64   `bun:"rank" json:"rank"`
+	CreatedAt time.Time `bun:"created_at" json:"created_at"`
+}
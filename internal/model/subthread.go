@@ -16,6 +16,9 @@ type SubThread struct {
 	LabelColor            string       `bun:"label_color" json:"label_color"`
 	UniversityID          *string      `bun:"university_id" json:"university_id"`
 	IsUniversitySubThread bool         `bun:"is_university_subthread" json:"is_university_subthread"`
+	OwnerID               *string      `bun:"owner_id" json:"owner_id"`
+	IsRevisionPublic      bool         `bun:"is_revision_public" json:"is_revision_public"`
+	SearchVector          string       `bun:"search_vector,scanonly" json:"-"`
 	CreatedBy             string       `bun:"created_by" json:"created_by"`
 	CreatedAt             time.Time    `bun:",nullzero,default:now()" json:"created_at"`
 	UpdatedBy             *string      `json:"updated_by"`
@@ -38,3 +41,37 @@ type SubThreadFollower struct {
 	DeletedBy   *string
 	DeletedAt   time.Time `bun:",nullzero,soft_delete"`
 }
+
+type SubThreadRule struct {
+	bun.BaseModel `bun:"table:subthread_rule,alias:str"`
+
+	ID          string       `bun:",pk" json:"id"`
+	SubThreadID string       `bun:"subthread_id" json:"subthread_id"`
+	Title       string       `bun:"title" json:"title"`
+	Description *string      `bun:"description" json:"description"`
+	Position    int          `bun:"position" json:"position"`
+	CreatedBy   string       `bun:"created_by" json:"created_by"`
+	CreatedAt   time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy   *string      `json:"updated_by"`
+	UpdatedAt   bun.NullTime `json:"updated_at"`
+	DeletedBy   *string      `json:"-"`
+	DeletedAt   time.Time    `bun:",nullzero,soft_delete" json:"-"`
+}
+
+// SubThreadBan stops a user from posting in a subthread until ExpiredAt, a nil ExpiredAt never expires
+type SubThreadBan struct {
+	bun.BaseModel `bun:"table:subthread_ban,alias:stb"`
+
+	ID          string       `bun:",pk" json:"id"`
+	SubThreadID string       `bun:"subthread_id" json:"subthread_id"`
+	UserID      string       `bun:"user_id" json:"user_id"`
+	User        *User        `bun:"rel:belongs-to,join:user_id=id" json:"user,omitempty"`
+	Reason      *string      `bun:"reason" json:"reason"`
+	ExpiredAt   bun.NullTime `bun:"expired_at" json:"expired_at"`
+	CreatedBy   string       `bun:"created_by" json:"created_by"`
+	CreatedAt   time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy   *string      `json:"updated_by"`
+	UpdatedAt   bun.NullTime `json:"updated_at"`
+	DeletedBy   *string      `json:"-"`
+	DeletedAt   time.Time    `bun:",nullzero,soft_delete" json:"-"`
+}
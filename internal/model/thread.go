@@ -20,8 +20,23 @@ type Thread struct {
 	LikeCount      int64            `bun:"like_count" json:"like_count"`
 	DislikeCount   int64            `bun:"dislike_count" json:"dislike_count"`
 	CommentCount   int64            `bun:"comment_count" json:"comment_count"`
+	IsPinned       bool             `bun:"is_pinned" json:"is_pinned"`
+	PinnedAt       bun.NullTime     `bun:"pinned_at" json:"pinned_at"`
+	PinnedBy       *string          `bun:"pinned_by" json:"-"`
+	IsLocked       bool             `bun:"is_locked" json:"is_locked"`
+	LockedAt       bun.NullTime     `bun:"locked_at" json:"locked_at"`
+	LockedBy       *string          `bun:"locked_by" json:"-"`
+	IsArchived     bool             `bun:"is_archived" json:"is_archived"`
+	ArchivedAt     bun.NullTime     `bun:"archived_at" json:"archived_at"`
+	ArchivedBy     *string          `bun:"archived_by" json:"-"`
+	LastActivityAt time.Time        `bun:",nullzero,default:now()" json:"last_activity_at"`
+	EditedAt       bun.NullTime     `bun:"edited_at" json:"edited_at"`
 	TrendingScore  float64          `bun:"trending_score,scanonly"`
 	ThreadAction   string           `bun:"thread_action,scanonly"`
+	IsBookmarked   bool             `bun:"is_bookmarked,scanonly" json:"-"`
+	SearchRank     float64          `bun:"search_rank,scanonly" json:"-"`
+	SearchSnippet  string           `bun:"search_snippet,scanonly" json:"-"`
+	SearchVector   string           `bun:"search_vector,scanonly" json:"-"`
 	Comments       []*ThreadComment `bun:"rel:has-many,join:id=thread_id"`
 	CreatedBy      string           `bun:"created_by" json:"created_by"`
 	CreatedAt      time.Time        `bun:",nullzero,default:now()" json:"created_at"`
@@ -58,6 +73,8 @@ type ThreadComment struct {
 	DislikeCount  int64                 `bun:"dislike_count" json:"dislike_count"`
 	ReplyCount    int64                 `bun:"reply_count" json:"reply_count"`
 	CommentAction string                `bun:"comment_action,scanonly"`
+	SortValue     float64               `bun:"sort_value,scanonly" json:"-"`
+	EditedAt      bun.NullTime          `bun:"edited_at" json:"edited_at"`
 	Replies       []*ThreadCommentReply `bun:"rel:has-many,join:id=thread_comment_id"`
 	CreatedBy     string                `bun:"created_by" json:"created_by"`
 	CreatedAt     time.Time             `bun:",nullzero,default:now()" json:"created_at"`
@@ -94,10 +111,14 @@ type ThreadCommentReply struct {
 	ThreadID           string       `bun:"thread_id" json:"thread_id"`
 	Thread             SubThread    `bun:"rel:belongs-to,join:thread_id=id" json:"thread"`
 	ThreadCommentID    string       `bun:"thread_comment_id" json:"thread_comment_id"`
+	ParentReplyID      *string      `bun:"parent_reply_id" json:"parent_reply_id"`
 	Content            string       `bun:"content" json:"content"`
 	LikeCount          int64        `bun:"like_count" json:"like_count"`
 	DislikeCount       int64        `bun:"dislike_count" json:"dislike_count"`
+	ReplyCount         int64        `bun:"reply_count" json:"reply_count"`
 	CommentReplyAction string       `bun:"comment_reply_action,scanonly"`
+	SortValue          float64      `bun:"sort_value,scanonly" json:"-"`
+	EditedAt           bun.NullTime `bun:"edited_at" json:"edited_at"`
 	CreatedBy          string       `bun:"created_by" json:"created_by"`
 	CreatedAt          time.Time    `bun:",nullzero,default:now()" json:"created_at"`
 	UpdatedBy          *string      `json:"updated_by"`
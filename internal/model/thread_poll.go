@@ -0,0 +1,51 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type ThreadPoll struct {
+	bun.BaseModel `bun:"table:thread_poll,alias:thp"`
+
+	ID               string              `bun:",pk" json:"id"`
+	ThreadID         string              `bun:"thread_id" json:"thread_id"`
+	Question         string              `bun:"question" json:"question"`
+	IsMultipleChoice bool                `bun:"is_multiple_choice" json:"is_multiple_choice"`
+	ClosesAt         bun.NullTime        `bun:"closes_at" json:"closes_at"`
+	VoterCount       int64               `bun:"voter_count" json:"voter_count"`
+	Options          []*ThreadPollOption `bun:"rel:has-many,join:id=poll_id" json:"options"`
+	CreatedBy        string              `bun:"created_by" json:"created_by"`
+	CreatedAt        time.Time           `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy        *string             `bun:"updated_by" json:"updated_by"`
+	UpdatedAt        bun.NullTime        `bun:"updated_at" json:"updated_at"`
+}
+
+// IsClosed reports whether the poll stopped accepting votes
+func (p ThreadPoll) IsClosed() bool {
+	return !p.ClosesAt.IsZero() && !p.ClosesAt.After(time.Now())
+}
+
+type ThreadPollOption struct {
+	bun.BaseModel `bun:"table:thread_poll_option,alias:thpo"`
+
+	ID        string    `bun:",pk" json:"id"`
+	PollID    string    `bun:"poll_id" json:"poll_id"`
+	Content   string    `bun:"content" json:"content"`
+	Position  int       `bun:"position" json:"position"`
+	VoteCount int64     `bun:"vote_count" json:"vote_count"`
+	CreatedBy string    `bun:"created_by" json:"created_by"`
+	CreatedAt time.Time `bun:",nullzero,default:now()" json:"created_at"`
+}
+
+type ThreadPollVote struct {
+	bun.BaseModel `bun:"table:thread_poll_vote,alias:thpv"`
+
+	ID               string    `bun:",pk" json:"id"`
+	PollID           string    `bun:"poll_id" json:"poll_id"`
+	OptionID         string    `bun:"option_id" json:"option_id"`
+	UserID           string    `bun:"user_id" json:"user_id"`
+	IsMultipleChoice bool      `bun:"is_multiple_choice" json:"is_multiple_choice"`
+	CreatedBy        string    `bun:"created_by" json:"created_by"`
+	CreatedAt        time.Time `bun:",nullzero,default:now()" json:"created_at"`
+}
@@ -12,6 +12,7 @@ type University struct {
 	Name            string       `bun:"name" json:"name"`
 	AbbreviatedName string       `bun:"abbreviated_name" json:"abbreviated_name"`
 	ImageURL        string       `bun:"image_url" json:"image_url"`
+	SearchVector    string       `bun:"search_vector,scanonly" json:"-"`
 	CreatedBy       string       `bun:"created_by" json:"created_by"`
 	CreatedAt       time.Time    `bun:",nullzero,default:now()" json:"created_at"`
 	UpdatedBy       *string      `json:"updated_by"`
@@ -38,6 +39,8 @@ type UniversityRating struct {
 	PriceToValueRating        int                      `bun:"price_to_value_rating" json:"price_to_value_rating"`
 	OverallRating             float64                  `bun:"overall_rating" json:"overall_rating"`
 	UniversityRatingPoints    []UniversityRatingPoints `bun:"rel:has-many,join:id=university_rating_id" json:"university_rating_points"`
+	SearchRank                float64                  `bun:"search_rank,scanonly" json:"-"`
+	SearchVector              string                   `bun:"search_vector,scanonly" json:"-"`
 	CreatedBy                 string                   `bun:"created_by" json:"created_by"`
 	CreatedAt                 time.Time                `bun:",nullzero,default:now()" json:"created_at"`
 	UpdatedBy                 *string                  `bun:"updated_by" json:"updated_by"`
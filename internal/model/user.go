@@ -8,25 +8,36 @@ import (
 type User struct {
 	bun.BaseModel `bun:"table:user,alias:u"`
 
-	ID                 string        `bun:",pk" json:"id"`
-	Username           string        `bun:"username" json:"username"`
-	Email              string        `bun:"email" json:"email"`
-	Password           string        `bun:"password" json:"password"`
-	Role               string        `bun:"role" json:"role"`
-	UniversityID       *string       `bun:"university_id" json:"university_id"`
-	University         *University   `bun:"rel:belongs-to,join:university_id=id" json:"university"`
-	IsBanned           bool          `bun:"is_banned" json:"is_banned"`
-	IsEmailVerified    bool          `bun:"is_email_verified" json:"is_email_verified"`
-	HasRateUniversity  bool          `bun:"has_rate_university" json:"has_rate_university"`
-	ReputationPoints   int64         `bun:"reputation_points" json:"reputation_points"`
-	UniversityRatingID *string       `bun:"-" json:"university_rating_id"`
-	Devices            []*UserDevice `bun:"rel:has-many,join:id=user_id" json:"devices"`
-	CreatedBy          string        `bun:"created_by" json:"created_by"`
-	CreatedAt          time.Time     `bun:",nullzero,default:now()" json:"created_at"`
-	UpdatedBy          *string       `json:"updated_by"`
-	UpdatedAt          bun.NullTime  `json:"updated_at"`
-	DeletedBy          *string       `json:"-"`
-	DeletedAt          time.Time     `bun:",nullzero,soft_delete" json:"-"`
+	ID                  string        `bun:",pk" json:"id"`
+	Username            string        `bun:"username" json:"username"`
+	Email               string        `bun:"email" json:"email"`
+	Password            string        `bun:"password" json:"password"`
+	Role                string        `bun:"role" json:"role"`
+	UniversityID        *string       `bun:"university_id" json:"university_id"`
+	University          *University   `bun:"rel:belongs-to,join:university_id=id" json:"university"`
+	IsBanned            bool          `bun:"is_banned" json:"is_banned"`
+	IsEmailVerified     bool          `bun:"is_email_verified" json:"is_email_verified"`
+	HasRateUniversity   bool          `bun:"has_rate_university" json:"has_rate_university"`
+	ReputationPoints    int64         `bun:"reputation_points" json:"reputation_points"`
+	FollowersCount      int64         `bun:"followers_count" json:"followers_count"`
+	FollowingCount      int64         `bun:"following_count" json:"following_count"`
+	AvatarURL           *string       `bun:"avatar_url" json:"avatar_url"`
+	Bio                 *string       `bun:"bio" json:"bio"`
+	Major               *string       `bun:"major" json:"major"`
+	GraduationYear      *int          `bun:"graduation_year" json:"graduation_year"`
+	ProfileVisibility   string        `bun:"profile_visibility,nullzero,default:'PUBLIC'" json:"profile_visibility"`
+	HideThreads         bool          `bun:"hide_threads" json:"hide_threads"`
+	FailedLoginAttempts int           `bun:"failed_login_attempts" json:"-"`
+	LockedUntil         bun.NullTime  `bun:"locked_until" json:"-"`
+	UniversityRatingID  *string       `bun:"-" json:"university_rating_id"`
+	Devices             []*UserDevice `bun:"rel:has-many,join:id=user_id" json:"devices"`
+	Badges              []*UserBadge  `bun:"rel:has-many,join:id=user_id" json:"badges"`
+	CreatedBy           string        `bun:"created_by" json:"created_by"`
+	CreatedAt           time.Time     `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy           *string       `json:"updated_by"`
+	UpdatedAt           bun.NullTime  `json:"updated_at"`
+	DeletedBy           *string       `json:"-"`
+	DeletedAt           time.Time     `bun:",nullzero,soft_delete" json:"-"`
 }
 
 type UserVerifyCode struct {
@@ -64,3 +75,25 @@ type UserDevice struct {
 	DeletedBy            *string      `bun:"deleted_by" json:"-"`
 	DeletedAt            time.Time    `bun:",nullzero,soft_delete" json:"-"`
 }
+
+type UserSession struct {
+	bun.BaseModel `bun:"table:user_session,alias:us"`
+
+	ID           string       `bun:",pk" json:"id"`
+	UserID       string       `bun:"user_id" json:"user_id"`
+	RefreshToken string       `bun:"refresh_token" json:"-"`
+	UserAgent    *string      `bun:"user_agent" json:"user_agent"`
+	IPAddress    *string      `bun:"ip_address" json:"ip_address"`
+	ExpiredAt    time.Time    `bun:"expired_at,nullzero" json:"expired_at"`
+	LastUsedAt   bun.NullTime `bun:"last_used_at" json:"last_used_at"`
+	RevokedAt    bun.NullTime `bun:"revoked_at" json:"revoked_at"`
+	RevokedBy    *string      `bun:"revoked_by" json:"-"`
+	CreatedBy    string       `bun:"created_by" json:"created_by"`
+	CreatedAt    time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy    *string      `bun:"updated_by" json:"updated_by"`
+	UpdatedAt    bun.NullTime `bun:"updated_at" json:"updated_at"`
+}
+
+func (us *UserSession) IsActive() bool {
+	return us.RevokedAt.IsZero() && us.ExpiredAt.After(time.Now())
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// UserBlock hides the content and the notifications of BlockedUserID from UserID, Type is BLOCK or MUTE
+type UserBlock struct {
+	bun.BaseModel `bun:"table:user_block,alias:ubl"`
+
+	ID            string       `bun:",pk" json:"id"`
+	UserID        string       `bun:"user_id" json:"user_id"`
+	BlockedUserID string       `bun:"blocked_user_id" json:"blocked_user_id"`
+	BlockedUser   *User        `bun:"rel:belongs-to,join:blocked_user_id=id" json:"blocked_user,omitempty"`
+	Type          string       `bun:"type" json:"type"`
+	CreatedBy     string       `bun:"created_by" json:"created_by"`
+	CreatedAt     time.Time    `bun:",nullzero,default:now()" json:"created_at"`
+	UpdatedBy     *string      `json:"updated_by"`
+	UpdatedAt     bun.NullTime `json:"updated_at"`
+}
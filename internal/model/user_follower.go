@@ -0,0 +1,19 @@
+package model
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// UserFollower is FollowerID following UserID
+type UserFollower struct {
+	bun.BaseModel `bun:"table:user_follower,alias:uf"`
+
+	ID         string    `bun:",pk" json:"id"`
+	UserID     string    `bun:"user_id" json:"user_id"`
+	User       *User     `bun:"rel:belongs-to,join:user_id=id" json:"user,omitempty"`
+	FollowerID string    `bun:"follower_id" json:"follower_id"`
+	Follower   *User     `bun:"rel:belongs-to,join:follower_id=id" json:"follower,omitempty"`
+	CreatedBy  string    `bun:"created_by" json:"created_by"`
+	CreatedAt  time.Time `bun:",nullzero,default:now()" json:"created_at"`
+}
@@ -0,0 +1,170 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/worker"
+	"github.com/andibalo/meowhasiswa-be/pkg/integration/notifsvc"
+	"github.com/andibalo/meowhasiswa-be/pkg/mailer"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"time"
+)
+
+// claimLease is how long a claimed message stays invisible to other dispatchers before it is retried
+const claimLease = time.Minute
+
+// errNonRetryable marks failures that will never succeed on retry, the message is dead-lettered right away
+var errNonRetryable = errors.New("non retryable")
+
+// Dispatcher delivers the messages written to the outbox table by the services
+type Dispatcher struct {
+	cfg              config.Config
+	outboxRepo       repository.OutboxRepository
+	notificationRepo repository.NotificationRepository
+	followerRepo     repository.UserFollowerRepository
+	notifCl          notifsvc.INotifSvc
+	mailerSvc        mailer.MailService
+	db               *bun.DB
+}
+
+// NewDispatcher returns the worker polling the outbox. When it is shut down the in-flight sends are cancelled,
+// their messages are retried after the claim lease expires
+func NewDispatcher(cfg config.Config, outboxRepo repository.OutboxRepository, notificationRepo repository.NotificationRepository, followerRepo repository.UserFollowerRepository, notifCl notifsvc.INotifSvc, mailerSvc mailer.MailService, db *bun.DB) *worker.Worker {
+
+	d := &Dispatcher{
+		cfg:              cfg,
+		outboxRepo:       outboxRepo,
+		notificationRepo: notificationRepo,
+		followerRepo:     followerRepo,
+		notifCl:          notifCl,
+		mailerSvc:        mailerSvc,
+		db:               db,
+	}
+
+	return worker.New("outbox dispatcher", time.Duration(cfg.GetOutboxCfg().PollIntervalSecs)*time.Second, d.dispatchPending)
+}
+
+func (d *Dispatcher) dispatchPending(ctx context.Context, stopping <-chan struct{}) {
+	batchSize := d.cfg.GetOutboxCfg().BatchSize
+
+	for {
+		outboxMessages, err := d.outboxRepo.ClaimPendingBatch(batchSize, claimLease)
+		if err != nil {
+			d.cfg.Logger().ErrorWithContext(ctx, "[Dispatcher.dispatchPending] Failed to claim outbox messages", zap.Error(err))
+			return
+		}
+
+		for _, om := range outboxMessages {
+			d.dispatch(ctx, om)
+		}
+
+		if len(outboxMessages) < batchSize || worker.IsStopping(stopping) {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, om model.OutboxMessage) {
+
+	err := d.send(ctx, om)
+	if err == nil {
+		updateValues := map[string]interface{}{
+			"status":       constants.OUTBOX_STATUS_SENT,
+			"last_error":   nil,
+			"processed_at": time.Now(),
+			"updated_at":   time.Now(),
+		}
+
+		err = d.outboxRepo.UpdateByID(om.ID, updateValues)
+		if err != nil {
+			d.cfg.Logger().ErrorWithContext(ctx, "[Dispatcher.dispatch] Failed to mark outbox message as sent", zap.String("outbox_id", om.ID), zap.Error(err))
+		}
+
+		return
+	}
+
+	// Cancelled by a forced shutdown, the claim lease takes care of the retry
+	if ctx.Err() != nil {
+		return
+	}
+
+	updateValues := map[string]interface{}{
+		"last_error": err.Error(),
+		"updated_at": time.Now(),
+	}
+
+	if errors.Is(err, errNonRetryable) || om.Attempts >= d.cfg.GetOutboxCfg().MaxAttempts {
+		d.cfg.Logger().ErrorWithContext(ctx, "[Dispatcher.dispatch] Outbox message dead-lettered", zap.String("outbox_id", om.ID), zap.String("type", om.Type), zap.Int("attempts", om.Attempts), zap.Error(err))
+
+		updateValues["status"] = constants.OUTBOX_STATUS_DEAD
+		updateValues["processed_at"] = time.Now()
+	} else {
+		d.cfg.Logger().WarnWithContext(ctx, "[Dispatcher.dispatch] Failed to send outbox message, retrying later", zap.String("outbox_id", om.ID), zap.String("type", om.Type), zap.Int("attempts", om.Attempts), zap.Error(err))
+
+		updateValues["next_attempt_at"] = time.Now().Add(d.backoff(om.Attempts))
+	}
+
+	err = d.outboxRepo.UpdateByID(om.ID, updateValues)
+	if err != nil {
+		d.cfg.Logger().ErrorWithContext(ctx, "[Dispatcher.dispatch] Failed to update outbox message", zap.String("outbox_id", om.ID), zap.Error(err))
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, om model.OutboxMessage) error {
+
+	switch om.Type {
+	case constants.OUTBOX_TYPE_PUSH_NOTIFICATION:
+		var req notifsvc.SendPushNotificationReq
+
+		if err := json.Unmarshal(om.Payload, &req); err != nil {
+			return fmt.Errorf("%w: invalid push notification payload: %v", errNonRetryable, err)
+		}
+
+		_, err := d.notifCl.SendPushNotification(ctx, req)
+
+		return err
+	case constants.OUTBOX_TYPE_EMAIL:
+		var mail mailer.Mail
+
+		if err := json.Unmarshal(om.Payload, &mail); err != nil {
+			return fmt.Errorf("%w: invalid email payload: %v", errNonRetryable, err)
+		}
+
+		return d.mailerSvc.SendMail(ctx, mail)
+	case constants.OUTBOX_TYPE_FOLLOWER_FAN_OUT:
+		var payload model.FollowerFanOutPayload
+
+		if err := json.Unmarshal(om.Payload, &payload); err != nil {
+			return fmt.Errorf("%w: invalid follower fan out payload: %v", errNonRetryable, err)
+		}
+
+		return d.fanOutFollowers(ctx, om.ID, payload, om.CreatedBy)
+	default:
+		return fmt.Errorf("%w: unknown outbox message type %s", errNonRetryable, om.Type)
+	}
+}
+
+// backoff doubles the base delay on every attempt, capped at the configured maximum
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	outboxCfg := d.cfg.GetOutboxCfg()
+
+	delay := time.Duration(outboxCfg.BaseBackoffSecs) * time.Second
+	maxDelay := time.Duration(outboxCfg.MaxBackoffSecs) * time.Second
+
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,155 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/pkg/integration/notifsvc"
+	"github.com/google/uuid"
+	"time"
+)
+
+// fanOutFollowers notifies the followers of a new thread page by page. Each page saves its notifications, queues its
+// push notifications and moves the cursor of the message forward in one transaction, a retry resumes after the last
+// saved page
+func (d *Dispatcher) fanOutFollowers(ctx context.Context, outboxID string, payload model.FollowerFanOutPayload, createdBy string) error {
+	pageSize := d.cfg.GetOutboxCfg().FanOutPageSize
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		followers, err := d.followerRepo.GetNotifiedFollowers(payload.AuthorID, payload.UniversityID, payload.Cursor, pageSize)
+		if err != nil {
+			return err
+		}
+
+		if len(followers) == 0 {
+			return nil
+		}
+
+		payload.Cursor = followers[len(followers)-1].ID
+
+		err = d.saveFanOutPage(outboxID, payload, followers, createdBy)
+		if err != nil {
+			return err
+		}
+
+		if len(followers) < pageSize {
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) saveFanOutPage(outboxID string, payload model.FollowerFanOutPayload, followers []model.UserFollower, createdBy string) error {
+
+	route := "/thread/" + payload.ThreadID
+	notifications := []model.Notification{}
+	notificationTokens := []string{}
+
+	for _, f := range followers {
+		notifications = append(notifications, model.Notification{
+			ID:      uuid.NewString(),
+			UserID:  f.FollowerID,
+			ActorID: &payload.AuthorID,
+			Type:    constants.FOLLOWED_USER_POSTED_EVENT,
+			Title:   payload.Title,
+			Content: payload.Content,
+			Data: map[string]string{
+				constants.APP_ROUTE_KEY:  route,
+				constants.EVENT_TYPE_KEY: constants.FOLLOWED_USER_POSTED_EVENT,
+			},
+			CreatedBy: createdBy,
+		})
+
+		if f.Follower == nil {
+			continue
+		}
+
+		for _, ud := range f.Follower.Devices {
+			if ud.IsNotificationActive {
+				notificationTokens = append(notificationTokens, ud.NotificationToken)
+			}
+		}
+	}
+
+	pushMessages, err := d.newPushMessages(notificationTokens, payload, route, createdBy)
+	if err != nil {
+		return err
+	}
+
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = d.notificationRepo.BulkSaveTx(notifications, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, pm := range pushMessages {
+		err = d.outboxRepo.SaveTx(pm, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// Every saved page renews the claim lease so a long fan out is not claimed by another dispatcher meanwhile
+	updateValues := map[string]interface{}{
+		"payload":         string(rawPayload),
+		"next_attempt_at": time.Now().Add(claimLease),
+		"updated_at":      time.Now(),
+	}
+
+	err = d.outboxRepo.UpdateByIDTx(outboxID, updateValues, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// newPushMessages splits the tokens into push notification messages of at most PushBatchSize tokens each
+func (d *Dispatcher) newPushMessages(notificationTokens []string, payload model.FollowerFanOutPayload, route string, createdBy string) ([]*model.OutboxMessage, error) {
+	batchSize := d.cfg.GetOutboxCfg().PushBatchSize
+
+	pushMessages := []*model.OutboxMessage{}
+
+	for start := 0; start < len(notificationTokens); start += batchSize {
+		end := min(start+batchSize, len(notificationTokens))
+
+		rawPayload, err := json.Marshal(notifsvc.SendPushNotificationReq{
+			NotificationTokens: notificationTokens[start:end],
+			Title:              payload.Title,
+			Content:            payload.Content,
+			Data: map[string]string{
+				constants.APP_ROUTE_KEY:  route,
+				constants.EVENT_TYPE_KEY: constants.FOLLOWED_USER_POSTED_EVENT,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pushMessages = append(pushMessages, &model.OutboxMessage{
+			ID:        uuid.NewString(),
+			Type:      constants.OUTBOX_TYPE_PUSH_NOTIFICATION,
+			Payload:   rawPayload,
+			Status:    constants.OUTBOX_STATUS_PENDING,
+			CreatedBy: createdBy,
+		})
+	}
+
+	return pushMessages, nil
+}
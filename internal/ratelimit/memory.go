@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens     float64
+	ratePerSec float64
+	burst      float64
+	updatedAt  time.Time
+}
+
+func (b *bucket) refill(now time.Time) float64 {
+	return math.Min(b.burst, b.tokens+now.Sub(b.updatedAt).Seconds()*b.ratePerSec)
+}
+
+// memoryStore keeps the buckets in the process, every instance enforces its own limits
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryStore) Take(ctx context.Context, key string, ratePerSec float64, burst int) (bool, time.Duration, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{
+			tokens:    float64(burst),
+			updatedAt: now,
+		}
+
+		s.buckets[key] = b
+	}
+
+	b.ratePerSec = ratePerSec
+	b.burst = float64(burst)
+	b.tokens = b.refill(now)
+	b.updatedAt = now
+
+	if b.tokens < 1 {
+		return false, retryAfter(b.tokens, ratePerSec), nil
+	}
+
+	b.tokens--
+
+	return true, 0, nil
+}
+
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range s.buckets {
+		if b.refill(now) >= b.burst {
+			delete(s.buckets, key)
+		}
+	}
+
+	s.lastSweep = now
+}
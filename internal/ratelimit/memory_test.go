@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	tests := []struct {
+		name        string
+		ratePerSec  float64
+		burst       int
+		takes       int
+		elapsed     time.Duration
+		wantAllowed bool
+	}{
+		{
+			name:        "first request of a new bucket is allowed",
+			ratePerSec:  1,
+			burst:       1,
+			takes:       0,
+			wantAllowed: true,
+		},
+		{
+			name:        "requests up to the burst are allowed",
+			ratePerSec:  1,
+			burst:       5,
+			takes:       4,
+			wantAllowed: true,
+		},
+		{
+			name:        "request over the burst is rejected",
+			ratePerSec:  1,
+			burst:       5,
+			takes:       5,
+			wantAllowed: false,
+		},
+		{
+			name:        "bucket refills over time",
+			ratePerSec:  1,
+			burst:       5,
+			takes:       5,
+			elapsed:     time.Second,
+			wantAllowed: true,
+		},
+		{
+			name:        "partial refill is not enough for a token",
+			ratePerSec:  1,
+			burst:       5,
+			takes:       5,
+			elapsed:     500 * time.Millisecond,
+			wantAllowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMemoryStore().(*memoryStore)
+
+			for i := 0; i < tt.takes; i++ {
+				_, _, err := s.Take(context.Background(), "key", tt.ratePerSec, tt.burst)
+				if err != nil {
+					t.Fatalf("Take() error = %v", err)
+				}
+			}
+
+			if b, ok := s.buckets["key"]; ok {
+				b.updatedAt = b.updatedAt.Add(-tt.elapsed)
+			}
+
+			allowed, wait, err := s.Take(context.Background(), "key", tt.ratePerSec, tt.burst)
+			if err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+
+			if allowed != tt.wantAllowed {
+				t.Errorf("Take() allowed = %v, want %v", allowed, tt.wantAllowed)
+			}
+
+			if allowed && wait != 0 {
+				t.Errorf("Take() wait = %v, want 0 when allowed", wait)
+			}
+
+			if !allowed && wait <= 0 {
+				t.Errorf("Take() wait = %v, want a positive wait when rejected", wait)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTakeSeparatesKeys(t *testing.T) {
+	s := NewMemoryStore()
+
+	allowed, _, _ := s.Take(context.Background(), "a", 1, 1)
+	if !allowed {
+		t.Fatalf("Take(a) allowed = false, want true")
+	}
+
+	allowed, _, _ = s.Take(context.Background(), "b", 1, 1)
+	if !allowed {
+		t.Errorf("Take(b) allowed = false, want true since b has its own bucket")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokens     float64
+		ratePerSec float64
+		want       time.Duration
+	}{
+		{
+			name:       "empty bucket waits a full token",
+			tokens:     0,
+			ratePerSec: 1,
+			want:       time.Second,
+		},
+		{
+			name:       "half a token waits half the refill",
+			tokens:     0.5,
+			ratePerSec: 1,
+			want:       500 * time.Millisecond,
+		},
+		{
+			name:       "faster rate waits less",
+			tokens:     0,
+			ratePerSec: 4,
+			want:       250 * time.Millisecond,
+		},
+		{
+			name:       "slower rate waits more",
+			tokens:     0,
+			ratePerSec: 0.5,
+			want:       2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.tokens, tt.ratePerSec); got != tt.want {
+				t.Errorf("retryAfter(%v, %v) = %v, want %v", tt.tokens, tt.ratePerSec, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// availableTokensExpr is the tokens of an existing bucket after refilling it up to the current time
+const availableTokensExpr = "LEAST(EXCLUDED.burst, rlb.tokens + EXTRACT(EPOCH FROM now() - rlb.updated_at) * EXCLUDED.rate_per_sec)"
+
+var takeQuery = fmt.Sprintf(`insert into rate_limit_bucket as rlb (key, tokens, rate_per_sec, burst, is_allowed, updated_at)
+	values (?, ? - 1, ?, ?, true, now())
+	on conflict (key) do update set
+		tokens = %[1]s - case when %[1]s >= 1 then 1 else 0 end,
+		rate_per_sec = EXCLUDED.rate_per_sec,
+		burst = EXCLUDED.burst,
+		is_allowed = %[1]s >= 1,
+		updated_at = now()
+	returning tokens, is_allowed`, availableTokensExpr)
+
+// postgresStore keeps the buckets in the rate_limit_bucket table so the limits are shared by every instance
+type postgresStore struct {
+	cfg config.Config
+	db  *bun.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(cfg config.Config, db *bun.DB) Store {
+	return &postgresStore{
+		cfg:       cfg,
+		db:        db,
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *postgresStore) Take(ctx context.Context, key string, ratePerSec float64, burst int) (bool, time.Duration, error) {
+	var result struct {
+		Tokens    float64 `bun:"tokens"`
+		IsAllowed bool    `bun:"is_allowed"`
+	}
+
+	// The bucket is refilled and taken from in a single statement so concurrent requests can not overdraw it
+	err := s.db.NewRaw(takeQuery, key, float64(burst), ratePerSec, float64(burst)).Scan(ctx, &result)
+	if err != nil {
+		return false, 0, err
+	}
+
+	s.sweep(ctx)
+
+	if !result.IsAllowed {
+		return false, retryAfter(result.Tokens, ratePerSec), nil
+	}
+
+	return true, 0, nil
+}
+
+func (s *postgresStore) sweep(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = time.Now()
+	s.mu.Unlock()
+
+	_, err := s.db.NewRaw(`delete from rate_limit_bucket
+		where updated_at + make_interval(secs => (burst - tokens) / rate_per_sec) < now()`).
+		Exec(ctx)
+	if err != nil {
+		s.cfg.Logger().WarnWithContext(ctx, "[postgresStore.sweep] Failed to delete refilled rate limit buckets", zap.Error(err))
+	}
+}
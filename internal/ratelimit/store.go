@@ -0,0 +1,22 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// sweepInterval is how often the stores drop the buckets that refilled completely,
+// a full bucket behaves exactly like a bucket that was never created
+const sweepInterval = time.Minute
+
+// Store keeps the token buckets the rate limits are enforced with
+type Store interface {
+	// Take removes a token from the bucket identified by key, which refills at ratePerSec up to burst tokens.
+	// When the bucket is empty the request is not allowed and the returned duration is how long until a token is available
+	Take(ctx context.Context, key string, ratePerSec float64, burst int) (bool, time.Duration, error)
+}
+
+// retryAfter is how long the bucket takes to refill from tokens to a single token
+func retryAfter(tokens float64, ratePerSec float64) time.Duration {
+	return time.Duration((1 - tokens) / ratePerSec * float64(time.Second))
+}
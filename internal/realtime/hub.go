@@ -0,0 +1,212 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// notifyChannel is the Postgres channel thread events are fanned out on between instances
+const notifyChannel = "thread_events"
+
+// subscriberBufferSize is how many events a slow subscriber can lag behind before events are dropped for it
+const subscriberBufferSize = 32
+
+// Event is a change of a thread pushed to the clients streaming that thread
+type Event struct {
+	Type     string      `json:"type"`
+	ThreadID string      `json:"thread_id"`
+	Data     interface{} `json:"data"`
+}
+
+type CommentCreatedData struct {
+	TargetType string      `json:"target_type"`
+	Comment    interface{} `json:"comment"`
+}
+
+type CommentEditedData struct {
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Content    string `json:"content"`
+}
+
+type VoteCountChangedData struct {
+	TargetType   string `json:"target_type"`
+	TargetID     string `json:"target_id"`
+	LikeCount    int64  `json:"like_count"`
+	DislikeCount int64  `json:"dislike_count"`
+}
+
+type notifyPayload struct {
+	Origin string          `json:"origin"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// Hub is an in-process pub/sub of thread events. Published events are delivered to the local
+// subscribers right away and relayed to the other instances through Postgres LISTEN/NOTIFY
+type Hub struct {
+	cfg        config.Config
+	db         *bun.DB
+	instanceID string
+
+	mu          sync.RWMutex
+	subscribers map[string]map[chan []byte]struct{}
+
+	listener *pgdriver.Listener
+	done     chan struct{}
+}
+
+func NewHub(cfg config.Config, db *bun.DB) *Hub {
+	return &Hub{
+		cfg:         cfg,
+		db:          db,
+		instanceID:  uuid.NewString(),
+		subscribers: make(map[string]map[chan []byte]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start listens for the events published by the other instances in a background goroutine
+func (h *Hub) Start() {
+	h.listener = pgdriver.NewListener(h.db)
+
+	if err := h.listener.Listen(context.Background(), notifyChannel); err != nil {
+		h.cfg.Logger().Error("[Hub.Start] Failed to listen to thread events channel", zap.Error(err))
+	}
+
+	go h.run(h.listener.Channel())
+}
+
+// Shutdown stops listening and closes every subscription
+func (h *Hub) Shutdown() error {
+	if h.listener == nil {
+		return nil
+	}
+
+	err := h.listener.Close()
+
+	<-h.done
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for threadID, subs := range h.subscribers {
+		for sub := range subs {
+			close(sub)
+		}
+
+		delete(h.subscribers, threadID)
+	}
+
+	return err
+}
+
+func (h *Hub) run(notifications <-chan pgdriver.Notification) {
+	defer close(h.done)
+
+	for n := range notifications {
+		var payload notifyPayload
+
+		if err := json.Unmarshal([]byte(n.Payload), &payload); err != nil {
+			h.cfg.Logger().Error("[Hub.run] Failed to unmarshal thread event", zap.Error(err))
+			continue
+		}
+
+		// Events of this instance were already delivered when they were published
+		if payload.Origin == h.instanceID {
+			continue
+		}
+
+		var event Event
+
+		if err := json.Unmarshal(payload.Event, &event); err != nil {
+			h.cfg.Logger().Error("[Hub.run] Failed to unmarshal thread event", zap.Error(err))
+			continue
+		}
+
+		h.broadcast(event.ThreadID, payload.Event)
+	}
+}
+
+// Subscribe returns the stream of encoded events of a thread, the returned func must be called to unsubscribe
+func (h *Hub) Subscribe(threadID string) (<-chan []byte, func()) {
+	sub := make(chan []byte, subscriberBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[threadID] == nil {
+		h.subscribers[threadID] = make(map[chan []byte]struct{})
+	}
+	h.subscribers[threadID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			subs, ok := h.subscribers[threadID]
+			if !ok {
+				return
+			}
+
+			if _, ok := subs[sub]; !ok {
+				return
+			}
+
+			delete(subs, sub)
+			close(sub)
+
+			if len(subs) == 0 {
+				delete(h.subscribers, threadID)
+			}
+		})
+	}
+
+	return sub, unsubscribe
+}
+
+// Publish delivers the event to the local subscribers and relays it to the other instances,
+// failures are only logged as the change itself is already committed
+func (h *Hub) Publish(ctx context.Context, event Event) {
+	rawEvent, err := json.Marshal(event)
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(ctx, "[Hub.Publish] Failed to marshal thread event", zap.Error(err))
+		return
+	}
+
+	h.broadcast(event.ThreadID, rawEvent)
+
+	rawPayload, err := json.Marshal(notifyPayload{
+		Origin: h.instanceID,
+		Event:  rawEvent,
+	})
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(ctx, "[Hub.Publish] Failed to marshal thread event payload", zap.Error(err))
+		return
+	}
+
+	err = pgdriver.Notify(ctx, h.db, notifyChannel, string(rawPayload))
+	if err != nil {
+		h.cfg.Logger().ErrorWithContext(ctx, "[Hub.Publish] Failed to notify thread event", zap.String("type", event.Type), zap.Error(err))
+	}
+}
+
+func (h *Hub) broadcast(threadID string, rawEvent []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers[threadID] {
+		select {
+		case sub <- rawEvent:
+		default:
+			h.cfg.Logger().Warn("[Hub.broadcast] Subscriber is lagging behind, thread event dropped", zap.String("thread_id", threadID))
+		}
+	}
+}
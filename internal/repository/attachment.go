@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"time"
+)
+
+type attachmentRepository struct {
+	db *bun.DB
+}
+
+func NewAttachmentRepository(db *bun.DB) AttachmentRepository {
+	return &attachmentRepository{
+		db: db,
+	}
+}
+
+func (r *attachmentRepository) Save(attachment *model.Attachment) error {
+
+	_, err := r.db.NewInsert().Model(attachment).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *attachmentRepository) SaveTx(attachment *model.Attachment, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(attachment).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *attachmentRepository) GetByID(id string) (*model.Attachment, error) {
+
+	attachment := &model.Attachment{}
+
+	err := r.db.NewSelect().
+		Model(attachment).
+		Where("id = ?", id).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// DetachByTargetTx releases the attachments of the target, they are garbage collected like any unattached upload
+func (r *attachmentRepository) DetachByTargetTx(targetType string, targetID string, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model((*model.Attachment)(nil)).
+		Set("target_type = NULL").
+		Set("target_id = NULL").
+		Set("attached_at = NULL").
+		Where("target_type = ?", targetType).
+		Where("target_id = ?", targetID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AttachTx links the unattached uploads of the user to the target, ordered as given in ids.
+// It returns the ids that were attached so the caller can detect foreign or already attached uploads
+func (r *attachmentRepository) AttachTx(ids []string, userID string, targetType string, targetID string, tx bun.Tx) ([]string, error) {
+
+	var attachedIDs []string
+
+	_, err := tx.NewRaw(`update
+							attachment
+						set
+							target_type = ?,
+							target_id = ?,
+							position = array_position(?::uuid[], id) - 1,
+							attached_at = now()
+						where
+							id IN (?)
+							and user_id = ?
+							and target_id is null
+						returning id`, targetType, targetID, pgdialect.Array(ids), bun.In(ids), userID).
+		Exec(context.Background(), &attachedIDs)
+	if err != nil {
+		return attachedIDs, err
+	}
+
+	return attachedIDs, nil
+}
+
+func (r *attachmentRepository) GetByTargets(targetType string, targetIDs []string) ([]model.Attachment, error) {
+
+	var attachments = []model.Attachment{}
+
+	if len(targetIDs) == 0 {
+		return attachments, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&attachments).
+		Where("att.target_type = ?", targetType).
+		Where("att.target_id IN (?)", bun.In(targetIDs)).
+		Order("att.target_id ASC", "att.position ASC").
+		Scan(context.Background())
+	if err != nil {
+		return attachments, err
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepository) GetUnattachedCreatedBefore(createdBefore time.Time, limit int) ([]model.Attachment, error) {
+
+	var attachments = []model.Attachment{}
+
+	err := r.db.NewSelect().
+		Model(&attachments).
+		Where("att.target_id IS NULL").
+		Where("att.created_at < ?", createdBefore).
+		Order("att.created_at ASC").
+		Limit(limit).
+		Scan(context.Background())
+	if err != nil {
+		return attachments, err
+	}
+
+	return attachments, nil
+}
+
+// DeleteUnattachedByID deletes the upload unless it was attached in the meantime, it reports whether the row was deleted
+func (r *attachmentRepository) DeleteUnattachedByID(id string) (bool, error) {
+
+	res, err := r.db.NewDelete().
+		Model((*model.Attachment)(nil)).
+		Where("id = ?", id).
+		Where("target_id IS NULL").
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// LockContentHashTx serialises the uploads and the deletes of the files of a content hash until tx ends
+func (r *attachmentRepository) LockContentHashTx(contentHash string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("SELECT pg_advisory_xact_lock(hashtext(?))", contentHash).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *attachmentRepository) CountByContentHashTx(contentHash string, tx bun.Tx) (int, error) {
+
+	count, err := tx.NewSelect().
+		Model((*model.Attachment)(nil)).
+		Where("content_hash = ?", contentHash).
+		Count(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
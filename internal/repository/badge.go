@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type badgeRepository struct {
+	db *bun.DB
+}
+
+func NewBadgeRepository(db *bun.DB) BadgeRepository {
+	return &badgeRepository{
+		db: db,
+	}
+}
+
+func (r *badgeRepository) GetByCode(code string) (*model.Badge, error) {
+	badge := &model.Badge{}
+
+	err := r.db.NewSelect().
+		Model(badge).
+		Where("code = ?", code).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return badge, nil
+}
+
+// AwardTx gives the badge identified by badgeCode to the user, it returns false when the user already had it
+func (r *badgeRepository) AwardTx(userBadge *model.UserBadge, badgeCode string, tx bun.Tx) (bool, error) {
+
+	res, err := tx.NewRaw(`insert into user_badge (id, user_id, badge_id, scope_type, scope_id, created_by)
+								select ?, ?, b.id, ?, ?, ?
+								from badge b
+								where b.code = ?
+								on conflict do nothing`, userBadge.ID, userBadge.UserID, userBadge.ScopeType, userBadge.ScopeID, userBadge.CreatedBy, badgeCode).
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *badgeRepository) GetUserBadgesByUserIDs(userIDs []string) ([]*model.UserBadge, error) {
+	userBadges := []*model.UserBadge{}
+
+	if len(userIDs) == 0 {
+		return userBadges, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&userBadges).
+		Relation("Badge").
+		Where("ub.user_id IN (?)", bun.In(userIDs)).
+		Order("ub.created_at asc", "ub.id asc").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return userBadges, nil
+}
+
+// CountLikesReceivedTx sums the likes of every thread, comment and reply of the user that is not deleted
+func (r *badgeRepository) CountLikesReceivedTx(userID string, tx bun.Tx) (int64, error) {
+
+	var likesReceived int64
+
+	err := tx.NewRaw(`select coalesce(sum(l.like_count), 0)
+								from (
+									select like_count from thread where user_id = ? and deleted_at is null
+									union all
+									select like_count from thread_comment where user_id = ? and deleted_at is null
+									union all
+									select like_count from thread_comment_reply where user_id = ? and deleted_at is null
+								) l`, userID, userID, userID).
+		Scan(context.Background(), &likesReceived)
+	if err != nil {
+		return 0, err
+	}
+
+	return likesReceived, nil
+}
+
+// GetSubThreadThreadCountsTx returns the number of threads the user posted in the subthread
+// along with the highest number of threads posted there by a single user
+func (r *badgeRepository) GetSubThreadThreadCountsTx(subThreadID string, userID string, tx bun.Tx) (int, int, error) {
+
+	var counts struct {
+		UserCount int `bun:"user_count"`
+		TopCount  int `bun:"top_count"`
+	}
+
+	err := tx.NewRaw(`select
+									coalesce(sum(c.thread_count) filter (where c.user_id = ?), 0) as user_count,
+									coalesce(max(c.thread_count), 0) as top_count
+								from (
+									select user_id, count(*) as thread_count
+									from thread
+									where subthread_id = ? and deleted_at is null
+									group by user_id
+								) c`, userID, subThreadID).
+		Scan(context.Background(), &counts)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return counts.UserCount, counts.TopCount, nil
+}
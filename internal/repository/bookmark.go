@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type bookmarkRepository struct {
+	db *bun.DB
+}
+
+func NewBookmarkRepository(db *bun.DB) BookmarkRepository {
+	return &bookmarkRepository{
+		db: db,
+	}
+}
+
+func (r *bookmarkRepository) Save(bookmark *model.Bookmark) error {
+
+	_, err := r.db.NewInsert().
+		Model(bookmark).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *bookmarkRepository) UpdateByID(bookmarkID string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("bookmark").
+		Where("id = ?", bookmarkID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *bookmarkRepository) DeleteByTarget(userID string, targetType string, targetID string) (bool, error) {
+
+	res, err := r.db.NewDelete().
+		TableExpr("bookmark").
+		Where("user_id = ?", userID).
+		Where("target_type = ?", targetType).
+		Where("target_id = ?", targetID).
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *bookmarkRepository) GetByTarget(userID string, targetType string, targetID string) (model.Bookmark, error) {
+
+	var (
+		bookmark model.Bookmark
+	)
+
+	err := r.db.NewSelect().
+		Model(&bookmark).
+		Where("bm.user_id = ?", userID).
+		Where("bm.target_type = ?", targetType).
+		Where("bm.target_id = ?", targetID).
+		Scan(context.Background())
+	if err != nil {
+		return bookmark, err
+	}
+
+	return bookmark, nil
+}
+
+func (r *bookmarkRepository) GetList(req request.GetBookmarkListReq) ([]model.Bookmark, pkg.Pagination, error) {
+
+	var (
+		bookmarks  = []model.Bookmark{}
+		nextCursor string
+	)
+
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
+		Model(&bookmarks).
+		Relation("Thread").
+		Relation("Thread.User", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username")
+		}).
+		Relation("Thread.User.University").
+		Relation("Thread.SubThread", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "name", "label_color")
+		}).
+		Where("bm.user_id = ?", req.UserID).
+		Where("EXISTS (SELECT 1 FROM thread AS bth WHERE bth.id = bm.thread_id AND bth.deleted_at IS NULL)").
+		Limit(req.Limit + 1)
+
+	if req.CollectionID != "" {
+		query.Where("bm.collection_id = ?", req.CollectionID)
+	}
+
+	if req.TargetType != "" {
+		query.Where("bm.target_type = ?", req.TargetType)
+	}
+
+	if req.Cursor != "" {
+		createdAt, bookmarkID := pkg.GetCursorData(req.Cursor)
+
+		query.Where("(bm.created_at, bm.id) <= (?, ?)", createdAt, bookmarkID)
+	}
+
+	query.Order("bm.created_at desc", "bm.id desc")
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return bookmarks, pagination, err
+	}
+
+	if len(bookmarks) > req.Limit {
+		lastBookmark := bookmarks[len(bookmarks)-1]
+
+		nextCursor = fmt.Sprintf("%s_%s", lastBookmark.CreatedAt.Format(time.RFC3339Nano), lastBookmark.ID)
+
+		bookmarks = bookmarks[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return bookmarks, pagination, nil
+}
+
+func (r *bookmarkRepository) SaveCollection(collection *model.BookmarkCollection) error {
+
+	_, err := r.db.NewInsert().
+		Model(collection).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *bookmarkRepository) UpdateCollectionByID(collectionID string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("bookmark_collection").
+		Where("id = ?", collectionID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCollectionByIDTx soft deletes the collection, its bookmarks are kept in the general saved list
+func (r *bookmarkRepository) DeleteCollectionByIDTx(collectionID string, deletedBy string, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		TableExpr("bookmark").
+		Set("collection_id = NULL").
+		Set("updated_by = ?", deletedBy).
+		Set("updated_at = NOW()").
+		Where("collection_id = ?", collectionID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewUpdate().
+		TableExpr("bookmark_collection").
+		Set("deleted_by = ?", deletedBy).
+		Set("deleted_at = NOW()").
+		Where("id = ?", collectionID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *bookmarkRepository) GetCollectionByID(userID string, collectionID string) (model.BookmarkCollection, error) {
+
+	var (
+		collection model.BookmarkCollection
+	)
+
+	err := r.db.NewSelect().
+		Model(&collection).
+		Where("bmc.id = ?", collectionID).
+		Where("bmc.user_id = ?", userID).
+		Scan(context.Background())
+	if err != nil {
+		return collection, err
+	}
+
+	return collection, nil
+}
+
+func (r *bookmarkRepository) GetCollectionByName(userID string, name string) (model.BookmarkCollection, error) {
+
+	var (
+		collection model.BookmarkCollection
+	)
+
+	err := r.db.NewSelect().
+		Model(&collection).
+		Where("bmc.user_id = ?", userID).
+		Where("LOWER(bmc.name) = LOWER(?)", name).
+		Scan(context.Background())
+	if err != nil {
+		return collection, err
+	}
+
+	return collection, nil
+}
+
+func (r *bookmarkRepository) GetCollections(userID string) ([]model.BookmarkCollection, error) {
+
+	var (
+		collections = []model.BookmarkCollection{}
+	)
+
+	err := r.db.NewSelect().
+		Model(&collections).
+		ColumnExpr("bmc.*").
+		ColumnExpr("(SELECT COUNT(*) FROM bookmark AS bm WHERE bm.collection_id = bmc.id) AS bookmark_count").
+		Where("bmc.user_id = ?", userID).
+		Order("bmc.name asc").
+		Scan(context.Background())
+	if err != nil {
+		return collections, err
+	}
+
+	return collections, nil
+}
+
+func (r *bookmarkRepository) CountCollections(userID string) (int, error) {
+
+	count, err := r.db.NewSelect().
+		Model((*model.BookmarkCollection)(nil)).
+		Where("bmc.user_id = ?", userID).
+		Count(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type imageUploadRepository struct {
+	db *bun.DB
+}
+
+func NewImageUploadRepository(db *bun.DB) ImageUploadRepository {
+	return &imageUploadRepository{
+		db: db,
+	}
+}
+
+func (r *imageUploadRepository) Save(imageUpload *model.ImageUpload) error {
+
+	_, err := r.db.NewInsert().Model(imageUpload).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *imageUploadRepository) GetByID(id string) (*model.ImageUpload, error) {
+
+	imageUpload := &model.ImageUpload{}
+
+	err := r.db.NewSelect().
+		Model(imageUpload).
+		Where("id = ?", id).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return imageUpload, nil
+}
+
+// ConfirmTx marks the pending upload as confirmed, it reports false when a concurrent request confirmed it first
+func (r *imageUploadRepository) ConfirmTx(id string, attachmentID string, tx bun.Tx) (bool, error) {
+
+	res, err := tx.NewUpdate().
+		Model((*model.ImageUpload)(nil)).
+		Set("status = ?", constants.IMAGE_UPLOAD_STATUS_CONFIRMED).
+		Set("attachment_id = ?", attachmentID).
+		Set("confirmed_at = now()").
+		Where("id = ?", id).
+		Where("status = ?", constants.IMAGE_UPLOAD_STATUS_PENDING).
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *imageUploadRepository) GetPendingExpiredBefore(expiredBefore time.Time, limit int) ([]model.ImageUpload, error) {
+
+	var imageUploads = []model.ImageUpload{}
+
+	err := r.db.NewSelect().
+		Model(&imageUploads).
+		Where("imu.status = ?", constants.IMAGE_UPLOAD_STATUS_PENDING).
+		Where("imu.expires_at < ?", expiredBefore).
+		Order("imu.expires_at ASC").
+		Limit(limit).
+		Scan(context.Background())
+	if err != nil {
+		return imageUploads, err
+	}
+
+	return imageUploads, nil
+}
+
+// DeletePendingByID deletes the upload unless it was confirmed in the meantime, it reports whether the row was deleted
+func (r *imageUploadRepository) DeletePendingByID(id string) (bool, error) {
+
+	res, err := r.db.NewDelete().
+		Model((*model.ImageUpload)(nil)).
+		Where("id = ?", id).
+		Where("status = ?", constants.IMAGE_UPLOAD_STATUS_PENDING).
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
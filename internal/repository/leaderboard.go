@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/uptrace/bun"
+)
+
+type leaderboardRepository struct {
+	db *bun.DB
+}
+
+func NewLeaderboardRepository(db *bun.DB) LeaderboardRepository {
+	return &leaderboardRepository{
+		db: db,
+	}
+}
+
+// GetList ranks the users with a public profile by the points they gained in the last days, all of the points are
+// counted when days is 0. It reads the leaderboard_score materialized view so it is as fresh as the last refresh
+func (r *leaderboardRepository) GetList(req request.GetLeaderboardReq, days int) ([]model.LeaderboardEntry, error) {
+
+	entries := []model.LeaderboardEntry{}
+
+	query := r.db.NewSelect().
+		TableExpr("leaderboard_score AS ls").
+		ColumnExpr("RANK() OVER (ORDER BY SUM(ls.points) DESC) AS rank").
+		ColumnExpr("u.id AS user_id, u.username, u.avatar_url, u.university_id").
+		ColumnExpr("uni.abbreviated_name AS university_abbreviated_name").
+		ColumnExpr("SUM(ls.points) AS points").
+		ColumnExpr("SUM(ls.like_count) AS like_count").
+		ColumnExpr("SUM(ls.dislike_count) AS dislike_count").
+		Join(`JOIN "user" AS u ON u.id = ls.user_id`).
+		Join("LEFT JOIN university AS uni ON uni.id = u.university_id").
+		Where("u.deleted_at IS NULL").
+		Where("u.is_banned = FALSE").
+		Where("u.profile_visibility = ?", constants.PROFILE_VISIBILITY_PUBLIC).
+		GroupExpr("u.id, uni.abbreviated_name").
+		Having("SUM(ls.points) > 0").
+		OrderExpr("points DESC, u.username ASC").
+		Limit(req.Limit)
+
+	if days > 0 {
+		query.Where("ls.day > CURRENT_DATE - ?::int", days)
+	}
+
+	if req.UniversityID != "" {
+		query.Where("u.university_id = ?", req.UniversityID)
+	}
+
+	if req.SubThreadID != "" {
+		query.Where("ls.subthread_id = ?", req.SubThreadID)
+	}
+
+	if !req.CanAccessAllUniversities {
+		query.Where("ls.subthread_id NOT IN (?)", closedUniversitySubThreadsQuery(r.db, req.UserUniversityID))
+	}
+
+	err := query.Scan(context.Background(), &entries)
+	if err != nil {
+		return entries, err
+	}
+
+	return entries, nil
+}
+
+func (r *leaderboardRepository) Refresh(ctx context.Context) error {
+
+	_, err := r.db.NewRaw("REFRESH MATERIALIZED VIEW CONCURRENTLY leaderboard_score").Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
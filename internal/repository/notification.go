@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type notificationRepository struct {
+	db *bun.DB
+}
+
+func NewNotificationRepository(db *bun.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) BulkSave(notifications []model.Notification) error {
+
+	_, err := r.db.NewInsert().
+		Model(&notifications).
+		On("CONFLICT DO NOTHING").
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) BulkSaveTx(notifications []model.Notification, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().
+		Model(&notifications).
+		On("CONFLICT DO NOTHING").
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) GetList(req request.GetNotificationListReq) ([]model.Notification, pkg.Pagination, error) {
+
+	var (
+		notifications = []model.Notification{}
+		nextCursor    string
+	)
+
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
+		Model(&notifications).
+		Where("n.user_id = ?", req.UserID).
+		Limit(req.Limit + 1)
+
+	if req.IsUnreadOnly {
+		query.Where("n.is_read = FALSE")
+	}
+
+	if req.Cursor != "" {
+		createdAt, notificationID := pkg.GetCursorData(req.Cursor)
+
+		query.Where("(n.created_at, n.id) <= (?, ?)", createdAt, notificationID)
+	}
+
+	query.Order("n.created_at desc", "n.id desc")
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return notifications, pagination, err
+	}
+
+	if len(notifications) > req.Limit {
+		lastNotification := notifications[len(notifications)-1]
+
+		nextCursor = fmt.Sprintf("%s_%s", lastNotification.CreatedAt.Format(time.RFC3339Nano), lastNotification.ID)
+
+		notifications = notifications[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return notifications, pagination, nil
+}
+
+func (r *notificationRepository) CountUnreadByUserID(userID string) (int, error) {
+
+	count, err := r.db.NewSelect().
+		Model((*model.Notification)(nil)).
+		Where("user_id = ?", userID).
+		Where("is_read = FALSE").
+		Count(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *notificationRepository) GetByIDAndUserID(id string, userID string) (*model.Notification, error) {
+	notification := &model.Notification{}
+
+	err := r.db.NewSelect().
+		Model(notification).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+func (r *notificationRepository) MarkAsRead(id string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("notification").
+		Where("id = ?", id).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) MarkAllAsRead(userID string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("notification").
+		Where("user_id = ?", userID).
+		Where("is_read = FALSE").
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type outboxRepository struct {
+	db *bun.DB
+}
+
+func NewOutboxRepository(db *bun.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (r *outboxRepository) SaveTx(outboxMessage *model.OutboxMessage, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(outboxMessage).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimPendingBatch locks up to limit due messages and pushes their next_attempt_at forward by lease,
+// so concurrent dispatchers skip them and a crashed dispatcher's messages are picked up again once the lease expires
+func (r *outboxRepository) ClaimPendingBatch(limit int, lease time.Duration) ([]model.OutboxMessage, error) {
+
+	var outboxMessages = []model.OutboxMessage{}
+
+	err := r.db.NewRaw(`update 
+								outbox
+							set
+								attempts = attempts + 1,
+								next_attempt_at = now() + make_interval(secs => ?),
+								updated_at = now()
+							where id in (
+								select id from outbox
+								where status = 'PENDING' and next_attempt_at <= now()
+								order by next_attempt_at
+								limit ?
+								for update skip locked
+							)
+							returning *`, lease.Seconds(), limit).
+		Scan(context.Background(), &outboxMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxMessages, nil
+}
+
+func (r *outboxRepository) UpdateByID(id string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("outbox").
+		Where("id = ?", id).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) UpdateByIDTx(id string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("outbox").
+		Where("id = ?", id).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
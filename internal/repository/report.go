@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type reportRepository struct {
+	db *bun.DB
+}
+
+func NewReportRepository(db *bun.DB) ReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+func (r *reportRepository) Save(report *model.Report) error {
+
+	_, err := r.db.NewInsert().Model(report).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepository) GetPendingReportByReporterAndContent(reporterID string, contentType string, contentID string) (*model.Report, error) {
+	report := &model.Report{}
+
+	err := r.db.NewSelect().
+		Model(report).
+		Where("reporter_id = ?", reporterID).
+		Where("content_type = ?", contentType).
+		Where("content_id = ?", contentID).
+		Where("status = 'PENDING'").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *reportRepository) GetReportsByContent(contentType string, contentID string, status string) ([]model.Report, error) {
+
+	var reports = []model.Report{}
+
+	query := r.db.NewSelect().
+		Model(&reports).
+		Relation("Reporter", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username")
+		}).
+		Where("rep.content_type = ?", contentType).
+		Where("rep.content_id = ?", contentID).
+		Order("rep.created_at desc")
+
+	if status != "" {
+		query.Where("rep.status = ?", status)
+	}
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (r *reportRepository) GetQueue(req request.GetModerationQueueReq) ([]model.ReportQueueItem, pkg.Pagination, error) {
+
+	var (
+		queueItems = []model.ReportQueueItem{}
+		nextCursor string
+	)
+
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
+		TableExpr("report AS rep").
+		ColumnExpr("rep.content_type, rep.content_id, rep.content_author_id").
+		ColumnExpr("COUNT(*) AS report_count").
+		ColumnExpr("ARRAY_AGG(DISTINCT rep.reason) AS reasons").
+		ColumnExpr("MIN(rep.created_at) AS first_reported_at").
+		ColumnExpr("MAX(rep.created_at) AS last_reported_at").
+		Where("rep.status = ?", req.Status).
+		Group("rep.content_type", "rep.content_id", "rep.content_author_id").
+		Limit(req.Limit + 1)
+
+	if req.ContentType != "" {
+		query.Where("rep.content_type = ?", req.ContentType)
+	}
+
+	// Filter on the aggregate so report_count still reflects every report of the content
+	if req.Reason != "" {
+		query.Having("BOOL_OR(rep.reason = ?)", req.Reason)
+	}
+
+	if req.MinReportCount > 0 {
+		query.Having("COUNT(*) >= ?", req.MinReportCount)
+	}
+
+	if req.Cursor != "" {
+		lastReportedAt, contentID := pkg.GetCursorData(req.Cursor)
+
+		query.Having("(MAX(rep.created_at), rep.content_id) <= (?, ?)", lastReportedAt, contentID)
+	}
+
+	query.Order("last_reported_at desc", "rep.content_id desc")
+
+	err := query.Scan(context.Background(), &queueItems)
+	if err != nil {
+		return queueItems, pagination, err
+	}
+
+	if len(queueItems) > req.Limit {
+		lastItem := queueItems[len(queueItems)-1]
+
+		nextCursor = fmt.Sprintf("%s_%s", lastItem.LastReportedAt.Format(time.RFC3339Nano), lastItem.ContentID)
+
+		queueItems = queueItems[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return queueItems, pagination, nil
+}
+
+func (r *reportRepository) ResolvePendingReportsByContentTx(contentType string, contentID string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("report").
+		Where("content_type = ?", contentType).
+		Where("content_id = ?", contentID).
+		Where("status = 'PENDING'").
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepository) SaveUserWarningTx(userWarning *model.UserWarning, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(userWarning).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
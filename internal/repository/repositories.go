@@ -6,6 +6,8 @@ import (
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/uptrace/bun"
+	"io"
+	"time"
 )
 
 type UserRepository interface {
@@ -13,6 +15,7 @@ type UserRepository interface {
 	SaveTx(user *model.User, tx bun.Tx) error
 	SaveUserDevice(userDevice *model.UserDevice) error
 	GetUserProfileByEmail(email string) (*model.User, error)
+	GetPublicProfileByUsername(username string) (*model.User, error)
 	GetByID(id string) (*model.User, error)
 	GetUserDevices(req request.GetUserDevicesReq) ([]model.UserDevice, error)
 	GetByEmail(email string) (*model.User, error)
@@ -28,8 +31,15 @@ type UserRepository interface {
 	UpdateUserVerifyCodeByID(id string, updateValues map[string]interface{}) error
 	UpdateUserVerifyCodeByIDTx(id string, updateValues map[string]interface{}, tx bun.Tx) error
 	UpdateUserPasswordByUserID(id string, updateValues map[string]interface{}) error
-	IncrementUserReputationPointsTx(id string, updateValues map[string]interface{}, tx bun.Tx) error
-	DecrementUserReputationPointsTx(id string, updateValues map[string]interface{}, tx bun.Tx) error
+	AddUserReputationPointsTx(id string, updateValues map[string]interface{}, tx bun.Tx) error
+	IncrementFailedLoginAttempts(id string) (int, error)
+	SaveUserSession(userSession *model.UserSession) error
+	GetUserSessionByID(id string) (*model.UserSession, error)
+	GetUserSessionByRefreshToken(refreshToken string) (*model.UserSession, error)
+	UpdateUserSessionByID(id string, updateValues map[string]interface{}) error
+	RotateUserSessionRefreshToken(id string, refreshToken string, updateValues map[string]interface{}) (bool, error)
+	RevokeUserSessionsByUserID(userID string, updateValues map[string]interface{}) error
+	RevokeUserSessionsByUserIDTx(userID string, updateValues map[string]interface{}, tx bun.Tx) error
 }
 
 type SubThreadRepository interface {
@@ -46,31 +56,52 @@ type SubThreadRepository interface {
 	UpdateSubThreadFollowerIsFollowingTx(id string, isFollowing bool, tx bun.Tx) error
 	DeleteByID(subThreadID string, updateValues map[string]interface{}) error
 	UpdateByID(subThreadID string, updateValues map[string]interface{}) error
+	UpdateByIDTx(subThreadID string, updateValues map[string]interface{}, tx bun.Tx) error
+	GetRulesBySubThreadID(subThreadID string) ([]model.SubThreadRule, error)
+	GetRuleByID(ruleID string) (*model.SubThreadRule, error)
+	SaveRule(rule *model.SubThreadRule) error
+	UpdateRuleByID(ruleID string, updateValues map[string]interface{}) error
+	GetActiveBans(subThreadID string) ([]model.SubThreadBan, error)
+	GetBanByUserID(subThreadID string, userID string) (*model.SubThreadBan, error)
+	IsUserBanned(subThreadID string, userID string) (bool, error)
+	SaveBan(ban *model.SubThreadBan) error
+	UpdateBanByID(banID string, updateValues map[string]interface{}) error
 }
 
 type ThreadRepository interface {
 	Save(thread *model.Thread) error
+	SaveTx(thread *model.Thread, tx bun.Tx) error
 	UpdateByID(threadID string, updateValues map[string]interface{}) error
+	UpdateByIDTx(threadID string, updateValues map[string]interface{}, tx bun.Tx) error
 	DeleteByID(threadID string, updateValues map[string]interface{}) error
+	DeleteByIDTx(threadID string, updateValues map[string]interface{}, tx bun.Tx) (bool, error)
 	GetList(req request.GetThreadListReq) ([]model.Thread, pkg.Pagination, error)
 	GetByID(id string) (model.Thread, error)
 	GetByIDSimple(id string) (model.Thread, error)
+	CountPinnedBySubThreadID(subThreadID string) (int, error)
+	GetPinnedList(req request.GetThreadListReq, limit int) ([]model.Thread, error)
+	ArchiveInactive(ctx context.Context, inactiveBefore time.Time) (int64, error)
 	GetThreadSubscribers(threadId string) ([]model.ThreadSubscription, error)
 	SaveThreadSubscription(threadSubscription *model.ThreadSubscription) error
 	UpdateThreadSubscriptionIsSubscribed(id string, isSubscribed bool) error
 	GetThreadSubscriptionByUserAndThreadID(userID string, threadID string) (model.ThreadSubscription, error)
 	GetThreadCommentByID(id string) (model.ThreadComment, error)
+	GetThreadCommentsByIDs(ids []string) ([]model.ThreadComment, error)
 	DeleteThreadCommentByID(threadCommentID string, updateValues map[string]interface{}) error
-	DeleteThreadCommentByIDTx(threadCommentID string, updateValues map[string]interface{}, tx bun.Tx) error
+	DeleteThreadCommentByIDTx(threadCommentID string, updateValues map[string]interface{}, tx bun.Tx) (bool, error)
 	UpdateThreadCommentByID(threadCommentID string, updateValues map[string]interface{}) error
+	UpdateThreadCommentByIDTx(threadCommentID string, updateValues map[string]interface{}, tx bun.Tx) error
 	GetThreadCommentReplyByID(id string) (model.ThreadCommentReply, error)
+	GetThreadCommentRepliesByIDs(ids []string) ([]model.ThreadCommentReply, error)
 	DeleteThreadCommentReplyByID(threadCommentReplyID string, updateValues map[string]interface{}) error
-	DeleteThreadCommentReplyByIDTx(threadCommentReplyID string, updateValues map[string]interface{}, tx bun.Tx) error
+	DeleteThreadCommentReplyByIDTx(threadCommentReplyID string, updateValues map[string]interface{}, tx bun.Tx) (bool, error)
 	UpdateThreadCommentReplyByID(threadCommentReplyID string, updateValues map[string]interface{}) error
+	UpdateThreadCommentReplyByIDTx(threadCommentReplyID string, updateValues map[string]interface{}, tx bun.Tx) error
 	GetLastThreadActivityByUserID(threadId string, userId string) (*model.ThreadActivity, error)
 	GetLastThreadCommentActivityByUserID(threadId string, commentId string, userId string) (*model.ThreadCommentActivity, error)
 	GetLastThreadCommentActivityReplyByUserID(threadId string, commentReplyId string, userId string) (*model.ThreadCommentActivity, error)
-	GetThreadCommentsByThreadID(threadId string, userId string) ([]model.ThreadComment, error)
+	GetThreadComments(req request.GetThreadCommentsReq) ([]model.ThreadComment, pkg.Pagination, error)
+	GetThreadCommentReplies(req request.GetThreadCommentRepliesReq) ([]model.ThreadCommentReply, pkg.Pagination, error)
 	SaveThreadActivity(threadActivity *model.ThreadActivity) error
 	SaveThreadActivityTx(threadActivity *model.ThreadActivity, tx bun.Tx) error
 	UpdateThreadActivityTx(threadActivityID string, actorID string, updateValues map[string]interface{}, tx bun.Tx) error
@@ -85,6 +116,9 @@ type ThreadRepository interface {
 	IncrementCommentsCountTx(threadID string, tx bun.Tx) error
 	DecrementCommentsCountTx(threadID string, tx bun.Tx) error
 	IncrementCommentReplyCountTx(commentID string, tx bun.Tx) error
+	DecrementCommentReplyCountTx(commentID string, tx bun.Tx) error
+	IncrementCommentReplyReplyCountTx(threadCommentReplyID string, tx bun.Tx) error
+	DecrementCommentReplyReplyCountTx(threadCommentReplyID string, tx bun.Tx) error
 	IncrementCommentLikesCountTx(threadCommentID string, tx bun.Tx) error
 	DecrementCommentLikesCountTx(threadCommentID string, tx bun.Tx) error
 	IncrementCommentDislikesCountTx(threadCommentID string, tx bun.Tx) error
@@ -96,7 +130,22 @@ type ThreadRepository interface {
 	SaveThreadCommentActivityTx(tca *model.ThreadCommentActivity, tx bun.Tx) error
 }
 
+type ThreadPollRepository interface {
+	SaveTx(threadPoll *model.ThreadPoll, tx bun.Tx) error
+	BulkSaveOptionsTx(threadPollOptions []model.ThreadPollOption, tx bun.Tx) error
+	GetByThreadID(threadID string) (model.ThreadPoll, error)
+	GetVotesByPollIDAndUserID(pollID string, userID string) ([]model.ThreadPollVote, error)
+	CountVotesByPollIDAndUserIDTx(pollID string, userID string, tx bun.Tx) (int, error)
+	BulkSaveVotesTx(threadPollVotes []model.ThreadPollVote, tx bun.Tx) error
+	DeleteVotesByPollIDAndUserIDTx(pollID string, userID string, tx bun.Tx) ([]string, error)
+	IncrementVoterCountTx(pollID string, tx bun.Tx) error
+	DecrementVoterCountTx(pollID string, tx bun.Tx) error
+	IncrementOptionsVoteCountTx(optionIDs []string, tx bun.Tx) error
+	DecrementOptionsVoteCountTx(optionIDs []string, tx bun.Tx) error
+}
+
 type UniversityRepository interface {
+	GetByID(id string) (model.University, error)
 	GetByDomain(domain string) (model.University, error)
 	GetUniversityRatingByID(id string) (model.UniversityRating, error)
 	GetList(req request.GetUniversityRatingListReq) ([]model.UniversityRating, pkg.Pagination, error)
@@ -109,6 +158,135 @@ type UniversityRepository interface {
 	DeleteUniversityRatingPointsTx(universityRatingID string, tx bun.Tx) error
 }
 
+type RoleRepository interface {
+	GetList() ([]model.Role, error)
+	GetByName(name string) (*model.Role, error)
+	GetUserRolesByUserID(userID string) ([]model.UserRole, error)
+	GetUserRole(userID string, roleID string, scopeID *string) (*model.UserRole, error)
+	SaveUserRole(userRole *model.UserRole) error
+	SaveUserRoleTx(userRole *model.UserRole, tx bun.Tx) error
+	DeleteUserRoleByID(userRoleID string, updateValues map[string]interface{}) error
+	HasPermission(userID string, permission string, scopeType string, scopeID string) (bool, error)
+	GetUserRolesByScope(scopeType string, scopeID string, roleNames []string) ([]model.UserRole, error)
+}
+
+type ReportRepository interface {
+	Save(report *model.Report) error
+	GetPendingReportByReporterAndContent(reporterID string, contentType string, contentID string) (*model.Report, error)
+	GetReportsByContent(contentType string, contentID string, status string) ([]model.Report, error)
+	GetQueue(req request.GetModerationQueueReq) ([]model.ReportQueueItem, pkg.Pagination, error)
+	ResolvePendingReportsByContentTx(contentType string, contentID string, updateValues map[string]interface{}, tx bun.Tx) error
+	SaveUserWarningTx(userWarning *model.UserWarning, tx bun.Tx) error
+}
+
+type SearchRepository interface {
+	Search(req request.SearchReq) ([]model.SearchResult, pkg.Pagination, error)
+}
+
+type NotificationRepository interface {
+	BulkSave(notifications []model.Notification) error
+	BulkSaveTx(notifications []model.Notification, tx bun.Tx) error
+	GetList(req request.GetNotificationListReq) ([]model.Notification, pkg.Pagination, error)
+	CountUnreadByUserID(userID string) (int, error)
+	GetByIDAndUserID(id string, userID string) (*model.Notification, error)
+	MarkAsRead(id string, updateValues map[string]interface{}) error
+	MarkAllAsRead(userID string, updateValues map[string]interface{}) error
+}
+
+type OutboxRepository interface {
+	SaveTx(outboxMessage *model.OutboxMessage, tx bun.Tx) error
+	ClaimPendingBatch(limit int, lease time.Duration) ([]model.OutboxMessage, error)
+	UpdateByID(id string, updateValues map[string]interface{}) error
+	UpdateByIDTx(id string, updateValues map[string]interface{}, tx bun.Tx) error
+}
+
 type FileRepository interface {
 	Upload(ctx context.Context, uploadFileData model.UploadFileDTO) (model.UploadFileOutputDTO, error)
+	Delete(ctx context.Context, bucket string, name string) error
+	Stat(ctx context.Context, bucket string, name string) (model.FileInfoDTO, error)
+	Download(ctx context.Context, bucket string, name string) (io.ReadCloser, error)
+	// PresignUpload only accepts a body of sizeBytes whose sha256 matches checksumSHA256, given as lowercase hex
+	PresignUpload(ctx context.Context, bucket string, name string, contentType string, sizeBytes int64, checksumSHA256 string, expiry time.Duration) (model.PresignedURLDTO, error)
+	PresignDownload(ctx context.Context, bucket string, name string, expiry time.Duration) (model.PresignedURLDTO, error)
+}
+
+type AttachmentRepository interface {
+	Save(attachment *model.Attachment) error
+	SaveTx(attachment *model.Attachment, tx bun.Tx) error
+	GetByID(id string) (*model.Attachment, error)
+	DetachByTargetTx(targetType string, targetID string, tx bun.Tx) error
+	AttachTx(ids []string, userID string, targetType string, targetID string, tx bun.Tx) ([]string, error)
+	GetByTargets(targetType string, targetIDs []string) ([]model.Attachment, error)
+	GetUnattachedCreatedBefore(createdBefore time.Time, limit int) ([]model.Attachment, error)
+	DeleteUnattachedByID(id string) (bool, error)
+	LockContentHashTx(contentHash string, tx bun.Tx) error
+	CountByContentHashTx(contentHash string, tx bun.Tx) (int, error)
+}
+
+type ImageUploadRepository interface {
+	Save(imageUpload *model.ImageUpload) error
+	GetByID(id string) (*model.ImageUpload, error)
+	ConfirmTx(id string, attachmentID string, tx bun.Tx) (bool, error)
+	GetPendingExpiredBefore(expiredBefore time.Time, limit int) ([]model.ImageUpload, error)
+	DeletePendingByID(id string) (bool, error)
+}
+
+type ReputationRepository interface {
+	SaveTx(reputationEvent *model.ReputationEvent, tx bun.Tx) error
+	GetNetDeltaBySourceTx(userID string, sourceType string, sourceID string, actorID string, tx bun.Tx) (int64, error)
+	GetList(req request.GetReputationHistoryReq) ([]model.ReputationEvent, pkg.Pagination, error)
+	RecomputeUserTotals(userID string, updatedBy string) (int64, error)
+}
+
+type BookmarkRepository interface {
+	Save(bookmark *model.Bookmark) error
+	UpdateByID(bookmarkID string, updateValues map[string]interface{}) error
+	DeleteByTarget(userID string, targetType string, targetID string) (bool, error)
+	GetByTarget(userID string, targetType string, targetID string) (model.Bookmark, error)
+	GetList(req request.GetBookmarkListReq) ([]model.Bookmark, pkg.Pagination, error)
+	SaveCollection(collection *model.BookmarkCollection) error
+	UpdateCollectionByID(collectionID string, updateValues map[string]interface{}) error
+	DeleteCollectionByIDTx(collectionID string, deletedBy string, tx bun.Tx) error
+	GetCollectionByID(userID string, collectionID string) (model.BookmarkCollection, error)
+	GetCollectionByName(userID string, name string) (model.BookmarkCollection, error)
+	GetCollections(userID string) ([]model.BookmarkCollection, error)
+	CountCollections(userID string) (int, error)
+}
+
+type RevisionRepository interface {
+	SaveTx(revision *model.ContentRevision, tx bun.Tx) error
+	GetListByTarget(targetType string, targetID string) ([]model.ContentRevision, error)
+}
+
+type BadgeRepository interface {
+	GetByCode(code string) (*model.Badge, error)
+	AwardTx(userBadge *model.UserBadge, badgeCode string, tx bun.Tx) (bool, error)
+	GetUserBadgesByUserIDs(userIDs []string) ([]*model.UserBadge, error)
+	CountLikesReceivedTx(userID string, tx bun.Tx) (int64, error)
+	GetSubThreadThreadCountsTx(subThreadID string, userID string, tx bun.Tx) (int, int, error)
+}
+
+type LeaderboardRepository interface {
+	GetList(req request.GetLeaderboardReq, days int) ([]model.LeaderboardEntry, error)
+	Refresh(ctx context.Context) error
+}
+
+type UserBlockRepository interface {
+	SaveTx(userBlock *model.UserBlock, tx bun.Tx) error
+	UpdateByIDTx(userBlockID string, updateValues map[string]interface{}, tx bun.Tx) error
+	DeleteByBlockedUserID(userID string, blockedUserID string) (bool, error)
+	GetByBlockedUserID(userID string, blockedUserID string) (model.UserBlock, error)
+	GetList(req request.GetUserBlockListReq) ([]model.UserBlock, pkg.Pagination, error)
+	GetUserIDsBlocking(blockedUserID string, userIDs []string) ([]string, error)
+}
+
+type UserFollowerRepository interface {
+	SaveTx(userFollower *model.UserFollower, tx bun.Tx) error
+	DeleteTx(userID string, followerID string, tx bun.Tx) (bool, error)
+	GetByUserIDAndFollowerID(userID string, followerID string) (model.UserFollower, error)
+	GetFollowers(req request.GetUserFollowListReq) ([]model.UserFollower, pkg.Pagination, error)
+	GetFollowing(req request.GetUserFollowListReq) ([]model.UserFollower, pkg.Pagination, error)
+	GetNotifiedFollowers(userID string, universityID *string, afterID string, limit int) ([]model.UserFollower, error)
+	IncrementFollowCountsTx(userID string, followerID string, tx bun.Tx) error
+	DecrementFollowCountsTx(userID string, followerID string, tx bun.Tx) error
 }
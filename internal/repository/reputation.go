@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type reputationRepository struct {
+	db *bun.DB
+}
+
+func NewReputationRepository(db *bun.DB) ReputationRepository {
+	return &reputationRepository{
+		db: db,
+	}
+}
+
+func (r *reputationRepository) SaveTx(reputationEvent *model.ReputationEvent, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().
+		Model(reputationEvent).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetNetDeltaBySourceTx sums the deltas an actor caused to a user through a single source. The pair is locked until
+// the end of the transaction so concurrent votes of the same actor can't both apply their difference
+func (r *reputationRepository) GetNetDeltaBySourceTx(userID string, sourceType string, sourceID string, actorID string, tx bun.Tx) (int64, error) {
+
+	var netDelta int64
+
+	_, err := tx.NewRaw("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", sourceType+"_"+sourceID+"_"+actorID).
+		Exec(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.NewSelect().
+		Model((*model.ReputationEvent)(nil)).
+		ColumnExpr("COALESCE(SUM(re.delta), 0)").
+		Where("re.user_id = ?", userID).
+		Where("re.source_type = ?", sourceType).
+		Where("re.source_id = ?", sourceID).
+		Where("re.actor_id = ?", actorID).
+		Scan(context.Background(), &netDelta)
+	if err != nil {
+		return 0, err
+	}
+
+	return netDelta, nil
+}
+
+func (r *reputationRepository) GetList(req request.GetReputationHistoryReq) ([]model.ReputationEvent, pkg.Pagination, error) {
+
+	var (
+		reputationEvents = []model.ReputationEvent{}
+		nextCursor       string
+	)
+
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
+		Model(&reputationEvents).
+		Where("re.user_id = ?", req.TargetUserID).
+		Limit(req.Limit + 1)
+
+	if req.Cursor != "" {
+		createdAt, reputationEventID := pkg.GetCursorData(req.Cursor)
+
+		query.Where("(re.created_at, re.id) <= (?, ?)", createdAt, reputationEventID)
+	}
+
+	query.Order("re.created_at desc", "re.id desc")
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return reputationEvents, pagination, err
+	}
+
+	if len(reputationEvents) > req.Limit {
+		lastReputationEvent := reputationEvents[len(reputationEvents)-1]
+
+		nextCursor = fmt.Sprintf("%s_%s", lastReputationEvent.CreatedAt.Format(time.RFC3339Nano), lastReputationEvent.ID)
+
+		reputationEvents = reputationEvents[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return reputationEvents, pagination, nil
+}
+
+// RecomputeUserTotals resets user.reputation_points to the sum of the ledger, for every user when userID is empty.
+// It returns the number of users whose cached total had drifted
+func (r *reputationRepository) RecomputeUserTotals(userID string, updatedBy string) (int64, error) {
+
+	totalSubQuery := r.db.NewSelect().
+		TableExpr(`"user" AS tu`).
+		ColumnExpr("tu.id").
+		ColumnExpr("COALESCE(SUM(re.delta), 0) AS total").
+		Join("LEFT JOIN reputation_event AS re ON re.user_id = tu.id").
+		GroupExpr("tu.id")
+
+	if userID != "" {
+		totalSubQuery.Where("tu.id = ?", userID)
+	}
+
+	res, err := r.db.NewUpdate().
+		TableExpr(`"user" AS u`).
+		With("t", totalSubQuery).
+		TableExpr("t").
+		Set("reputation_points = t.total").
+		Set("updated_at = now()").
+		Set("updated_by = ?", updatedBy).
+		Where("u.id = t.id").
+		Where("u.reputation_points <> t.total").
+		Exec(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
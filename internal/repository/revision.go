@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type revisionRepository struct {
+	db *bun.DB
+}
+
+func NewRevisionRepository(db *bun.DB) RevisionRepository {
+	return &revisionRepository{
+		db: db,
+	}
+}
+
+func (r *revisionRepository) SaveTx(revision *model.ContentRevision, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().
+		Model(revision).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *revisionRepository) GetListByTarget(targetType string, targetID string) ([]model.ContentRevision, error) {
+
+	var (
+		revisions []model.ContentRevision
+	)
+
+	err := r.db.NewSelect().
+		Model(&revisions).
+		Where("crv.target_type = ?", targetType).
+		Where("crv.target_id = ?", targetID).
+		Order("crv.created_at desc").
+		Scan(context.Background())
+	if err != nil {
+		return revisions, err
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type roleRepository struct {
+	db *bun.DB
+}
+
+func NewRoleRepository(db *bun.DB) RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (r *roleRepository) GetList() ([]model.Role, error) {
+
+	var roles = []model.Role{}
+
+	err := r.db.NewSelect().
+		Model(&roles).
+		Relation("RolePermissions.Permission").
+		Order("r.name ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetByName(name string) (*model.Role, error) {
+	role := &model.Role{}
+
+	err := r.db.NewSelect().
+		Model(role).
+		Where("name = ?", name).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) GetUserRolesByUserID(userID string) ([]model.UserRole, error) {
+
+	var userRoles = []model.UserRole{}
+
+	err := r.db.NewSelect().
+		Model(&userRoles).
+		Relation("Role").
+		Where("ur.user_id = ?", userID).
+		Order("ur.created_at ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return userRoles, nil
+}
+
+func (r *roleRepository) GetUserRole(userID string, roleID string, scopeID *string) (*model.UserRole, error) {
+	userRole := &model.UserRole{}
+
+	query := r.db.NewSelect().
+		Model(userRole).
+		Where("user_id = ?", userID).
+		Where("role_id = ?", roleID)
+
+	if scopeID != nil {
+		query.Where("scope_id = ?", *scopeID)
+	} else {
+		query.Where("scope_id IS NULL")
+	}
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return userRole, nil
+}
+
+func (r *roleRepository) SaveUserRole(userRole *model.UserRole) error {
+
+	_, err := r.db.NewInsert().Model(userRole).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *roleRepository) SaveUserRoleTx(userRole *model.UserRole, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(userRole).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *roleRepository) DeleteUserRoleByID(userRoleID string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("user_role").
+		Where("id = ?", userRoleID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetUserRolesByScope returns the users holding one of the roles on a single subthread/university
+func (r *roleRepository) GetUserRolesByScope(scopeType string, scopeID string, roleNames []string) ([]model.UserRole, error) {
+
+	var userRoles = []model.UserRole{}
+
+	err := r.db.NewSelect().
+		Model(&userRoles).
+		Relation("Role").
+		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username", "avatar_url")
+		}).
+		Where("ur.scope_type = ?", scopeType).
+		Where("ur.scope_id = ?", scopeID).
+		Where("role.name IN (?)", bun.In(roleNames)).
+		Order("ur.created_at ASC").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return userRoles, nil
+}
+
+// HasPermission checks whether the user owns the permission through a global role, or through
+// a role scoped to scopeType/scopeID when a scope is given
+func (r *roleRepository) HasPermission(userID string, permission string, scopeType string, scopeID string) (bool, error) {
+
+	query := r.db.NewSelect().
+		TableExpr("user_role AS ur").
+		Join("JOIN role_permission AS rp ON rp.role_id = ur.role_id").
+		Join("JOIN permission AS p ON p.id = rp.permission_id").
+		Where("ur.user_id = ?", userID).
+		Where("ur.deleted_at IS NULL").
+		Where("p.name = ?", permission)
+
+	if scopeType != "" && scopeID != "" {
+		query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("ur.scope_id IS NULL").
+				WhereOr("ur.scope_type = ? AND ur.scope_id = ?", scopeType, scopeID)
+		})
+	} else {
+		query.Where("ur.scope_id IS NULL")
+	}
+
+	exists, err := query.Exists(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"strconv"
+)
+
+// searchHeadlineOptions are the ts_headline options used to build highlighted snippets
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" ... \""
+
+type searchRepository struct {
+	db *bun.DB
+}
+
+func NewSearchRepository(db *bun.DB) SearchRepository {
+	return &searchRepository{
+		db: db,
+	}
+}
+
+func (r *searchRepository) Search(req request.SearchReq) ([]model.SearchResult, pkg.Pagination, error) {
+
+	var (
+		results    = []model.SearchResult{}
+		nextCursor string
+	)
+
+	pagination := pkg.Pagination{}
+
+	var subQueries []*bun.SelectQuery
+
+	if req.Type == "" || req.Type == constants.SEARCH_TYPE_THREAD {
+		threadQuery := r.db.NewSelect().
+			TableExpr("thread AS th").
+			ColumnExpr("? AS type", constants.SEARCH_TYPE_THREAD).
+			ColumnExpr("th.id, th.title, th.content AS body").
+			ColumnExpr("ts_rank_cd(th.search_vector, websearch_to_tsquery('simple', ?))::float8 AS rank", req.Query).
+			ColumnExpr("th.created_at").
+			Where("th.deleted_at IS NULL").
+			Where("th.search_vector @@ websearch_to_tsquery('simple', ?)", req.Query)
+
+		if !req.CanAccessAllUniversities {
+			threadQuery.Where("th.subthread_id NOT IN (?)", closedUniversitySubThreadsQuery(r.db, req.UserUniversityID))
+		}
+
+		subQueries = append(subQueries, threadQuery)
+	}
+
+	if req.Type == "" || req.Type == constants.SEARCH_TYPE_SUBTHREAD {
+		subQueries = append(subQueries, r.db.NewSelect().
+			TableExpr("subthread AS st").
+			ColumnExpr("? AS type", constants.SEARCH_TYPE_SUBTHREAD).
+			ColumnExpr("st.id, st.name AS title, st.description AS body").
+			ColumnExpr("ts_rank_cd(st.search_vector, websearch_to_tsquery('simple', ?))::float8 AS rank", req.Query).
+			ColumnExpr("st.created_at").
+			Where("st.deleted_at IS NULL").
+			Where("st.search_vector @@ websearch_to_tsquery('simple', ?)", req.Query))
+	}
+
+	if req.Type == "" || req.Type == constants.SEARCH_TYPE_UNIVERSITY_RATING {
+		subQueries = append(subQueries, r.db.NewSelect().
+			TableExpr("university_rating AS unir").
+			ColumnExpr("? AS type", constants.SEARCH_TYPE_UNIVERSITY_RATING).
+			ColumnExpr("unir.id, unir.title, unir.content AS body").
+			ColumnExpr("ts_rank_cd(unir.search_vector, websearch_to_tsquery('simple', ?))::float8 AS rank", req.Query).
+			ColumnExpr("unir.created_at").
+			Where("unir.deleted_at IS NULL").
+			Where("unir.search_vector @@ websearch_to_tsquery('simple', ?)", req.Query))
+	}
+
+	if len(subQueries) == 0 {
+		return results, pagination, nil
+	}
+
+	unionQuery := subQueries[0]
+	for _, sq := range subQueries[1:] {
+		unionQuery = unionQuery.UnionAll(sq)
+	}
+
+	// Snippets are built on the outer query so ts_headline only runs for the returned page
+	query := r.db.NewSelect().
+		TableExpr("(?) AS res", unionQuery).
+		ColumnExpr("res.type, res.id, res.title, res.rank, res.created_at").
+		ColumnExpr("ts_headline('simple', res.body, websearch_to_tsquery('simple', ?), ?) AS snippet", req.Query, searchHeadlineOptions).
+		Limit(req.Limit + 1)
+
+	if req.Cursor != "" {
+		rank, id := pkg.GetCursorData(req.Cursor)
+
+		query.Where("(res.rank, res.id) <= (?, ?)", rank, id)
+	}
+
+	query.Order("res.rank desc", "res.id desc")
+
+	err := query.Scan(context.Background(), &results)
+	if err != nil {
+		return results, pagination, err
+	}
+
+	if len(results) > req.Limit {
+		lastResult := results[len(results)-1]
+
+		nextCursor = fmt.Sprintf("%s_%s", strconv.FormatFloat(lastResult.Rank, 'g', -1, 64), lastResult.ID)
+
+		results = results[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return results, pagination, nil
+}
@@ -222,6 +222,20 @@ func (r *subThreadRepository) UpdateByID(subThreadID string, updateValues map[st
 	return nil
 }
 
+func (r *subThreadRepository) UpdateByIDTx(subThreadID string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("subthread").
+		Where("id = ?", subThreadID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *subThreadRepository) DeleteByID(subThreadID string, updateValues map[string]interface{}) error {
 
 	_, err := r.db.NewUpdate().
@@ -235,3 +249,138 @@ func (r *subThreadRepository) DeleteByID(subThreadID string, updateValues map[st
 
 	return nil
 }
+
+func (r *subThreadRepository) GetRulesBySubThreadID(subThreadID string) ([]model.SubThreadRule, error) {
+
+	var rules = []model.SubThreadRule{}
+
+	err := r.db.NewSelect().
+		Model(&rules).
+		Where("str.subthread_id = ?", subThreadID).
+		Order("str.position ASC", "str.created_at ASC").
+		Scan(context.Background())
+	if err != nil {
+		return rules, err
+	}
+
+	return rules, nil
+}
+
+func (r *subThreadRepository) GetRuleByID(ruleID string) (*model.SubThreadRule, error) {
+	rule := &model.SubThreadRule{}
+
+	err := r.db.NewSelect().Model(rule).Where("id = ?", ruleID).Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (r *subThreadRepository) SaveRule(rule *model.SubThreadRule) error {
+
+	_, err := r.db.NewInsert().Model(rule).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *subThreadRepository) UpdateRuleByID(ruleID string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("subthread_rule").
+		Where("id = ?", ruleID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetActiveBans returns the bans of a subthread that are not lifted and not expired
+func (r *subThreadRepository) GetActiveBans(subThreadID string) ([]model.SubThreadBan, error) {
+
+	var bans = []model.SubThreadBan{}
+
+	err := r.db.NewSelect().
+		Model(&bans).
+		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username", "avatar_url")
+		}).
+		Where("stb.subthread_id = ?", subThreadID).
+		Where("stb.expired_at IS NULL OR stb.expired_at > NOW()").
+		Order("stb.created_at DESC").
+		Scan(context.Background())
+	if err != nil {
+		return bans, err
+	}
+
+	return bans, nil
+}
+
+// GetBanByUserID returns the ban of the user that is not lifted, it may already be expired
+func (r *subThreadRepository) GetBanByUserID(subThreadID string, userID string) (*model.SubThreadBan, error) {
+	ban := &model.SubThreadBan{}
+
+	err := r.db.NewSelect().
+		Model(ban).
+		Where("subthread_id = ? AND user_id = ?", subThreadID, userID).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+func (r *subThreadRepository) IsUserBanned(subThreadID string, userID string) (bool, error) {
+
+	exists, err := r.db.NewSelect().
+		Model((*model.SubThreadBan)(nil)).
+		Where("stb.subthread_id = ? AND stb.user_id = ?", subThreadID, userID).
+		Where("stb.expired_at IS NULL OR stb.expired_at > NOW()").
+		Exists(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *subThreadRepository) SaveBan(ban *model.SubThreadBan) error {
+
+	_, err := r.db.NewInsert().Model(ban).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *subThreadRepository) UpdateBanByID(banID string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("subthread_ban").
+		Where("id = ?", banID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// closedUniversitySubThreadsQuery selects the ids of the university subthreads which are closed to the students of other universities
+func closedUniversitySubThreadsQuery(db *bun.DB, universityID *string) *bun.SelectQuery {
+	return db.NewSelect().
+		TableExpr("subthread AS ust").
+		Column("ust.id").
+		Where("ust.is_university_subthread = TRUE").
+		Where("ust.university_id IS NOT NULL").
+		Where("ust.university_id IS DISTINCT FROM ?", universityID)
+}
@@ -3,11 +3,12 @@ package repository
 import (
 	"context"
 	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/uptrace/bun"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -31,6 +32,16 @@ func (r *threadRepository) Save(thread *model.Thread) error {
 	return nil
 }
 
+func (r *threadRepository) SaveTx(thread *model.Thread, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(thread).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *threadRepository) UpdateByID(threadID string, updateValues map[string]interface{}) error {
 
 	_, err := r.db.NewUpdate().
@@ -45,6 +56,20 @@ func (r *threadRepository) UpdateByID(threadID string, updateValues map[string]i
 	return nil
 }
 
+func (r *threadRepository) UpdateByIDTx(threadID string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("thread").
+		Where("id = ?", threadID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *threadRepository) DeleteByID(threadID string, updateValues map[string]interface{}) error {
 
 	_, err := r.db.NewUpdate().
@@ -59,9 +84,30 @@ func (r *threadRepository) DeleteByID(threadID string, updateValues map[string]i
 	return nil
 }
 
+// DeleteByIDTx reports whether the row was deleted, it is false when it was already deleted
+func (r *threadRepository) DeleteByIDTx(threadID string, updateValues map[string]interface{}, tx bun.Tx) (bool, error) {
+
+	res, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("thread").
+		Where("id = ?", threadID).
+		Where("deleted_at IS NULL").
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
 func (r *threadRepository) IncrementCommentsCountTx(threadID string, tx bun.Tx) error {
 
-	_, err := tx.NewRaw("UPDATE thread SET comment_count = comment_count + 1 WHERE id = ?", threadID).
+	_, err := tx.NewRaw("UPDATE thread SET comment_count = comment_count + 1, last_activity_at = NOW() WHERE id = ?", threadID).
 		Exec(context.Background())
 
 	if err != nil {
@@ -250,6 +296,40 @@ func (r *threadRepository) GetByIDSimple(id string) (model.Thread, error) {
 	return thread, nil
 }
 
+func (r *threadRepository) CountPinnedBySubThreadID(subThreadID string) (int, error) {
+
+	count, err := r.db.NewSelect().
+		Model((*model.Thread)(nil)).
+		Where("th.subthread_id = ?", subThreadID).
+		Where("th.is_pinned = TRUE").
+		Count(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ArchiveInactive archives the unpinned threads without a new comment or reply since inactiveBefore
+func (r *threadRepository) ArchiveInactive(ctx context.Context, inactiveBefore time.Time) (int64, error) {
+
+	res, err := r.db.NewUpdate().
+		TableExpr("thread").
+		Set("is_archived = TRUE").
+		Set("archived_at = NOW()").
+		Set("archived_by = ?", "SYSTEM").
+		Where("is_archived = FALSE").
+		Where("is_pinned = FALSE").
+		Where("deleted_at IS NULL").
+		Where("last_activity_at < ?", inactiveBefore).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
 func (r *threadRepository) GetThreadSubscribers(threadId string) ([]model.ThreadSubscription, error) {
 
 	var (
@@ -290,6 +370,74 @@ func (r *threadRepository) GetThreadSubscriptionByUserAndThreadID(userID string,
 	return threadSubscription, nil
 }
 
+// newThreadListQuery selects the threads matching the filters shared by the paginated and the pinned thread lists
+func (r *threadRepository) newThreadListQuery(threads *[]model.Thread, req request.GetThreadListReq) *bun.SelectQuery {
+
+	query := r.db.NewSelect().
+		Column("th.*").
+		Model(threads).
+		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username")
+		}).
+		Relation("User.University").
+		Relation("SubThread", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "name", "label_color")
+		})
+
+	if req.IncludeUserActivity {
+		query.ColumnExpr("ta.action as thread_action")
+		query.ColumnExpr("EXISTS (SELECT 1 FROM bookmark AS bm WHERE bm.user_id = ? AND bm.target_type = ? AND bm.target_id = th.id) AS is_bookmarked", req.UserID, constants.BOOKMARK_TARGET_TYPE_THREAD)
+		query.Join("LEFT JOIN thread_activity AS ta ON ta.thread_id = th.id AND ta.actor_id = ?", req.UserID)
+	}
+
+	if req.IsUserFollowing {
+		query.Join("JOIN subthread_follower AS stf ON stf.subthread_id = th.subthread_id AND stf.user_id = ?", req.UserID)
+		query.Where("stf.is_following = TRUE")
+	}
+
+	// The feed holds the threads of the users followed by the user, except those who blocked the user
+	if req.IsFeed {
+		query.Join("JOIN user_follower AS uf ON uf.user_id = th.user_id AND uf.follower_id = ?", req.UserID)
+		query.Where("th.user_id NOT IN (?)", blockingUsersQuery(r.db, req.UserID))
+	}
+
+	if req.SubThreadID != "" {
+		query.Where("th.subthread_id = ?", req.SubThreadID)
+	}
+
+	if req.UserIDParam != "" {
+		query.Where("th.user_id = ?", req.UserIDParam)
+	}
+
+	if !req.CanAccessAllUniversities {
+		query.Where("th.subthread_id NOT IN (?)", closedUniversitySubThreadsQuery(r.db, req.UserUniversityID))
+	}
+
+	if req.UserID != "" {
+		query.Where("th.user_id NOT IN (?)", hiddenUsersQuery(r.db, req.UserID))
+	}
+
+	return query
+}
+
+func (r *threadRepository) GetPinnedList(req request.GetThreadListReq, limit int) ([]model.Thread, error) {
+
+	var (
+		threads []model.Thread
+	)
+
+	err := r.newThreadListQuery(&threads, req).
+		Where("th.is_pinned = TRUE").
+		Order("th.pinned_at desc", "th.id desc").
+		Limit(limit).
+		Scan(context.Background())
+	if err != nil {
+		return threads, err
+	}
+
+	return threads, nil
+}
+
 func (r *threadRepository) GetList(req request.GetThreadListReq) ([]model.Thread, pkg.Pagination, error) {
 
 	var (
@@ -307,45 +455,24 @@ func (r *threadRepository) GetList(req request.GetThreadListReq) ([]model.Thread
 									(comment_count * 2)
 								) * EXP(EXTRACT(EPOCH FROM (NOW() - created_at)) / -172800.0), 2) AS trending_score`)
 
-	query := r.db.NewSelect().
-		Column("th.*").
-		Model(&threads).
-		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.Column("id", "username")
-		}).
-		Relation("User.University").
-		Relation("SubThread", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.Column("id", "name", "label_color")
-		}).
+	query := r.newThreadListQuery(&threads, req).
 		Limit(req.Limit + 1)
 
-	if req.IncludeUserActivity {
-		query.ColumnExpr("ta.action as thread_action")
-		query.Join("LEFT JOIN thread_activity AS ta ON ta.thread_id = th.id AND ta.actor_id = ?", req.UserID)
-	}
-
 	if req.IsTrending {
 		query.Column("ts.trending_score")
 		query.Join("LEFT JOIN (?) AS ts ON (ts.id = th.id)", trendingScoreSubQuery)
 	}
 
-	if req.IsUserFollowing {
-		query.Join("JOIN subthread_follower AS stf ON stf.subthread_id = th.subthread_id AND stf.user_id = ?", req.UserID)
-		query.Where("stf.is_following = TRUE")
+	if len(req.ExcludeThreadIDs) > 0 {
+		query.Where("th.id NOT IN (?)", bun.In(req.ExcludeThreadIDs))
 	}
 
-	if req.UserIDParam != "" {
-		query.Where("th.user_id = ?", req.UserIDParam)
-	}
+	isSearchRanked := req.Search != "" && !req.IsTrending
 
 	if req.Search != "" {
-		searchCols := []string{
-			"th.title",
-			"th.content",
-			"th.content_summary",
-		}
-
-		query.Where("CONCAT("+strings.Join(searchCols, ", ")+") ILIKE ?", "%"+req.Search+"%")
+		query.ColumnExpr("ts_rank_cd(th.search_vector, websearch_to_tsquery('simple', ?))::float8 AS search_rank", req.Search)
+		query.ColumnExpr("ts_headline('simple', th.content, websearch_to_tsquery('simple', ?), ?) AS search_snippet", req.Search, searchHeadlineOptions)
+		query.Where("th.search_vector @@ websearch_to_tsquery('simple', ?)", req.Search)
 	}
 
 	if req.Cursor != "" {
@@ -355,6 +482,12 @@ func (r *threadRepository) GetList(req request.GetThreadListReq) ([]model.Thread
 			query.Where("ts.trending_score <= ?", trendingScore)
 			query.Order("ts.trending_score desc")
 
+		} else if isSearchRanked {
+			searchRank, id := pkg.GetCursorData(req.Cursor)
+
+			query.Where("(ts_rank_cd(th.search_vector, websearch_to_tsquery('simple', ?))::float8, th.id) <= (?, ?)", req.Search, searchRank, id)
+
+			query.Order("search_rank desc", "th.id desc")
 		} else {
 
 			createdAt, id := pkg.GetCursorData(req.Cursor)
@@ -366,6 +499,8 @@ func (r *threadRepository) GetList(req request.GetThreadListReq) ([]model.Thread
 	} else {
 		if req.IsTrending {
 			query.Order("ts.trending_score desc")
+		} else if isSearchRanked {
+			query.Order("search_rank desc", "th.id desc")
 		} else {
 			query.Order("th.created_at desc")
 		}
@@ -381,6 +516,8 @@ func (r *threadRepository) GetList(req request.GetThreadListReq) ([]model.Thread
 
 		if req.IsTrending {
 			nextCursor = fmt.Sprintf("%.2f_%s", lastThread.TrendingScore, lastThread.ID)
+		} else if isSearchRanked {
+			nextCursor = fmt.Sprintf("%s_%s", strconv.FormatFloat(lastThread.SearchRank, 'g', -1, 64), lastThread.ID)
 		} else {
 			nextCursor = fmt.Sprintf("%s_%s", lastThread.CreatedAt.Format(time.RFC3339Nano), lastThread.ID)
 		}
@@ -394,6 +531,46 @@ func (r *threadRepository) GetList(req request.GetThreadListReq) ([]model.Thread
 	return threads, pagination, nil
 }
 
+func (r *threadRepository) GetThreadCommentsByIDs(ids []string) ([]model.ThreadComment, error) {
+
+	var (
+		threadComments = []model.ThreadComment{}
+	)
+
+	err := r.db.NewSelect().
+		Model(&threadComments).
+		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username")
+		}).
+		Where("thc.id IN (?)", bun.In(ids)).
+		Scan(context.Background())
+	if err != nil {
+		return threadComments, err
+	}
+
+	return threadComments, nil
+}
+
+func (r *threadRepository) GetThreadCommentRepliesByIDs(ids []string) ([]model.ThreadCommentReply, error) {
+
+	var (
+		threadCommentReplies = []model.ThreadCommentReply{}
+	)
+
+	err := r.db.NewSelect().
+		Model(&threadCommentReplies).
+		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username")
+		}).
+		Where("thcr.id IN (?)", bun.In(ids)).
+		Scan(context.Background())
+	if err != nil {
+		return threadCommentReplies, err
+	}
+
+	return threadCommentReplies, nil
+}
+
 func (r *threadRepository) GetThreadCommentReplyByID(id string) (model.ThreadCommentReply, error) {
 
 	var (
@@ -441,6 +618,42 @@ func (r *threadRepository) IncrementCommentReplyCountTx(commentID string, tx bun
 	return nil
 }
 
+func (r *threadRepository) DecrementCommentReplyCountTx(commentID string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE thread_comment SET reply_count = reply_count - 1 WHERE id = ?", commentID).
+		Exec(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *threadRepository) IncrementCommentReplyReplyCountTx(threadCommentReplyID string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE thread_comment_reply SET reply_count = reply_count + 1 WHERE id = ?", threadCommentReplyID).
+		Exec(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *threadRepository) DecrementCommentReplyReplyCountTx(threadCommentReplyID string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE thread_comment_reply SET reply_count = reply_count - 1 WHERE id = ?", threadCommentReplyID).
+		Exec(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *threadRepository) SaveCommentReplyTx(threadCommentReply *model.ThreadCommentReply, tx bun.Tx) error {
 
 	_, err := tx.NewInsert().Model(threadCommentReply).Exec(context.Background())
@@ -591,42 +804,157 @@ func (r *threadRepository) GetLastThreadCommentActivityReplyByUserID(threadId st
 	return threadCommentActivity, nil
 }
 
-func (r *threadRepository) GetThreadCommentsByThreadID(threadId string, userId string) ([]model.ThreadComment, error) {
+func (r *threadRepository) GetThreadComments(req request.GetThreadCommentsReq) ([]model.ThreadComment, pkg.Pagination, error) {
 	var (
-		threadComments []model.ThreadComment
+		threadComments = []model.ThreadComment{}
+		nextCursor     string
 	)
 
-	err := r.db.NewSelect().
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
 		Column("thc.*").
 		ColumnExpr("tca.action as comment_action").
 		Model(&threadComments).
-		Join("LEFT JOIN thread_comment_activity AS tca ON tca.thread_comment_id = thc.id AND tca.actor_id = ? AND tca.thread_comment_reply_id IS NULL", userId).
+		Join("LEFT JOIN thread_comment_activity AS tca ON tca.thread_comment_id = thc.id AND tca.actor_id = ? AND tca.thread_comment_reply_id IS NULL", req.UserID).
 		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
 			return q.Column("id", "username")
 		}).
 		Relation("User.University", func(q *bun.SelectQuery) *bun.SelectQuery {
 			return q.Column("id", "name", "abbreviated_name", "image_url")
 		}).
-		Relation("Replies", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.ColumnExpr("thcr.*, tca.action as comment_reply_action").
-				Join("LEFT JOIN thread_comment_activity AS tca ON tca.thread_comment_reply_id = thcr.id AND tca.actor_id = ?", userId).
-				Order("thcr.created_at desc")
-		}).
-		Relation("Replies.User", func(q *bun.SelectQuery) *bun.SelectQuery {
+		Where("thc.thread_id = ?", req.ThreadID).
+		Limit(req.Limit + 1)
+
+	if req.UserID != "" {
+		query.Where("thc.user_id NOT IN (?)", hiddenUsersQuery(r.db, req.UserID))
+	}
+
+	applyCommentSort(query, "thc", req.SortBy, req.Cursor)
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return threadComments, pagination, err
+	}
+
+	if len(threadComments) > req.Limit {
+		lastComment := threadComments[len(threadComments)-1]
+
+		nextCursor = getCommentCursor(req.SortBy, lastComment.CreatedAt, lastComment.SortValue, lastComment.ID)
+
+		threadComments = threadComments[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return threadComments, pagination, nil
+}
+
+// GetThreadCommentReplies returns a page of the direct replies of a comment, or of a reply when ParentReplyID is set
+func (r *threadRepository) GetThreadCommentReplies(req request.GetThreadCommentRepliesReq) ([]model.ThreadCommentReply, pkg.Pagination, error) {
+	var (
+		threadCommentReplies = []model.ThreadCommentReply{}
+		nextCursor           string
+	)
+
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
+		Column("thcr.*").
+		ColumnExpr("tca.action as comment_reply_action").
+		Model(&threadCommentReplies).
+		Join("LEFT JOIN thread_comment_activity AS tca ON tca.thread_comment_reply_id = thcr.id AND tca.actor_id = ?", req.UserID).
+		Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
 			return q.Column("id", "username")
 		}).
-		Relation("Replies.User.University", func(q *bun.SelectQuery) *bun.SelectQuery {
+		Relation("User.University", func(q *bun.SelectQuery) *bun.SelectQuery {
 			return q.Column("id", "name", "abbreviated_name", "image_url")
 		}).
-		Where("thc.thread_id = ?", threadId).
-		Order("thc.created_at desc").
-		Scan(context.Background())
+		Where("thcr.thread_comment_id = ?", req.CommentID).
+		Limit(req.Limit + 1)
+
+	if req.ParentReplyID != "" {
+		query.Where("thcr.parent_reply_id = ?", req.ParentReplyID)
+	} else {
+		query.Where("thcr.parent_reply_id IS NULL")
+	}
+
+	if req.UserID != "" {
+		query.Where("thcr.user_id NOT IN (?)", hiddenUsersQuery(r.db, req.UserID))
+	}
+
+	applyCommentSort(query, "thcr", req.SortBy, req.Cursor)
 
+	err := query.Scan(context.Background())
 	if err != nil {
-		return nil, err
+		return threadCommentReplies, pagination, err
 	}
 
-	return threadComments, nil
+	if len(threadCommentReplies) > req.Limit {
+		lastReply := threadCommentReplies[len(threadCommentReplies)-1]
+
+		nextCursor = getCommentCursor(req.SortBy, lastReply.CreatedAt, lastReply.SortValue, lastReply.ID)
+
+		threadCommentReplies = threadCommentReplies[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return threadCommentReplies, pagination, nil
+}
+
+// commentSortExpr returns the score a comment or reply is ranked by, newest has none and is ranked by created_at
+func commentSortExpr(alias string, sortBy string) string {
+
+	switch sortBy {
+	case constants.COMMENT_SORT_TOP:
+		return fmt.Sprintf("(%[1]s.like_count - %[1]s.dislike_count)::float8", alias)
+	case constants.COMMENT_SORT_CONTROVERSIAL:
+		// Many votes evenly split between likes and dislikes rank highest, one-sided votes rank last
+		return fmt.Sprintf(`(CASE WHEN %[1]s.like_count = 0 OR %[1]s.dislike_count = 0 THEN 0
+			ELSE power((%[1]s.like_count + %[1]s.dislike_count)::float8, LEAST(%[1]s.like_count, %[1]s.dislike_count)::float8 / GREATEST(%[1]s.like_count, %[1]s.dislike_count))
+			END)::float8`, alias)
+	}
+
+	return ""
+}
+
+func applyCommentSort(query *bun.SelectQuery, alias string, sortBy string, cursor string) {
+
+	sortExpr := commentSortExpr(alias, sortBy)
+
+	if sortExpr == "" {
+		if cursor != "" {
+			createdAt, id := pkg.GetCursorData(cursor)
+
+			query.Where(fmt.Sprintf("(%[1]s.created_at, %[1]s.id) <= (?, ?)", alias), createdAt, id)
+		}
+
+		query.OrderExpr(fmt.Sprintf("%[1]s.created_at desc, %[1]s.id desc", alias))
+
+		return
+	}
+
+	query.ColumnExpr(sortExpr + " AS sort_value")
+
+	if cursor != "" {
+		sortValue, id := pkg.GetCursorData(cursor)
+
+		query.Where(fmt.Sprintf("(%s, %s.id) <= (?::float8, ?)", sortExpr, alias), sortValue, id)
+	}
+
+	query.OrderExpr(fmt.Sprintf("sort_value desc, %s.id desc", alias))
+}
+
+func getCommentCursor(sortBy string, createdAt time.Time, sortValue float64, id string) string {
+
+	if commentSortExpr("", sortBy) == "" {
+		return fmt.Sprintf("%s_%s", createdAt.Format(time.RFC3339Nano), id)
+	}
+
+	return fmt.Sprintf("%s_%s", strconv.FormatFloat(sortValue, 'g', -1, 64), id)
 }
 
 func (r *threadRepository) GetThreadCommentByID(id string) (model.ThreadComment, error) {
@@ -661,18 +989,25 @@ func (r *threadRepository) DeleteThreadCommentByID(threadCommentID string, updat
 	return nil
 }
 
-func (r *threadRepository) DeleteThreadCommentByIDTx(threadCommentID string, updateValues map[string]interface{}, tx bun.Tx) error {
+// DeleteThreadCommentByIDTx reports whether the row was deleted, it is false when it was already deleted
+func (r *threadRepository) DeleteThreadCommentByIDTx(threadCommentID string, updateValues map[string]interface{}, tx bun.Tx) (bool, error) {
 
-	_, err := tx.NewUpdate().
+	res, err := tx.NewUpdate().
 		Model(&updateValues).
 		TableExpr("thread_comment").
 		Where("id = ?", threadCommentID).
+		Where("deleted_at IS NULL").
 		Exec(context.Background())
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
 }
 
 func (r *threadRepository) UpdateThreadCommentByID(threadCommentID string, updateValues map[string]interface{}) error {
@@ -689,6 +1024,20 @@ func (r *threadRepository) UpdateThreadCommentByID(threadCommentID string, updat
 	return nil
 }
 
+func (r *threadRepository) UpdateThreadCommentByIDTx(threadCommentID string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("thread_comment").
+		Where("id = ?", threadCommentID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *threadRepository) DeleteThreadCommentReplyByID(threadCommentReplyID string, updateValues map[string]interface{}) error {
 
 	_, err := r.db.NewUpdate().
@@ -703,18 +1052,25 @@ func (r *threadRepository) DeleteThreadCommentReplyByID(threadCommentReplyID str
 	return nil
 }
 
-func (r *threadRepository) DeleteThreadCommentReplyByIDTx(threadCommentReplyID string, updateValues map[string]interface{}, tx bun.Tx) error {
+// DeleteThreadCommentReplyByIDTx reports whether the row was deleted, it is false when it was already deleted
+func (r *threadRepository) DeleteThreadCommentReplyByIDTx(threadCommentReplyID string, updateValues map[string]interface{}, tx bun.Tx) (bool, error) {
 
-	_, err := tx.NewUpdate().
+	res, err := tx.NewUpdate().
 		Model(&updateValues).
 		TableExpr("thread_comment_reply").
 		Where("id = ?", threadCommentReplyID).
+		Where("deleted_at IS NULL").
 		Exec(context.Background())
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
 }
 
 func (r *threadRepository) UpdateThreadCommentReplyByID(threadCommentReplyID string, updateValues map[string]interface{}) error {
@@ -731,6 +1087,20 @@ func (r *threadRepository) UpdateThreadCommentReplyByID(threadCommentReplyID str
 	return nil
 }
 
+func (r *threadRepository) UpdateThreadCommentReplyByIDTx(threadCommentReplyID string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("thread_comment_reply").
+		Where("id = ?", threadCommentReplyID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *threadRepository) SaveThreadSubscription(threadSubscription *model.ThreadSubscription) error {
 
 	_, err := r.db.NewInsert().Model(threadSubscription).Exec(context.Background())
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+)
+
+type threadPollRepository struct {
+	db *bun.DB
+}
+
+func NewThreadPollRepository(db *bun.DB) ThreadPollRepository {
+	return &threadPollRepository{
+		db: db,
+	}
+}
+
+func (r *threadPollRepository) SaveTx(threadPoll *model.ThreadPoll, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(threadPoll).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *threadPollRepository) BulkSaveOptionsTx(threadPollOptions []model.ThreadPollOption, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(&threadPollOptions).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *threadPollRepository) GetByThreadID(threadID string) (model.ThreadPoll, error) {
+
+	var threadPoll model.ThreadPoll
+
+	err := r.db.NewSelect().
+		Model(&threadPoll).
+		Relation("Options", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("thpo.position ASC")
+		}).
+		Where("thp.thread_id = ?", threadID).
+		Scan(context.Background())
+	if err != nil {
+		return threadPoll, err
+	}
+
+	return threadPoll, nil
+}
+
+func (r *threadPollRepository) GetVotesByPollIDAndUserID(pollID string, userID string) ([]model.ThreadPollVote, error) {
+
+	var threadPollVotes []model.ThreadPollVote
+
+	err := r.db.NewSelect().
+		Model(&threadPollVotes).
+		Where("poll_id = ?", pollID).
+		Where("user_id = ?", userID).
+		Scan(context.Background())
+	if err != nil {
+		return threadPollVotes, err
+	}
+
+	return threadPollVotes, nil
+}
+
+func (r *threadPollRepository) CountVotesByPollIDAndUserIDTx(pollID string, userID string, tx bun.Tx) (int, error) {
+
+	count, err := tx.NewSelect().
+		Model((*model.ThreadPollVote)(nil)).
+		Where("poll_id = ?", pollID).
+		Where("user_id = ?", userID).
+		Count(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *threadPollRepository) BulkSaveVotesTx(threadPollVotes []model.ThreadPollVote, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().Model(&threadPollVotes).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteVotesByPollIDAndUserIDTx deletes the votes of the user and returns the options they were cast for
+func (r *threadPollRepository) DeleteVotesByPollIDAndUserIDTx(pollID string, userID string, tx bun.Tx) ([]string, error) {
+
+	var optionIDs []string
+
+	err := tx.NewDelete().
+		Model((*model.ThreadPollVote)(nil)).
+		Where("poll_id = ?", pollID).
+		Where("user_id = ?", userID).
+		Returning("option_id").
+		Scan(context.Background(), &optionIDs)
+	if err != nil {
+		return optionIDs, err
+	}
+
+	return optionIDs, nil
+}
+
+func (r *threadPollRepository) IncrementVoterCountTx(pollID string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE thread_poll SET voter_count = voter_count + 1 WHERE id = ?", pollID).
+		Exec(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *threadPollRepository) DecrementVoterCountTx(pollID string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE thread_poll SET voter_count = voter_count - 1 WHERE id = ?", pollID).
+		Exec(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *threadPollRepository) IncrementOptionsVoteCountTx(optionIDs []string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE thread_poll_option SET vote_count = vote_count + 1 WHERE id IN (?)", bun.In(optionIDs)).
+		Exec(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *threadPollRepository) DecrementOptionsVoteCountTx(optionIDs []string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE thread_poll_option SET vote_count = vote_count - 1 WHERE id IN (?)", bun.In(optionIDs)).
+		Exec(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
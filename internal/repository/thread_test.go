@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDB builds the queries without connecting, the connector only dials once a query is executed
+func newTestDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector()), pgdialect.New())
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
+
+func TestCommentCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 15, 123456789, time.UTC)
+	id := "0b6c3f0e-8f5a-4a57-9c1e-2f7b7b1a9d11"
+
+	tests := []struct {
+		name          string
+		sortBy        string
+		sortValue     float64
+		wantCursor    string
+		wantCondition string
+		wantOrder     string
+	}{
+		{
+			name:          "newest pages by created_at",
+			sortBy:        constants.COMMENT_SORT_NEWEST,
+			sortValue:     42,
+			wantCursor:    "2024-05-01T10:30:15.123456789Z_" + id,
+			wantCondition: "(thc.created_at, thc.id) <= ('2024-05-01T10:30:15.123456789Z', '" + id + "')",
+			wantOrder:     "ORDER BY thc.created_at desc, thc.id desc",
+		},
+		{
+			name:          "unknown sort falls back to newest",
+			sortBy:        "",
+			sortValue:     42,
+			wantCursor:    "2024-05-01T10:30:15.123456789Z_" + id,
+			wantCondition: "(thc.created_at, thc.id) <= ('2024-05-01T10:30:15.123456789Z', '" + id + "')",
+			wantOrder:     "ORDER BY thc.created_at desc, thc.id desc",
+		},
+		{
+			name:          "top pages by score",
+			sortBy:        constants.COMMENT_SORT_TOP,
+			sortValue:     12,
+			wantCursor:    "12_" + id,
+			wantCondition: "thc.id) <= ('12'::float8, '" + id + "')",
+			wantOrder:     "ORDER BY sort_value desc, thc.id desc",
+		},
+		{
+			name:          "top keeps a negative score",
+			sortBy:        constants.COMMENT_SORT_TOP,
+			sortValue:     -3,
+			wantCursor:    "-3_" + id,
+			wantCondition: "thc.id) <= ('-3'::float8, '" + id + "')",
+			wantOrder:     "ORDER BY sort_value desc, thc.id desc",
+		},
+		{
+			name:          "controversial keeps a fractional score",
+			sortBy:        constants.COMMENT_SORT_CONTROVERSIAL,
+			sortValue:     7.483314773547883,
+			wantCursor:    "7.483314773547883_" + id,
+			wantCondition: "thc.id) <= ('7.483314773547883'::float8, '" + id + "')",
+			wantOrder:     "ORDER BY sort_value desc, thc.id desc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := getCommentCursor(tt.sortBy, createdAt, tt.sortValue, id)
+
+			if cursor != tt.wantCursor {
+				t.Fatalf("getCommentCursor() = %s, want %s", cursor, tt.wantCursor)
+			}
+
+			value, cursorID := pkg.GetCursorData(cursor)
+
+			if cursorID != id {
+				t.Errorf("GetCursorData() id = %s, want %s", cursorID, id)
+			}
+
+			if commentSortExpr("", tt.sortBy) == "" {
+				decoded, err := time.Parse(time.RFC3339Nano, value)
+				if err != nil || !decoded.Equal(createdAt) {
+					t.Errorf("GetCursorData() created_at = %s, want %s", value, createdAt)
+				}
+			} else {
+				decoded, err := strconv.ParseFloat(value, 64)
+				if err != nil || decoded != tt.sortValue {
+					t.Errorf("GetCursorData() sort value = %s, want %v", value, tt.sortValue)
+				}
+			}
+
+			query := newTestDB(t).NewSelect().TableExpr("thread_comment AS thc").Column("thc.id")
+
+			applyCommentSort(query, "thc", tt.sortBy, cursor)
+
+			got := query.String()
+
+			if !strings.Contains(got, tt.wantCondition) {
+				t.Errorf("applyCommentSort() query = %s, want it to contain %s", got, tt.wantCondition)
+			}
+
+			if !strings.HasSuffix(got, tt.wantOrder) {
+				t.Errorf("applyCommentSort() query = %s, want it to end with %s", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestApplyCommentSortFirstPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		sortBy        string
+		wantSortValue bool
+	}{
+		{name: "newest", sortBy: constants.COMMENT_SORT_NEWEST},
+		{name: "top", sortBy: constants.COMMENT_SORT_TOP, wantSortValue: true},
+		{name: "controversial", sortBy: constants.COMMENT_SORT_CONTROVERSIAL, wantSortValue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := newTestDB(t).NewSelect().TableExpr("thread_comment AS thc").Column("thc.id")
+
+			applyCommentSort(query, "thc", tt.sortBy, "")
+
+			got := query.String()
+
+			if strings.Contains(got, "WHERE") {
+				t.Errorf("applyCommentSort() query = %s, want no cursor condition on the first page", got)
+			}
+
+			if strings.Contains(got, "AS sort_value") != tt.wantSortValue {
+				t.Errorf("applyCommentSort() query = %s, selects sort_value = %v, want %v", got, !tt.wantSortValue, tt.wantSortValue)
+			}
+		})
+	}
+}
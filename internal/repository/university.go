@@ -7,7 +7,7 @@ import (
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/uptrace/bun"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -21,6 +21,24 @@ func NewUniversityRepository(db *bun.DB) UniversityRepository {
 	}
 }
 
+func (r *universityRepository) GetByID(id string) (model.University, error) {
+
+	var (
+		uni model.University
+	)
+
+	err := r.db.NewSelect().
+		Model(&uni).
+		Where("uni.id = ?", id).
+		Scan(context.Background())
+
+	if err != nil {
+		return uni, err
+	}
+
+	return uni, nil
+}
+
 func (r *universityRepository) GetByDomain(domain string) (model.University, error) {
 
 	var (
@@ -87,20 +105,20 @@ func (r *universityRepository) GetList(req request.GetUniversityRatingListReq) (
 		Limit(req.Limit + 1)
 
 	if req.Search != "" {
-		query.Join("JOIN university AS uni ON uni.id = unir.university_id")
-
-		searchCols := []string{
-			"uni.name",
-			"uni.abbreviated_name",
-			"unir.title",
-			"unir.content",
-			"unir.university_major",
-		}
+		// Rank the university name lower than the rating itself so ratings that mention the query come first
+		rankExpr := "(ts_rank_cd(unir.search_vector, websearch_to_tsquery('simple', ?)) + ts_rank_cd(searched_uni.search_vector, websearch_to_tsquery('simple', ?)) * 0.5)::float8"
 
-		query.Where("CONCAT("+strings.Join(searchCols, ", ")+") ILIKE ?", "%"+req.Search+"%")
-	}
+		query.Join("JOIN university AS searched_uni ON searched_uni.id = unir.university_id")
+		query.ColumnExpr(rankExpr+" AS search_rank", req.Search, req.Search)
+		query.Where("(unir.search_vector @@ websearch_to_tsquery('simple', ?) OR searched_uni.search_vector @@ websearch_to_tsquery('simple', ?))", req.Search, req.Search)
 
-	if req.Cursor != "" {
+		if req.Cursor != "" {
+			searchRank, id := pkg.GetCursorData(req.Cursor)
+			query.Where("("+rankExpr+", unir.id) <= (?, ?)", req.Search, req.Search, searchRank, id)
+		}
+
+		query.Order("search_rank desc", "unir.id desc")
+	} else if req.Cursor != "" {
 		createdAt, id := pkg.GetCursorData(req.Cursor)
 		query.Where("(unir.created_at, unir.id) <= (?, ?)", createdAt, id)
 
@@ -117,7 +135,13 @@ func (r *universityRepository) GetList(req request.GetUniversityRatingListReq) (
 
 	if len(uniRatings) > req.Limit {
 		lastUniRating := uniRatings[len(uniRatings)-1]
-		nextCursor = fmt.Sprintf("%s_%s", lastUniRating.CreatedAt.Format(time.RFC3339Nano), lastUniRating.ID)
+
+		if req.Search != "" {
+			nextCursor = fmt.Sprintf("%s_%s", strconv.FormatFloat(lastUniRating.SearchRank, 'g', -1, 64), lastUniRating.ID)
+		} else {
+			nextCursor = fmt.Sprintf("%s_%s", lastUniRating.CreatedAt.Format(time.RFC3339Nano), lastUniRating.ID)
+		}
+
 		uniRatings = uniRatings[:req.Limit] // Trim to the requested limit
 	}
 
@@ -114,6 +114,10 @@ func (r *userRepository) GetUserProfileByEmail(email string) (*model.User, error
 		Model(user).
 		ExcludeColumn("password").
 		Relation("University").
+		Relation("Badges", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("ub.created_at asc", "ub.id asc")
+		}).
+		Relation("Badges.Badge").
 		Where("email = ?", email).
 		Scan(context.Background())
 	if err != nil {
@@ -123,6 +127,27 @@ func (r *userRepository) GetUserProfileByEmail(email string) (*model.User, error
 	return user, nil
 }
 
+func (r *userRepository) GetPublicProfileByUsername(username string) (*model.User, error) {
+	user := &model.User{}
+
+	err := r.db.NewSelect().
+		Model(user).
+		ExcludeColumn("password").
+		Relation("University").
+		Relation("Badges", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("ub.created_at asc", "ub.id asc")
+		}).
+		Relation("Badges.Badge").
+		Where("username = ?", username).
+		Where("is_banned = FALSE").
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (r *userRepository) GetByEmail(email string) (*model.User, error) {
 	user := &model.User{}
 
@@ -288,7 +313,8 @@ func (r *userRepository) UpdateUserTx(id string, updateValues map[string]interfa
 	return nil
 }
 
-func (r *userRepository) IncrementUserReputationPointsTx(id string, updateValues map[string]interface{}, tx bun.Tx) error {
+// AddUserReputationPointsTx keeps the cached total in sync with the ledger, reputation_points is a signed delta
+func (r *userRepository) AddUserReputationPointsTx(id string, updateValues map[string]interface{}, tx bun.Tx) error {
 
 	_, err := tx.NewRaw(`update
 									"user"
@@ -306,16 +332,118 @@ func (r *userRepository) IncrementUserReputationPointsTx(id string, updateValues
 	return nil
 }
 
-func (r *userRepository) DecrementUserReputationPointsTx(id string, updateValues map[string]interface{}, tx bun.Tx) error {
+func (r *userRepository) IncrementFailedLoginAttempts(id string) (int, error) {
+	var failedLoginAttempts int
+
+	err := r.db.NewRaw(`update
+							"user"
+						set
+							failed_login_attempts = failed_login_attempts + 1
+						where
+							id = ?
+						returning failed_login_attempts`, id).
+		Scan(context.Background(), &failedLoginAttempts)
+	if err != nil {
+		return 0, err
+	}
 
-	_, err := tx.NewRaw(`update
-									"user"
-								set 
-									reputation_points = reputation_points - ?, 
-									updated_at = now(), 
-									updated_by = ?
-								where
-								id = ?`, updateValues["reputation_points"], updateValues["updated_by"], id).
+	return failedLoginAttempts, nil
+}
+
+func (r *userRepository) SaveUserSession(userSession *model.UserSession) error {
+
+	_, err := r.db.NewInsert().Model(userSession).Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetUserSessionByID(id string) (*model.UserSession, error) {
+	userSession := &model.UserSession{}
+
+	err := r.db.NewSelect().
+		Model(userSession).
+		Where("id = ?", id).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return userSession, nil
+}
+
+func (r *userRepository) GetUserSessionByRefreshToken(refreshToken string) (*model.UserSession, error) {
+	userSession := &model.UserSession{}
+
+	err := r.db.NewSelect().
+		Model(userSession).
+		Where("refresh_token = ?", refreshToken).
+		Scan(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return userSession, nil
+}
+
+func (r *userRepository) UpdateUserSessionByID(id string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("user_session").
+		Where("id = ?", id).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) RotateUserSessionRefreshToken(id string, refreshToken string, updateValues map[string]interface{}) (bool, error) {
+
+	res, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("user_session").
+		Where("id = ?", id).
+		Where("refresh_token = ?", refreshToken).
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *userRepository) RevokeUserSessionsByUserID(userID string, updateValues map[string]interface{}) error {
+
+	_, err := r.db.NewUpdate().
+		Model(&updateValues).
+		TableExpr("user_session").
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) RevokeUserSessionsByUserIDTx(userID string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("user_session").
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
 		Exec(context.Background())
 	if err != nil {
 		return err
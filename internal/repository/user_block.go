@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type userBlockRepository struct {
+	db *bun.DB
+}
+
+func NewUserBlockRepository(db *bun.DB) UserBlockRepository {
+	return &userBlockRepository{
+		db: db,
+	}
+}
+
+func (r *userBlockRepository) SaveTx(userBlock *model.UserBlock, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().
+		Model(userBlock).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userBlockRepository) UpdateByIDTx(userBlockID string, updateValues map[string]interface{}, tx bun.Tx) error {
+
+	_, err := tx.NewUpdate().
+		Model(&updateValues).
+		TableExpr("user_block").
+		Where("id = ?", userBlockID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userBlockRepository) DeleteByBlockedUserID(userID string, blockedUserID string) (bool, error) {
+
+	res, err := r.db.NewDelete().
+		TableExpr("user_block").
+		Where("user_id = ?", userID).
+		Where("blocked_user_id = ?", blockedUserID).
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *userBlockRepository) GetByBlockedUserID(userID string, blockedUserID string) (model.UserBlock, error) {
+
+	var (
+		userBlock model.UserBlock
+	)
+
+	err := r.db.NewSelect().
+		Model(&userBlock).
+		Where("ubl.user_id = ?", userID).
+		Where("ubl.blocked_user_id = ?", blockedUserID).
+		Scan(context.Background())
+	if err != nil {
+		return userBlock, err
+	}
+
+	return userBlock, nil
+}
+
+func (r *userBlockRepository) GetList(req request.GetUserBlockListReq) ([]model.UserBlock, pkg.Pagination, error) {
+
+	var (
+		userBlocks = []model.UserBlock{}
+		nextCursor string
+	)
+
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
+		Model(&userBlocks).
+		Relation("BlockedUser", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username", "avatar_url")
+		}).
+		Where("ubl.user_id = ?", req.UserID).
+		Limit(req.Limit + 1)
+
+	if req.Type != "" {
+		query.Where("ubl.type = ?", req.Type)
+	}
+
+	if req.Cursor != "" {
+		createdAt, userBlockID := pkg.GetCursorData(req.Cursor)
+
+		query.Where("(ubl.created_at, ubl.id) <= (?, ?)", createdAt, userBlockID)
+	}
+
+	query.Order("ubl.created_at desc", "ubl.id desc")
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return userBlocks, pagination, err
+	}
+
+	if len(userBlocks) > req.Limit {
+		lastUserBlock := userBlocks[len(userBlocks)-1]
+
+		nextCursor = fmt.Sprintf("%s_%s", lastUserBlock.CreatedAt.Format(time.RFC3339Nano), lastUserBlock.ID)
+
+		userBlocks = userBlocks[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return userBlocks, pagination, nil
+}
+
+// GetUserIDsBlocking returns the users among userIDs who blocked or muted blockedUserID
+func (r *userBlockRepository) GetUserIDsBlocking(blockedUserID string, userIDs []string) ([]string, error) {
+
+	var (
+		blockingUserIDs = []string{}
+	)
+
+	if len(userIDs) == 0 {
+		return blockingUserIDs, nil
+	}
+
+	err := r.db.NewSelect().
+		Model((*model.UserBlock)(nil)).
+		Column("ubl.user_id").
+		Where("ubl.blocked_user_id = ?", blockedUserID).
+		Where("ubl.user_id IN (?)", bun.In(userIDs)).
+		Scan(context.Background(), &blockingUserIDs)
+	if err != nil {
+		return blockingUserIDs, err
+	}
+
+	return blockingUserIDs, nil
+}
+
+// hiddenUsersQuery selects the ids of the users blocked or muted by the user, their content is left out of the user's lists
+func hiddenUsersQuery(db *bun.DB, userID string) *bun.SelectQuery {
+	return db.NewSelect().
+		TableExpr("user_block AS hub").
+		Column("hub.blocked_user_id").
+		Where("hub.user_id = ?", userID)
+}
+
+// blockingUsersQuery selects the ids of the users who blocked the user, their content is left out of the user's feed
+func blockingUsersQuery(db *bun.DB, userID string) *bun.SelectQuery {
+	return db.NewSelect().
+		TableExpr("user_block AS bub").
+		Column("bub.user_id").
+		Where("bub.blocked_user_id = ?", userID).
+		Where("bub.type = ?", constants.USER_BLOCK_TYPE_BLOCK)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type userFollowerRepository struct {
+	db *bun.DB
+}
+
+func NewUserFollowerRepository(db *bun.DB) UserFollowerRepository {
+	return &userFollowerRepository{
+		db: db,
+	}
+}
+
+func (r *userFollowerRepository) SaveTx(userFollower *model.UserFollower, tx bun.Tx) error {
+
+	_, err := tx.NewInsert().
+		Model(userFollower).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userFollowerRepository) DeleteTx(userID string, followerID string, tx bun.Tx) (bool, error) {
+
+	res, err := tx.NewDelete().
+		TableExpr("user_follower").
+		Where("user_id = ?", userID).
+		Where("follower_id = ?", followerID).
+		Exec(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *userFollowerRepository) GetByUserIDAndFollowerID(userID string, followerID string) (model.UserFollower, error) {
+
+	var (
+		userFollower model.UserFollower
+	)
+
+	err := r.db.NewSelect().
+		Model(&userFollower).
+		Where("uf.user_id = ?", userID).
+		Where("uf.follower_id = ?", followerID).
+		Scan(context.Background())
+	if err != nil {
+		return userFollower, err
+	}
+
+	return userFollower, nil
+}
+
+// GetFollowers returns a page of the followers of req.ProfileUserID, newest follow first
+func (r *userFollowerRepository) GetFollowers(req request.GetUserFollowListReq) ([]model.UserFollower, pkg.Pagination, error) {
+	return r.getList("Follower", "uf.user_id", req)
+}
+
+// GetFollowing returns a page of the users followed by req.ProfileUserID, newest follow first
+func (r *userFollowerRepository) GetFollowing(req request.GetUserFollowListReq) ([]model.UserFollower, pkg.Pagination, error) {
+	return r.getList("User", "uf.follower_id", req)
+}
+
+func (r *userFollowerRepository) getList(relation string, profileUserColumn string, req request.GetUserFollowListReq) ([]model.UserFollower, pkg.Pagination, error) {
+
+	var (
+		userFollowers = []model.UserFollower{}
+		nextCursor    string
+	)
+
+	pagination := pkg.Pagination{}
+
+	query := r.db.NewSelect().
+		Model(&userFollowers).
+		Relation(relation, func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username", "avatar_url")
+		}).
+		Where(profileUserColumn+" = ?", req.ProfileUserID).
+		Limit(req.Limit + 1)
+
+	if req.Cursor != "" {
+		createdAt, userFollowerID := pkg.GetCursorData(req.Cursor)
+
+		query.Where("(uf.created_at, uf.id) <= (?, ?)", createdAt, userFollowerID)
+	}
+
+	query.Order("uf.created_at desc", "uf.id desc")
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return userFollowers, pagination, err
+	}
+
+	if len(userFollowers) > req.Limit {
+		lastUserFollower := userFollowers[len(userFollowers)-1]
+
+		nextCursor = fmt.Sprintf("%s_%s", lastUserFollower.CreatedAt.Format(time.RFC3339Nano), lastUserFollower.ID)
+
+		userFollowers = userFollowers[:req.Limit] // Trim to the requested limit
+	}
+
+	pagination.CurrentCursor = req.Cursor
+	pagination.NextCursor = nextCursor
+
+	return userFollowers, pagination, nil
+}
+
+// GetNotifiedFollowers returns a page of the followers of the user with their devices, ordered by id after afterID, to
+// be notified of the user's new threads. Followers who blocked or muted the user or were blocked by the user are left
+// out, a non nil universityID only keeps the students of that university
+func (r *userFollowerRepository) GetNotifiedFollowers(userID string, universityID *string, afterID string, limit int) ([]model.UserFollower, error) {
+
+	var (
+		userFollowers = []model.UserFollower{}
+	)
+
+	blockingUsers := r.db.NewSelect().
+		TableExpr("user_block AS bub").
+		Column("bub.user_id").
+		Where("bub.blocked_user_id = ?", userID)
+
+	blockedUsers := r.db.NewSelect().
+		TableExpr("user_block AS bdu").
+		Column("bdu.blocked_user_id").
+		Where("bdu.user_id = ?", userID).
+		Where("bdu.type = ?", constants.USER_BLOCK_TYPE_BLOCK)
+
+	query := r.db.NewSelect().
+		Model(&userFollowers).
+		Relation("Follower", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "username", "university_id")
+		}).
+		Relation("Follower.Devices").
+		Where("uf.user_id = ?", userID).
+		Where("uf.follower_id NOT IN (?)", blockingUsers).
+		Where("uf.follower_id NOT IN (?)", blockedUsers).
+		Order("uf.id").
+		Limit(limit)
+
+	if universityID != nil {
+		query.Where("follower.university_id = ?", *universityID)
+	}
+
+	if afterID != "" {
+		query.Where("uf.id > ?", afterID)
+	}
+
+	err := query.Scan(context.Background())
+	if err != nil {
+		return userFollowers, err
+	}
+
+	return userFollowers, nil
+}
+
+func (r *userFollowerRepository) IncrementFollowCountsTx(userID string, followerID string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE \"user\" SET followers_count = followers_count + 1 WHERE id = ?", userID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewRaw("UPDATE \"user\" SET following_count = following_count + 1 WHERE id = ?", followerID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userFollowerRepository) DecrementFollowCountsTx(userID string, followerID string, tx bun.Tx) error {
+
+	_, err := tx.NewRaw("UPDATE \"user\" SET followers_count = followers_count - 1 WHERE id = ?", userID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewRaw("UPDATE \"user\" SET following_count = following_count - 1 WHERE id = ?", followerID).
+		Exec(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
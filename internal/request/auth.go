@@ -9,6 +9,9 @@ type RegisterUserReq struct {
 type LoginUserReq struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
 }
 
 type VerifyEmailReq struct {
@@ -29,3 +32,22 @@ type VerifyResetPasswordReq struct {
 type SendResetPasswordLinkReq struct {
 	Email string `json:"email" binding:"required"`
 }
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type LogoutReq struct {
+	SessionID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type LogoutAllDevicesReq struct {
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
@@ -0,0 +1,55 @@
+package request
+
+type SaveBookmarkReq struct {
+	TargetType   string  `json:"target_type" binding:"required"`
+	TargetID     string  `json:"target_id" binding:"required,uuid"`
+	CollectionID *string `json:"collection_id" binding:"omitempty,uuid"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type DeleteBookmarkReq struct {
+	TargetType string `json:"-"`
+	TargetID   string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetBookmarkListReq struct {
+	CollectionID string `json:"collection_id"`
+	TargetType   string `json:"target_type"`
+	Limit        int    `json:"limit"`
+	Cursor       string `json:"cursor"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type CreateBookmarkCollectionReq struct {
+	Name string `json:"name" binding:"required,max=100"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type UpdateBookmarkCollectionReq struct {
+	CollectionID string `json:"-"`
+	Name         string `json:"name" binding:"required,max=100"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type DeleteBookmarkCollectionReq struct {
+	CollectionID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetBookmarkCollectionsReq struct {
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
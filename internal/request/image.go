@@ -0,0 +1,28 @@
+package request
+
+import "github.com/andibalo/meowhasiswa-be/internal/model"
+
+type UploadImageReq struct {
+	File    model.File
+	AltText *string
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type PresignImageUploadReq struct {
+	ContentType    string  `json:"content_type" binding:"required"`
+	SizeBytes      int64   `json:"size_bytes" binding:"required,min=1"`
+	ChecksumSHA256 string  `json:"checksum_sha256" binding:"required,len=64,hexadecimal"`
+	AltText        *string `json:"alt_text" binding:"omitempty,max=500"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type ConfirmImageUploadReq struct {
+	UploadID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
@@ -0,0 +1,12 @@
+package request
+
+type GetLeaderboardReq struct {
+	Period       string `json:"period"`
+	UniversityID string `json:"university_id"`
+	SubThreadID  string `json:"subthread_id"`
+	Limit        int    `json:"limit"`
+
+	UserID                   string  `json:"-"`
+	UserUniversityID         *string `json:"-"`
+	CanAccessAllUniversities bool    `json:"-"`
+}
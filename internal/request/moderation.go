@@ -0,0 +1,44 @@
+package request
+
+type CreateReportReq struct {
+	ContentType string `json:"content_type" binding:"required,oneof=THREAD THREAD_COMMENT THREAD_COMMENT_REPLY UNIVERSITY_RATING"`
+	ContentID   string `json:"content_id" binding:"required"`
+	Reason      string `json:"reason" binding:"required,oneof=SPAM HARASSMENT HATE_SPEECH MISINFORMATION INAPPROPRIATE_CONTENT OTHER"`
+	Description string `json:"description" binding:"max=255"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetModerationQueueReq struct {
+	Status         string `json:"status"`
+	ContentType    string `json:"content_type"`
+	Reason         string `json:"reason"`
+	MinReportCount int    `json:"min_report_count"`
+	Limit          int    `json:"limit"`
+	Cursor         string `json:"cursor"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetContentReportsReq struct {
+	ContentType string `json:"content_type"`
+	ContentID   string `json:"content_id"`
+	Status      string `json:"status"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type ResolveReportReq struct {
+	ContentType string `json:"content_type" binding:"required,oneof=THREAD THREAD_COMMENT THREAD_COMMENT_REPLY UNIVERSITY_RATING"`
+	ContentID   string `json:"content_id" binding:"required"`
+	Action      string `json:"action" binding:"required,oneof=HIDE_CONTENT WARN_AUTHOR BAN_AUTHOR DISMISS"`
+	// HideContent also hides the reported content when the action is WARN_AUTHOR or BAN_AUTHOR
+	HideContent bool   `json:"hide_content"`
+	Note        string `json:"note" binding:"max=255"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
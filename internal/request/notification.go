@@ -9,3 +9,28 @@ type SendPushNotificationReq struct {
 	Username  string `json:"-"`
 	UserEmail string `json:"-"`
 }
+
+type GetNotificationListReq struct {
+	IsUnreadOnly bool   `json:"is_unread_only"`
+	Limit        int    `json:"limit"`
+	Cursor       string `json:"cursor"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetUnreadNotificationCountReq struct {
+	UserID string `json:"-"`
+}
+
+type MarkNotificationAsReadReq struct {
+	NotificationID string `json:"notification_id"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type MarkAllNotificationsAsReadReq struct {
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
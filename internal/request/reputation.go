@@ -0,0 +1,19 @@
+package request
+
+type GetReputationHistoryReq struct {
+	Username     string `json:"-"`
+	TargetUserID string `json:"-"`
+	Limit        int    `json:"limit"`
+	Cursor       string `json:"cursor"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type RecomputeReputationReq struct {
+	// TargetUserID limits the recompute to a single user, every user is recomputed when it is empty
+	TargetUserID string `json:"user_id" binding:"omitempty,uuid"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
@@ -0,0 +1,26 @@
+package request
+
+type GetUserRolesReq struct {
+	TargetUserID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GrantRoleReq struct {
+	TargetUserID string  `json:"user_id" binding:"required"`
+	RoleName     string  `json:"role" binding:"required"`
+	ScopeID      *string `json:"scope_id"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type RevokeRoleReq struct {
+	TargetUserID string  `json:"user_id" binding:"required"`
+	RoleName     string  `json:"role" binding:"required"`
+	ScopeID      *string `json:"scope_id"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
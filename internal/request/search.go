@@ -0,0 +1,13 @@
+package request
+
+type SearchReq struct {
+	Query  string `json:"_q"`
+	Type   string `json:"type"`
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor"`
+
+	UserID                   string  `json:"-"`
+	UserEmail                string  `json:"-"`
+	UserUniversityID         *string `json:"-"`
+	CanAccessAllUniversities bool    `json:"-"`
+}
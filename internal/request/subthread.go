@@ -1,13 +1,17 @@
 package request
 
+import "time"
+
 type CreateSubThreadReq struct {
 	Name                  string  `json:"name" binding:"required"`
 	Description           string  `json:"description" binding:"required"`
-	ImageUrl              string  `json:"image_url" binding:"required"`
+	ImageUrl              string  `json:"image_url" binding:"required_without=ImageAttachmentID"`
+	ImageAttachmentID     *string `json:"image_attachment_id" binding:"omitempty,uuid"`
 	LabelColor            string  `json:"label_color" binding:"required"`
 	UniversityID          *string `json:"university_id"`
 	IsUniversitySubThread bool    `json:"is_university_subthread"`
 
+	UserID    string `json:"-"`
 	UserEmail string `json:"-"`
 }
 
@@ -15,11 +19,14 @@ type UpdateSubThreadReq struct {
 	SubThreadID           string  `json:"subthread_id"`
 	Name                  string  `json:"name" binding:"required"`
 	Description           string  `json:"description" binding:"required"`
-	ImageUrl              string  `json:"image_url" binding:"required"`
+	ImageUrl              string  `json:"image_url" binding:"required_without=ImageAttachmentID"`
+	ImageAttachmentID     *string `json:"image_attachment_id" binding:"omitempty,uuid"`
 	LabelColor            string  `json:"label_color" binding:"required"`
 	UniversityID          *string `json:"university_id"`
 	IsUniversitySubThread *bool   `json:"is_university_subthread"`
+	IsRevisionPublic      *bool   `json:"is_revision_public"`
 
+	UserID    string `json:"-"`
 	UserEmail string `json:"-"`
 }
 
@@ -59,3 +66,77 @@ type DeleteSubThreadReq struct {
 	Username  string `json:"-"`
 	UserEmail string `json:"-"`
 }
+
+type GetSubThreadModeratorsReq struct {
+	SubThreadID string `json:"-"`
+}
+
+type AddSubThreadModeratorReq struct {
+	SubThreadID  string `json:"-"`
+	TargetUserID string `json:"user_id" binding:"required,uuid"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type RemoveSubThreadModeratorReq struct {
+	SubThreadID  string `json:"-"`
+	TargetUserID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetSubThreadRulesReq struct {
+	SubThreadID string `json:"-"`
+}
+
+type CreateSubThreadRuleReq struct {
+	SubThreadID string  `json:"-"`
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type UpdateSubThreadRuleReq struct {
+	SubThreadID string  `json:"-"`
+	RuleID      string  `json:"-"`
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+	Position    *int    `json:"position" binding:"omitempty,min=1"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type DeleteSubThreadRuleReq struct {
+	SubThreadID string `json:"-"`
+	RuleID      string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetSubThreadBansReq struct {
+	SubThreadID string `json:"-"`
+}
+
+type BanSubThreadUserReq struct {
+	SubThreadID  string     `json:"-"`
+	TargetUserID string     `json:"user_id" binding:"required,uuid"`
+	Reason       *string    `json:"reason" binding:"omitempty,max=255"`
+	ExpiredAt    *time.Time `json:"expired_at"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type UnbanSubThreadUserReq struct {
+	SubThreadID  string `json:"-"`
+	TargetUserID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
@@ -1,26 +1,59 @@
 package request
 
+import "time"
+
 type CreateThreadReq struct {
-	SubThreadID    string `json:"subthread_id" binding:"required"`
-	Title          string `json:"title" binding:"required"`
-	Content        string `json:"content" binding:"required"`
-	ContentSummary string `json:"content_summary" binding:"required"`
+	SubThreadID    string               `json:"subthread_id" binding:"required"`
+	Title          string               `json:"title" binding:"required"`
+	Content        string               `json:"content" binding:"required"`
+	ContentSummary string               `json:"content_summary" binding:"required"`
+	Poll           *CreateThreadPollReq `json:"poll"`
+	AttachmentIDs  []string             `json:"attachment_ids" binding:"omitempty,max=10,dive,uuid"`
 
 	UserID    string `json:"-"`
 	UserEmail string `json:"-"`
 }
 
+type CreateThreadPollReq struct {
+	Question         string     `json:"question" binding:"required,max=255"`
+	Options          []string   `json:"options" binding:"required,min=2,max=10,dive,required,max=255"`
+	IsMultipleChoice bool       `json:"is_multiple_choice"`
+	ClosesAt         *time.Time `json:"closes_at"`
+}
+
+type VoteThreadPollReq struct {
+	ThreadID  string   `json:"-"`
+	OptionIDs []string `json:"option_ids" binding:"required,min=1,max=10,dive,required"`
+
+	UserID    string `json:"-"`
+	Username  string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type UnVoteThreadPollReq struct {
+	ThreadID string `json:"-"`
+
+	UserID    string `json:"-"`
+	Username  string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
 type GetThreadListReq struct {
 	Search              string `json:"_q"`
 	IsTrending          bool   `json:"is_trending"`
 	IsUserFollowing     bool   `json:"is_user_following"`
+	IsFeed              bool   `json:"is_feed"`
+	SubThreadID         string `json:"subthread_id"`
 	UserIDParam         string `json:"user_id"`
 	Limit               int    `json:"limit"`
 	Cursor              string `json:"cursor"`
 	IncludeUserActivity bool   `json:"include_user_activity"`
 
-	UserID    string `json:"-"`
-	UserEmail string `json:"-"`
+	UserID                   string   `json:"-"`
+	UserEmail                string   `json:"-"`
+	UserUniversityID         *string  `json:"-"`
+	CanAccessAllUniversities bool     `json:"-"`
+	ExcludeThreadIDs         []string `json:"-"`
 }
 
 type GetThreadDetailReq struct {
@@ -48,7 +81,8 @@ type DislikeThreadReq struct {
 }
 
 type CommentThreadReq struct {
-	Content string `json:"content" binding:"required"`
+	Content       string   `json:"content" binding:"required"`
+	AttachmentIDs []string `json:"attachment_ids" binding:"omitempty,max=10,dive,uuid"`
 
 	ThreadID  string `json:"-"`
 	UserID    string `json:"-"`
@@ -58,6 +92,21 @@ type CommentThreadReq struct {
 
 type GetThreadCommentsReq struct {
 	ThreadID string `json:"-"`
+	SortBy   string `json:"sort_by"`
+	Limit    int    `json:"limit"`
+	Cursor   string `json:"cursor"`
+
+	UserID    string `json:"-"`
+	Username  string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetThreadCommentRepliesReq struct {
+	CommentID     string `json:"-"`
+	ParentReplyID string `json:"parent_reply_id"`
+	SortBy        string `json:"sort_by"`
+	Limit         int    `json:"limit"`
+	Cursor        string `json:"cursor"`
 
 	UserID    string `json:"-"`
 	Username  string `json:"-"`
@@ -65,8 +114,10 @@ type GetThreadCommentsReq struct {
 }
 
 type ReplyCommentReq struct {
-	Content  string `json:"content" binding:"required"`
-	ThreadID string `json:"thread_id" binding:"required"`
+	Content       string   `json:"content" binding:"required"`
+	ThreadID      string   `json:"thread_id" binding:"required"`
+	ParentReplyID *string  `json:"parent_reply_id"`
+	AttachmentIDs []string `json:"attachment_ids" binding:"omitempty,max=10,dive,uuid"`
 
 	CommentID string `json:"-"`
 	UserID    string `json:"-"`
@@ -85,6 +136,13 @@ type UpdateThreadReq struct {
 	UserEmail string `json:"-"`
 }
 
+type GetThreadRevisionsReq struct {
+	ThreadID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
 type DeleteThreadReq struct {
 	ThreadID string `json:"thread_id"`
 
@@ -93,6 +151,27 @@ type DeleteThreadReq struct {
 	UserEmail string `json:"-"`
 }
 
+type PinThreadReq struct {
+	ThreadID string `json:"thread_id"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type LockThreadReq struct {
+	ThreadID string `json:"thread_id"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type ArchiveThreadReq struct {
+	ThreadID string `json:"thread_id"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
 type LikeCommentReq struct {
 	ThreadID  string `json:"thread_id"`
 	IsReply   bool   `json:"is_reply"`
@@ -130,6 +209,13 @@ type UpdateThreadCommentReq struct {
 	UserEmail string `json:"-"`
 }
 
+type GetThreadCommentRevisionsReq struct {
+	CommentID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
 type DeleteThreadCommentReplyReq struct {
 	CommentID string `json:"comment_id"`
 
@@ -155,6 +241,14 @@ type SubscribeThreadReq struct {
 	UserEmail string `json:"-"`
 }
 
+type SubscribeThreadEventsReq struct {
+	ThreadID string `json:"-"`
+
+	UserID    string `json:"-"`
+	Username  string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
 type UnSubscribeThreadReq struct {
 	ThreadID string `json:"-"`
 
@@ -13,6 +13,26 @@ type GetUserProfileReq struct {
 	UserEmail string `json:"-"`
 }
 
+type UpdateUserProfileReq struct {
+	AvatarAttachmentID *string `json:"avatar_attachment_id" binding:"omitempty,uuid"`
+	RemoveAvatar       bool    `json:"remove_avatar"`
+	Bio                *string `json:"bio" binding:"omitempty,max=500"`
+	Major              *string `json:"major" binding:"omitempty,max=255"`
+	GraduationYear     *int    `json:"graduation_year" binding:"omitempty,min=1950,max=2100"`
+	ProfileVisibility  *string `json:"profile_visibility" binding:"omitempty,oneof=PUBLIC UNIVERSITY PRIVATE"`
+	HideThreads        *bool   `json:"hide_threads"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetPublicUserProfileReq struct {
+	Username string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
 type CreateUserDeviceReq struct {
 	Brand                string `json:"brand"`
 	Type                 string `json:"type"`
@@ -44,3 +64,51 @@ type UnBanUserReq struct {
 	UserID    string `json:"-"`
 	UserEmail string `json:"-"`
 }
+
+type BlockUserReq struct {
+	BlockedUserID string `json:"user_id" binding:"required,uuid"`
+	Type          string `json:"type" binding:"required,oneof=BLOCK MUTE"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type UnblockUserReq struct {
+	BlockedUserID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetUserBlockListReq struct {
+	Type   string `json:"type"`
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type FollowUserReq struct {
+	FollowedUserID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type UnFollowUserReq struct {
+	FollowedUserID string `json:"-"`
+
+	UserID    string `json:"-"`
+	UserEmail string `json:"-"`
+}
+
+type GetUserFollowListReq struct {
+	Username string `json:"-"`
+	Limit    int    `json:"limit"`
+	Cursor   string `json:"cursor"`
+
+	ProfileUserID string `json:"-"`
+	UserID        string `json:"-"`
+	UserEmail     string `json:"-"`
+}
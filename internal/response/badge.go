@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+type UserBadge struct {
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	IconURL     *string `json:"icon_url"`
+	// ScopeType and ScopeID identify the subthread a scoped badge such as top contributor was earned in
+	ScopeType *string   `json:"scope_type"`
+	ScopeID   *string   `json:"scope_id"`
+	EarnedAt  time.Time `json:"earned_at"`
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type BookmarkData struct {
+	ID           string    `json:"id"`
+	TargetType   string    `json:"target_type"`
+	TargetID     string    `json:"target_id"`
+	ThreadID     string    `json:"thread_id"`
+	ThreadTitle  string    `json:"thread_title"`
+	CollectionID *string   `json:"collection_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	// Thread is set for thread bookmarks, Comment for comment and reply bookmarks
+	Thread  *ThreadListData  `json:"thread,omitempty"`
+	Comment *BookmarkComment `json:"comment,omitempty"`
+}
+
+type BookmarkComment struct {
+	ID        string       `json:"id"`
+	UserID    string       `json:"user_id"`
+	UserName  string       `json:"username"`
+	Content   string       `json:"content"`
+	CreatedAt time.Time    `json:"created_at"`
+	EditedAt  bun.NullTime `json:"edited_at"`
+}
+
+type GetBookmarkListResponse struct {
+	Data []BookmarkData `json:"bookmarks"`
+	Meta PaginationMeta `json:"meta"`
+}
+
+type GetBookmarkCollectionsResponse struct {
+	Data []model.BookmarkCollection `json:"collections"`
+}
@@ -11,24 +11,36 @@ const (
 	DuplicateUser     Code = "CS0033"
 	NotFound          Code = "CS0034"
 
-	Unauthorized   Code = "CS0502"
-	Forbidden      Code = "CS0503"
-	GatewayTimeout Code = "CS0048"
+	Unauthorized        Code = "CS0502"
+	Forbidden           Code = "CS0503"
+	TooManyRequests     Code = "CS0504"
+	GatewayTimeout      Code = "CS0048"
+	SubThreadBanned     Code = "CS0505"
+	NotUniversityMember Code = "CS0506"
+	ThreadLocked        Code = "CS0507"
+	ThreadArchived      Code = "CS0508"
+	UserBlocked         Code = "CS0509"
 )
 
 type Code string
 
 var codeMap = map[Code]string{
-	Success:           "success",
-	Failed:            "failed",
-	Pending:           "pending",
-	BadRequest:        "bad or invalid request",
-	Unauthorized:      "Unauthorized Token",
-	GatewayTimeout:    "Gateway Timeout",
-	ServerError:       "Internal Server Error",
-	InvalidInputParam: "Other invalid argument",
-	DuplicateUser:     "duplicate user",
-	NotFound:          "Not found",
+	Success:             "success",
+	Failed:              "failed",
+	Pending:             "pending",
+	BadRequest:          "bad or invalid request",
+	Unauthorized:        "Unauthorized Token",
+	GatewayTimeout:      "Gateway Timeout",
+	ServerError:         "Internal Server Error",
+	InvalidInputParam:   "Other invalid argument",
+	DuplicateUser:       "duplicate user",
+	NotFound:            "Not found",
+	TooManyRequests:     "Too many requests",
+	SubThreadBanned:     "Banned from the subthread",
+	NotUniversityMember: "Not a student of the university",
+	ThreadLocked:        "Thread is locked",
+	ThreadArchived:      "Thread is archived",
+	UserBlocked:         "User is blocked",
 }
 
 func (c Code) AsString() string {
@@ -1,6 +1,12 @@
 package response
 
+import "time"
+
 type UploadImageResp struct {
+	ID          string
+	Width       *int
+	Height      *int
+	Variants    []ImageVariant
 	URL         string
 	Name        string
 	ETag        string
@@ -10,3 +16,19 @@ type UploadImageResp struct {
 	Tags        string
 	Bucket      string
 }
+
+type ImageVariant struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+type PresignImageUploadResp struct {
+	UploadID  string            `json:"upload_id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,10 @@
+package response
+
+import "github.com/andibalo/meowhasiswa-be/internal/model"
+
+type GetLeaderboardResponse struct {
+	Period       string                   `json:"period"`
+	UniversityID *string                  `json:"university_id"`
+	SubThreadID  *string                  `json:"subthread_id"`
+	Data         []model.LeaderboardEntry `json:"users"`
+}
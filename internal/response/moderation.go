@@ -0,0 +1,8 @@
+package response
+
+import "github.com/andibalo/meowhasiswa-be/internal/model"
+
+type GetModerationQueueResponse struct {
+	Data []model.ReportQueueItem `json:"queue"`
+	Meta PaginationMeta          `json:"meta"`
+}
@@ -1 +1,12 @@
 package response
+
+import "github.com/andibalo/meowhasiswa-be/internal/model"
+
+type GetNotificationListResponse struct {
+	Data []model.Notification `json:"notifications"`
+	Meta PaginationMeta       `json:"meta"`
+}
+
+type UnreadNotificationCountResponse struct {
+	UnreadCount int `json:"unread_count"`
+}
@@ -0,0 +1,13 @@
+package response
+
+import "github.com/andibalo/meowhasiswa-be/internal/model"
+
+type GetReputationHistoryResponse struct {
+	ReputationPoints int64                   `json:"reputation_points"`
+	Data             []model.ReputationEvent `json:"events"`
+	Meta             PaginationMeta          `json:"meta"`
+}
+
+type RecomputeReputationResponse struct {
+	UpdatedUsers int64 `json:"updated_users"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import "github.com/andibalo/meowhasiswa-be/internal/model"
+
+type GetContentRevisionsResponse struct {
+	TargetType string                  `json:"target_type"`
+	TargetID   string                  `json:"target_id"`
+	Data       []model.ContentRevision `json:"revisions"`
+}
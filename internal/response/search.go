@@ -0,0 +1,8 @@
+package response
+
+import "github.com/andibalo/meowhasiswa-be/internal/model"
+
+type SearchResponse struct {
+	Data []model.SearchResult `json:"results"`
+	Meta PaginationMeta       `json:"meta"`
+}
@@ -1,6 +1,9 @@
 package response
 
-import "github.com/andibalo/meowhasiswa-be/internal/model"
+import (
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"time"
+)
 
 type GetSubThreadListResponse struct {
 	Data []model.SubThread `json:"subthreads"`
@@ -8,5 +11,14 @@ type GetSubThreadListResponse struct {
 }
 
 type GetSubThreadByIDResponse struct {
-	Data *model.SubThread `json:"subthread"`
+	Data  *model.SubThread      `json:"subthread"`
+	Rules []model.SubThreadRule `json:"rules"`
+}
+
+type SubThreadModerator struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	AvatarURL *string   `json:"avatar_url"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -9,6 +9,7 @@ type ThreadListData struct {
 	ID                        string       `json:"id"`
 	UserID                    string       `json:"user_id"`
 	UserName                  string       `json:"username"`
+	UserBadges                []UserBadge  `json:"user_badges"`
 	UniversityAbbreviatedName *string      `json:"university_abbreviated_name"`
 	UniversityImageURL        *string      `json:"university_image_url"`
 	SubThreadID               string       `json:"subthread_id"`
@@ -18,15 +19,21 @@ type ThreadListData struct {
 	Content                   string       `json:"content"`
 	ContentSummary            string       `json:"content_summary"`
 	IsActive                  bool         `json:"is_active"`
+	IsPinned                  bool         `json:"is_pinned"`
+	IsLocked                  bool         `json:"is_locked"`
+	IsArchived                bool         `json:"is_archived"`
 	LikeCount                 int64        `json:"like_count"`
 	DislikeCount              int64        `json:"dislike_count"`
 	CommentCount              int64        `json:"comment_count"`
 	IsLiked                   bool         `json:"is_liked"`
 	IsDisliked                bool         `json:"is_disliked"`
+	IsBookmarked              bool         `json:"is_bookmarked"`
+	SearchSnippet             *string      `json:"search_snippet,omitempty"`
 	CreatedBy                 string       `json:"created_by"`
 	CreatedAt                 time.Time    `json:"created_at"`
 	UpdatedBy                 *string      `json:"updated_by"`
 	UpdatedAt                 bun.NullTime `json:"updated_at"`
+	EditedAt                  bun.NullTime `json:"edited_at"`
 }
 
 type GetThreadListResponse struct {
@@ -38,6 +45,7 @@ type ThreadDetailData struct {
 	ID                        string       `json:"id"`
 	UserID                    string       `json:"user_id"`
 	UserName                  string       `json:"username"`
+	UserBadges                []UserBadge  `json:"user_badges"`
 	UniversityAbbreviatedName *string      `json:"university_abbreviated_name"`
 	UniversityImageURL        *string      `json:"university_image_url"`
 	SubThreadID               string       `json:"subthread_id"`
@@ -47,16 +55,51 @@ type ThreadDetailData struct {
 	Content                   string       `json:"content"`
 	ContentSummary            string       `json:"content_summary"`
 	IsActive                  bool         `json:"is_active"`
+	IsPinned                  bool         `json:"is_pinned"`
+	IsLocked                  bool         `json:"is_locked"`
+	IsArchived                bool         `json:"is_archived"`
 	LikeCount                 int64        `json:"like_count"`
 	DislikeCount              int64        `json:"dislike_count"`
 	CommentCount              int64        `json:"comment_count"`
 	IsLiked                   bool         `json:"is_liked"`
 	IsDisliked                bool         `json:"is_disliked"`
 	IsSubscribed              bool         `json:"is_subscribed"`
+	Poll                      *ThreadPoll  `json:"poll"`
+	Attachments               []Attachment `json:"attachments"`
 	CreatedBy                 string       `json:"created_by"`
 	CreatedAt                 time.Time    `json:"created_at"`
 	UpdatedBy                 *string      `json:"updated_by"`
 	UpdatedAt                 bun.NullTime `json:"updated_at"`
+	EditedAt                  bun.NullTime `json:"edited_at"`
+}
+
+type Attachment struct {
+	ID          string         `json:"id"`
+	URL         string         `json:"url"`
+	ContentType string         `json:"content_type"`
+	Width       *int           `json:"width"`
+	Height      *int           `json:"height"`
+	AltText     *string        `json:"alt_text"`
+	Position    int            `json:"position"`
+	Variants    []ImageVariant `json:"variants"`
+}
+
+type ThreadPoll struct {
+	ID               string             `json:"id"`
+	Question         string             `json:"question"`
+	IsMultipleChoice bool               `json:"is_multiple_choice"`
+	ClosesAt         bun.NullTime       `json:"closes_at"`
+	IsClosed         bool               `json:"is_closed"`
+	VoterCount       int64              `json:"voter_count"`
+	HasVoted         bool               `json:"has_voted"`
+	Options          []ThreadPollOption `json:"options"`
+}
+
+type ThreadPollOption struct {
+	ID        string `json:"id"`
+	Content   string `json:"content"`
+	VoteCount int64  `json:"vote_count"`
+	IsVoted   bool   `json:"is_voted"`
 }
 
 type ThreadComment struct {
@@ -79,28 +122,31 @@ type GetThreadDetailResponse struct {
 }
 
 type GetThreadCommentsData struct {
-	ID                        string               `json:"id"`
-	ThreadID                  string               `json:"thread_id"`
-	UserID                    string               `json:"user_id"`
-	UserName                  string               `json:"username"`
-	UniversityAbbreviatedName *string              `json:"university_abbreviated_name"`
-	UniversityImageURL        *string              `json:"university_image_url"`
-	Content                   string               `json:"content"`
-	LikeCount                 int64                `json:"like_count"`
-	DislikeCount              int64                `json:"dislike_count"`
-	IsLiked                   bool                 `json:"is_liked"`
-	IsDisliked                bool                 `json:"is_disliked"`
-	Replies                   []ThreadCommentReply `json:"replies"`
-	CreatedBy                 string               `json:"created_by"`
-	CreatedAt                 time.Time            `json:"created_at"`
-	UpdatedBy                 *string              `json:"updated_by"`
-	UpdatedAt                 bun.NullTime         `json:"updated_at"`
+	ID                        string       `json:"id"`
+	ThreadID                  string       `json:"thread_id"`
+	UserID                    string       `json:"user_id"`
+	UserName                  string       `json:"username"`
+	UniversityAbbreviatedName *string      `json:"university_abbreviated_name"`
+	UniversityImageURL        *string      `json:"university_image_url"`
+	Content                   string       `json:"content"`
+	LikeCount                 int64        `json:"like_count"`
+	DislikeCount              int64        `json:"dislike_count"`
+	IsLiked                   bool         `json:"is_liked"`
+	IsDisliked                bool         `json:"is_disliked"`
+	ReplyCount                int64        `json:"reply_count"`
+	Attachments               []Attachment `json:"attachments"`
+	CreatedBy                 string       `json:"created_by"`
+	CreatedAt                 time.Time    `json:"created_at"`
+	UpdatedBy                 *string      `json:"updated_by"`
+	UpdatedAt                 bun.NullTime `json:"updated_at"`
+	EditedAt                  bun.NullTime `json:"edited_at"`
 }
 
 type ThreadCommentReply struct {
 	ID                        string       `json:"id"`
 	ThreadID                  string       `json:"thread_id"`
 	ThreadCommentID           string       `json:"thread_comment_id"`
+	ParentReplyID             *string      `json:"parent_reply_id"`
 	UserID                    string       `json:"user_id"`
 	UserName                  string       `json:"username"`
 	UniversityAbbreviatedName *string      `json:"university_abbreviated_name"`
@@ -110,12 +156,21 @@ type ThreadCommentReply struct {
 	DislikeCount              int64        `json:"dislike_count"`
 	IsLiked                   bool         `json:"is_liked"`
 	IsDisliked                bool         `json:"is_disliked"`
+	ReplyCount                int64        `json:"reply_count"`
+	Attachments               []Attachment `json:"attachments"`
 	CreatedBy                 string       `json:"created_by"`
 	CreatedAt                 time.Time    `json:"created_at"`
 	UpdatedBy                 *string      `json:"updated_by"`
 	UpdatedAt                 bun.NullTime `json:"updated_at"`
+	EditedAt                  bun.NullTime `json:"edited_at"`
 }
 
 type GetThreadCommentsResponse struct {
 	Data []GetThreadCommentsData `json:"thread_comments"`
+	Meta PaginationMeta          `json:"meta"`
+}
+
+type GetThreadCommentRepliesResponse struct {
+	Data []ThreadCommentReply `json:"thread_comment_replies"`
+	Meta PaginationMeta       `json:"meta"`
 }
@@ -0,0 +1,54 @@
+package response
+
+import "time"
+
+// PublicUserProfile is the profile shown to other users, it never carries the email or the account settings
+type PublicUserProfile struct {
+	ID               string             `json:"id"`
+	Username         string             `json:"username"`
+	AvatarURL        *string            `json:"avatar_url"`
+	Bio              *string            `json:"bio"`
+	Major            *string            `json:"major"`
+	GraduationYear   *int               `json:"graduation_year"`
+	ReputationPoints int64              `json:"reputation_points"`
+	FollowersCount   int64              `json:"followers_count"`
+	FollowingCount   int64              `json:"following_count"`
+	IsFollowing      bool               `json:"is_following"`
+	University       *ProfileUniversity `json:"university"`
+	Badges           []UserBadge        `json:"badges"`
+	Threads          []ThreadListData   `json:"threads"`
+	ThreadsMeta      *PaginationMeta    `json:"threads_meta"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+type ProfileUniversity struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	AbbreviatedName string `json:"abbreviated_name"`
+	ImageURL        string `json:"image_url"`
+}
+
+type UserBlockData struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	AvatarURL *string   `json:"avatar_url"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetUserBlockListResponse struct {
+	Data []UserBlockData `json:"blocks"`
+	Meta PaginationMeta  `json:"meta"`
+}
+
+type UserFollowData struct {
+	UserID     string    `json:"user_id"`
+	Username   string    `json:"username"`
+	AvatarURL  *string   `json:"avatar_url"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type GetUserFollowListResponse struct {
+	Data []UserFollowData `json:"users"`
+	Meta PaginationMeta   `json:"meta"`
+}
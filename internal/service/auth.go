@@ -18,26 +18,29 @@ import (
 	"github.com/samber/oops"
 	"github.com/uptrace/bun"
 	"go.uber.org/zap"
+	"math"
 	"net/http"
 	"time"
 )
 
 type authService struct {
-	cfg       config.Config
-	userRepo  repository.UserRepository
-	uniRepo   repository.UniversityRepository
-	db        *bun.DB
-	mailerSvc mailer.MailService
+	cfg            config.Config
+	userRepo       repository.UserRepository
+	uniRepo        repository.UniversityRepository
+	outboxRepo     repository.OutboxRepository
+	reputationRepo repository.ReputationRepository
+	db             *bun.DB
 }
 
-func NewAuthService(cfg config.Config, userRepo repository.UserRepository, uniRepo repository.UniversityRepository, db *bun.DB, mailerSvc mailer.MailService) AuthService {
+func NewAuthService(cfg config.Config, userRepo repository.UserRepository, uniRepo repository.UniversityRepository, outboxRepo repository.OutboxRepository, reputationRepo repository.ReputationRepository, db *bun.DB) AuthService {
 
 	return &authService{
-		cfg:       cfg,
-		userRepo:  userRepo,
-		uniRepo:   uniRepo,
-		db:        db,
-		mailerSvc: mailerSvc,
+		cfg:            cfg,
+		userRepo:       userRepo,
+		uniRepo:        uniRepo,
+		outboxRepo:     outboxRepo,
+		reputationRepo: reputationRepo,
+		db:             db,
 	}
 }
 
@@ -96,6 +99,21 @@ func (s *authService) Register(ctx context.Context, req request.RegisterUserReq)
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
+	err = s.reputationRepo.SaveTx(&model.ReputationEvent{
+		ID:         uuid.NewString(),
+		UserID:     user.ID,
+		SourceType: constants.REPUTATION_SOURCE_SIGNUP,
+		Delta:      user.ReputationPoints,
+		Reason:     constants.REPUTATION_REASON_SIGNUP_BONUS,
+		CreatedBy:  user.Email,
+	}, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Register] Failed to save signup reputation event", zap.Error(err))
+		tx.Rollback()
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
 	userVerifyCode := &model.UserVerifyCode{
 		ID:        uuid.NewString(),
 		UserID:    user.ID,
@@ -115,14 +133,8 @@ func (s *authService) Register(ctx context.Context, req request.RegisterUserReq)
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[Register] Failed to commit transaction", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-	}
-
 	if s.cfg.GetFlags().EnableSendEmail {
-		err = s.mailerSvc.SendMail(ctx, mailer.Mail{
+		outboxMessage, err := newOutboxMessage(constants.OUTBOX_TYPE_EMAIL, mailer.Mail{
 			To: []string{
 				req.Email,
 			},
@@ -132,13 +144,29 @@ func (s *authService) Register(ctx context.Context, req request.RegisterUserReq)
 			Data: map[string]interface{}{
 				"code": userVerifyCode.Code,
 			},
-		})
+		}, s.cfg.AppName())
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[Register] Failed to build verification email outbox message", zap.Error(err))
+			tx.Rollback()
 
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		err = s.outboxRepo.SaveTx(outboxMessage, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[Register] Failed to send mail", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[Register] Failed to save verification email outbox message", zap.Error(err))
+			tx.Rollback()
+
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Register] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
 	return nil
 }
 
@@ -159,44 +187,274 @@ func (s *authService) mapCreateUserReqToUserModel(ctx context.Context, data requ
 		Password:         hasedPassword,
 		IsBanned:         false,
 		IsEmailVerified:  false,
-		ReputationPoints: 50,
+		ReputationPoints: constants.DEFAULT_SIGNUP_REPUTATION,
 		CreatedBy:        data.Email,
 	}, nil
 }
 
-func (s *authService) Login(ctx context.Context, req request.LoginUserReq) (token string, err error) {
+func (s *authService) Login(ctx context.Context, req request.LoginUserReq) (response.AuthTokenResponse, error) {
 	//ctx, endFunc := trace.Start(ctx, "AuthService.Login", "service")
 	//defer endFunc()
 
+	var resp response.AuthTokenResponse
+
 	existingUser, err := s.userRepo.GetByEmail(req.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			s.cfg.Logger().ErrorWithContext(ctx, "[Login] Invalid email/password", zap.Error(err))
-			return "", oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid Email/Password")
+			return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid Email/Password")
 		}
 
 		s.cfg.Logger().ErrorWithContext(ctx, "[Login] Failed to get user by email", zap.Error(err))
-		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingUser.LockedUntil.After(time.Now()) {
+		retryAfterSecs := int(math.Ceil(time.Until(existingUser.LockedUntil.Time).Seconds()))
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[Login] User is locked out", zap.String("email", req.Email))
+		return resp, oops.Code(response.TooManyRequests.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusTooManyRequests).With(httpresp.RetryAfterCtxKey, retryAfterSecs).Errorf("Too many failed login attempts, please try again later")
 	}
 
 	if !existingUser.IsEmailVerified {
 		s.cfg.Logger().ErrorWithContext(ctx, "[Login] User email is not yet verified", zap.String("email", req.Email))
-		return "", oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("User email is not yet verified")
+		return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("User email is not yet verified")
 	}
 
 	isMatch := pkg.CheckPasswordHash(req.Password, existingUser.Password)
 	if !isMatch {
 		s.cfg.Logger().ErrorWithContext(ctx, "[Login] Invalid password for user", zap.String("email", req.Email))
-		return "", oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid Email/Password")
+		s.recordFailedLogin(ctx, existingUser)
+		return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid Email/Password")
+	}
+
+	if existingUser.IsBanned {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Login] User is banned", zap.String("email", req.Email))
+		return resp, oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("User is banned")
 	}
 
-	token, err = pkg.GenerateToken(existingUser)
+	if existingUser.FailedLoginAttempts > 0 || !existingUser.LockedUntil.IsZero() {
+		err = s.userRepo.UpdateUser(existingUser.ID, map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		})
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[Login] Failed to reset user failed login attempts", zap.String("email", req.Email), zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+	}
+
+	refreshToken, err := pkg.GenerateRefreshToken()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Login] Failed to generate refresh token for user", zap.String("email", req.Email), zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	userSession := &model.UserSession{
+		ID:           uuid.NewString(),
+		UserID:       existingUser.ID,
+		RefreshToken: pkg.HashToken(refreshToken),
+		ExpiredAt:    time.Now().Add(time.Hour * 24 * time.Duration(s.cfg.GetAuthCfg().RefreshTokenExpiryDays)),
+		CreatedBy:    existingUser.Email,
+	}
+
+	if req.UserAgent != "" {
+		userSession.UserAgent = pkg.ToPointer(pkg.TruncateWithEllipsis(req.UserAgent, 250))
+	}
+
+	if req.IPAddress != "" {
+		userSession.IPAddress = pkg.ToPointer(req.IPAddress)
+	}
+
+	err = s.userRepo.SaveUserSession(userSession)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Login] Failed to insert user session to database", zap.String("email", req.Email), zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	resp, err = s.generateAuthToken(existingUser, userSession, refreshToken)
 	if err != nil {
 		s.cfg.Logger().ErrorWithContext(ctx, "[Login] Failed to generate JWT Token for user", zap.String("email", req.Email))
-		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return resp, nil
+}
+
+// recordFailedLogin locks the user out once the failed attempts reach the configured maximum.
+// Failures are only logged so the caller still answers with the invalid credentials error
+func (s *authService) recordFailedLogin(ctx context.Context, user *model.User) {
+	failedLoginAttempts, err := s.userRepo.IncrementFailedLoginAttempts(user.ID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[recordFailedLogin] Failed to increment user failed login attempts", zap.String("email", user.Email), zap.Error(err))
+		return
+	}
+
+	if failedLoginAttempts < s.cfg.GetAuthCfg().LoginMaxFailedAttempts {
+		return
+	}
+
+	updateValues := map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          time.Now().Add(time.Minute * time.Duration(s.cfg.GetAuthCfg().LoginLockoutMins)),
+	}
+
+	err = s.userRepo.UpdateUser(user.ID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[recordFailedLogin] Failed to lock user out", zap.String("email", user.Email), zap.Error(err))
+		return
+	}
+
+	s.cfg.Logger().WarnWithContext(ctx, "[recordFailedLogin] User locked out after too many failed login attempts", zap.String("email", user.Email))
+}
+
+func (s *authService) RefreshToken(ctx context.Context, req request.RefreshTokenReq) (response.AuthTokenResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "AuthService.RefreshToken", "service")
+	//defer endFunc()
+
+	var resp response.AuthTokenResponse
+
+	userSession, err := s.userRepo.GetUserSessionByRefreshToken(pkg.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] User session not found", zap.Error(err))
+			return resp, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("Invalid refresh token")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] Failed to get user session by refresh token", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if !userSession.IsActive() {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] User session is revoked or expired", zap.String("session_id", userSession.ID))
+		return resp, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("Session is revoked or expired")
+	}
+
+	existingUser, err := s.userRepo.GetByID(userSession.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] User not found", zap.Error(err))
+			return resp, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] Failed to get user by id", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingUser.IsBanned {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] User is banned", zap.String("email", existingUser.Email))
+		return resp, oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("User is banned")
+	}
+
+	// Rotate the refresh token so a leaked token can only be used once
+	refreshToken, err := pkg.GenerateRefreshToken()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] Failed to generate refresh token for user", zap.String("email", existingUser.Email), zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	oldRefreshToken := userSession.RefreshToken
+	userSession.RefreshToken = pkg.HashToken(refreshToken)
+	userSession.ExpiredAt = time.Now().Add(time.Hour * 24 * time.Duration(s.cfg.GetAuthCfg().RefreshTokenExpiryDays))
+
+	updateValues := map[string]interface{}{
+		"refresh_token": userSession.RefreshToken,
+		"expired_at":    userSession.ExpiredAt,
+		"last_used_at":  time.Now(),
+		"updated_by":    existingUser.Email,
+		"updated_at":    time.Now(),
+	}
+
+	if req.UserAgent != "" {
+		updateValues["user_agent"] = pkg.TruncateWithEllipsis(req.UserAgent, 250)
+	}
+
+	if req.IPAddress != "" {
+		updateValues["ip_address"] = req.IPAddress
+	}
+
+	// Only rotate when the stored token is still the one presented, so two concurrent
+	// refreshes with the same token cannot both succeed
+	isRotated, err := s.userRepo.RotateUserSessionRefreshToken(userSession.ID, oldRefreshToken, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] Failed to update user session", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update user session")
 	}
 
-	return token, nil
+	if !isRotated {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] Refresh token was already used", zap.String("session_id", userSession.ID))
+		return resp, oops.Code(response.Unauthorized.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusUnauthorized).Errorf("Invalid refresh token")
+	}
+
+	resp, err = s.generateAuthToken(existingUser, userSession, refreshToken)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RefreshToken] Failed to generate JWT Token for user", zap.String("email", existingUser.Email))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return resp, nil
+}
+
+func (s *authService) generateAuthToken(user *model.User, userSession *model.UserSession, refreshToken string) (response.AuthTokenResponse, error) {
+
+	accessTokenExpiredAt := time.Now().Add(time.Minute * time.Duration(s.cfg.GetAuthCfg().AccessTokenExpiryMins))
+
+	token, err := pkg.GenerateToken(user, userSession.ID, accessTokenExpiredAt)
+	if err != nil {
+		return response.AuthTokenResponse{}, err
+	}
+
+	return response.AuthTokenResponse{
+		AccessToken:           token,
+		AccessTokenExpiredAt:  accessTokenExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiredAt: userSession.ExpiredAt,
+	}, nil
+}
+
+func (s *authService) Logout(ctx context.Context, req request.LogoutReq) error {
+	//ctx, endFunc := trace.Start(ctx, "AuthService.Logout", "service")
+	//defer endFunc()
+
+	if req.SessionID == "" {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Logout] Token is not bound to any session")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Token is not bound to any session")
+	}
+
+	updateValues := map[string]interface{}{
+		"revoked_at": time.Now(),
+		"revoked_by": req.UserEmail,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	err := s.userRepo.UpdateUserSessionByID(req.SessionID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Logout] Failed to revoke user session", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to revoke user session")
+	}
+
+	return nil
+}
+
+func (s *authService) LogoutAllDevices(ctx context.Context, req request.LogoutAllDevicesReq) error {
+	//ctx, endFunc := trace.Start(ctx, "AuthService.LogoutAllDevices", "service")
+	//defer endFunc()
+
+	updateValues := map[string]interface{}{
+		"revoked_at": time.Now(),
+		"revoked_by": req.UserEmail,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	err := s.userRepo.RevokeUserSessionsByUserID(req.UserID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LogoutAllDevices] Failed to revoke user sessions", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to revoke user sessions")
+	}
+
+	return nil
 }
 
 func (s *authService) VerifyEmail(ctx context.Context, req request.VerifyEmailReq) (err error) {
@@ -393,15 +651,22 @@ func (s *authService) SendResetPasswordLink(ctx context.Context, req request.Sen
 		CreatedBy: s.cfg.AppName(),
 	}
 
-	err = s.userRepo.SaveUserVerifyCode(userVerifyCode)
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[SendResetPasswordLink] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.userRepo.SaveUserVerifyCodeTx(userVerifyCode, tx)
 	if err != nil {
 		s.cfg.Logger().ErrorWithContext(ctx, "[SendResetPasswordLink] Failed to insert user verify code to database", zap.Error(err))
+		tx.Rollback()
 
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
 	if s.cfg.GetFlags().EnableSendEmail {
-		err = s.mailerSvc.SendMail(ctx, mailer.Mail{
+		outboxMessage, err := newOutboxMessage(constants.OUTBOX_TYPE_EMAIL, mailer.Mail{
 			To: []string{
 				req.Email,
 			},
@@ -411,12 +676,28 @@ func (s *authService) SendResetPasswordLink(ctx context.Context, req request.Sen
 			Data: map[string]interface{}{
 				"code": userVerifyCode.Code,
 			},
-		})
+		}, s.cfg.AppName())
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[SendResetPasswordLink] Failed to build reset password email outbox message", zap.Error(err))
+			tx.Rollback()
+
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
 
+		err = s.outboxRepo.SaveTx(outboxMessage, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[SendResetPasswordLink] Failed to send reset password link email", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[SendResetPasswordLink] Failed to save reset password email outbox message", zap.Error(err))
+			tx.Rollback()
+
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[SendResetPasswordLink] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
 	return nil
 }
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// awardBadgeTx gives the badge to the user and notifies them, nothing happens when the user already has it
+func awardBadgeTx(badgeRepo repository.BadgeRepository, notificationRepo repository.NotificationRepository, userID string, badgeCode string, scopeType *string, scopeID *string, createdBy string, tx bun.Tx) error {
+
+	awarded, err := badgeRepo.AwardTx(&model.UserBadge{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		ScopeType: scopeType,
+		ScopeID:   scopeID,
+		CreatedBy: createdBy,
+	}, badgeCode, tx)
+	if err != nil || !awarded {
+		return err
+	}
+
+	badge, err := badgeRepo.GetByCode(badgeCode)
+	if err != nil {
+		return err
+	}
+
+	dedupeKey := fmt.Sprintf("%s_%s_%s", constants.BADGE_EARNED_EVENT, badgeCode, userID)
+	if scopeID != nil {
+		dedupeKey += "_" + *scopeID
+	}
+
+	notification := newNotification(
+		userID,
+		"",
+		constants.BADGE_EARNED_EVENT,
+		fmt.Sprintf("You earned the %s badge!", badge.Name),
+		badge.Description,
+		"/profile",
+		dedupeKey,
+		createdBy,
+	)
+
+	return notificationRepo.BulkSaveTx([]model.Notification{notification}, tx)
+}
+
+// evaluateThreadBadgesTx runs the rules of the badges earned by posting a thread, it must be called after the thread is saved
+func evaluateThreadBadgesTx(badgeRepo repository.BadgeRepository, notificationRepo repository.NotificationRepository, thread *model.Thread, createdBy string, tx bun.Tx) error {
+
+	err := awardBadgeTx(badgeRepo, notificationRepo, thread.UserID, constants.BADGE_FIRST_THREAD, nil, nil, createdBy, tx)
+	if err != nil {
+		return err
+	}
+
+	userCount, topCount, err := badgeRepo.GetSubThreadThreadCountsTx(thread.SubThreadID, thread.UserID, tx)
+	if err != nil {
+		return err
+	}
+
+	// Ties share the badge, a contributor that is overtaken later keeps it
+	if userCount < constants.BADGE_TOP_CONTRIBUTOR_MIN_THREADS || userCount < topCount {
+		return nil
+	}
+
+	return awardBadgeTx(badgeRepo, notificationRepo, thread.UserID, constants.BADGE_TOP_CONTRIBUTOR, pkg.ToPointer(constants.SCOPE_TYPE_SUBTHREAD), pkg.ToPointer(thread.SubThreadID), createdBy, tx)
+}
+
+// evaluateLikesReceivedBadgeTx must be called after the like count of the content is incremented
+func evaluateLikesReceivedBadgeTx(badgeRepo repository.BadgeRepository, notificationRepo repository.NotificationRepository, authorID string, createdBy string, tx bun.Tx) error {
+
+	likesReceived, err := badgeRepo.CountLikesReceivedTx(authorID, tx)
+	if err != nil {
+		return err
+	}
+
+	if likesReceived < constants.BADGE_LIKES_RECEIVED_THRESHOLD {
+		return nil
+	}
+
+	return awardBadgeTx(badgeRepo, notificationRepo, authorID, constants.BADGE_LIKES_RECEIVED_100, nil, nil, createdBy, tx)
+}
+
+func evaluateVerifiedStudentBadgeTx(badgeRepo repository.BadgeRepository, notificationRepo repository.NotificationRepository, user *model.User, createdBy string, tx bun.Tx) error {
+
+	if user.UniversityID == nil || !user.IsEmailVerified {
+		return nil
+	}
+
+	return awardBadgeTx(badgeRepo, notificationRepo, user.ID, constants.BADGE_VERIFIED_STUDENT, nil, nil, createdBy, tx)
+}
+
+// evaluateUniversityReviewBadgeTx rewards the reviews that give both sides of the university
+func evaluateUniversityReviewBadgeTx(badgeRepo repository.BadgeRepository, notificationRepo repository.NotificationRepository, userID string, pros []string, cons []string, createdBy string, tx bun.Tx) error {
+
+	if len(pros) < constants.BADGE_HELPFUL_REVIEWER_MIN_POINTS || len(cons) < constants.BADGE_HELPFUL_REVIEWER_MIN_POINTS {
+		return nil
+	}
+
+	return awardBadgeTx(badgeRepo, notificationRepo, userID, constants.BADGE_HELPFUL_UNIVERSITY_REVIEWER, nil, nil, createdBy, tx)
+}
+
+func mapUserBadges(userBadges []*model.UserBadge) []response.UserBadge {
+
+	badges := []response.UserBadge{}
+
+	for _, ub := range userBadges {
+		if ub.Badge == nil {
+			continue
+		}
+
+		badges = append(badges, response.UserBadge{
+			Code:        ub.Badge.Code,
+			Name:        ub.Badge.Name,
+			Description: ub.Badge.Description,
+			IconURL:     ub.Badge.IconURL,
+			ScopeType:   ub.ScopeType,
+			ScopeID:     ub.ScopeID,
+			EarnedAt:    ub.CreatedAt,
+		})
+	}
+
+	return badges
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/google/uuid"
+	"github.com/samber/oops"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type bookmarkService struct {
+	cfg           config.Config
+	bookmarkRepo  repository.BookmarkRepository
+	threadRepo    repository.ThreadRepository
+	subThreadRepo repository.SubThreadRepository
+	userRepo      repository.UserRepository
+	roleRepo      repository.RoleRepository
+	db            *bun.DB
+}
+
+func NewBookmarkService(cfg config.Config, bookmarkRepo repository.BookmarkRepository, threadRepo repository.ThreadRepository, subThreadRepo repository.SubThreadRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, db *bun.DB) BookmarkService {
+
+	return &bookmarkService{
+		cfg:           cfg,
+		bookmarkRepo:  bookmarkRepo,
+		threadRepo:    threadRepo,
+		subThreadRepo: subThreadRepo,
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		db:            db,
+	}
+}
+
+// SaveBookmark bookmarks a thread, comment or reply. Saving an existing bookmark moves it to the requested collection
+func (s *bookmarkService) SaveBookmark(ctx context.Context, req request.SaveBookmarkReq) error {
+	//ctx, endFunc := trace.Start(ctx, "BookmarkService.SaveBookmark", "service")
+	//defer endFunc()
+
+	threadID, err := s.getTargetThreadID(ctx, req.TargetType, req.TargetID)
+	if err != nil {
+		return err
+	}
+
+	thread, err := s.threadRepo.GetByIDSimple(threadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[SaveBookmark] Thread not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[SaveBookmark] Failed to get thread by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	subThread, err := s.subThreadRepo.GetByID(thread.SubThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[SaveBookmark] Failed to get subthread by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = checkUniversitySubThreadAccess(ctx, s.cfg, s.userRepo, s.roleRepo, "SaveBookmark", subThread, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if req.CollectionID != nil {
+		_, err = s.getCollection(ctx, "SaveBookmark", req.UserID, *req.CollectionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	existingBookmark, err := s.bookmarkRepo.GetByTarget(req.UserID, req.TargetType, req.TargetID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[SaveBookmark] Failed to get bookmark by target", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingBookmark.ID != "" {
+		updateValues := map[string]interface{}{
+			"collection_id": req.CollectionID,
+			"updated_by":    req.UserEmail,
+			"updated_at":    time.Now(),
+		}
+
+		err = s.bookmarkRepo.UpdateByID(existingBookmark.ID, updateValues)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[SaveBookmark] Failed to update bookmark", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update bookmark")
+		}
+
+		return nil
+	}
+
+	bookmark := &model.Bookmark{
+		ID:           uuid.NewString(),
+		UserID:       req.UserID,
+		TargetType:   req.TargetType,
+		TargetID:     req.TargetID,
+		ThreadID:     threadID,
+		CollectionID: req.CollectionID,
+		CreatedBy:    req.UserEmail,
+	}
+
+	err = s.bookmarkRepo.Save(bookmark)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[SaveBookmark] Failed to save bookmark", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save bookmark")
+	}
+
+	return nil
+}
+
+// getTargetThreadID returns the thread of the bookmarked thread, comment or reply
+func (s *bookmarkService) getTargetThreadID(ctx context.Context, targetType string, targetID string) (string, error) {
+
+	switch targetType {
+	case constants.BOOKMARK_TARGET_TYPE_THREAD:
+		return targetID, nil
+	case constants.BOOKMARK_TARGET_TYPE_THREAD_COMMENT:
+		threadComment, err := s.threadRepo.GetThreadCommentByID(targetID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				s.cfg.Logger().ErrorWithContext(ctx, "[getTargetThreadID] Thread comment not found", zap.Error(err))
+				return "", oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment not found")
+			}
+
+			s.cfg.Logger().ErrorWithContext(ctx, "[getTargetThreadID] Failed to get thread comment by id", zap.Error(err))
+			return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		return threadComment.ThreadID, nil
+	case constants.BOOKMARK_TARGET_TYPE_THREAD_COMMENT_REPLY:
+		threadCommentReply, err := s.threadRepo.GetThreadCommentReplyByID(targetID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				s.cfg.Logger().ErrorWithContext(ctx, "[getTargetThreadID] Thread comment reply not found", zap.Error(err))
+				return "", oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply not found")
+			}
+
+			s.cfg.Logger().ErrorWithContext(ctx, "[getTargetThreadID] Failed to get thread comment reply by id", zap.Error(err))
+			return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		return threadCommentReply.ThreadID, nil
+	}
+
+	s.cfg.Logger().ErrorWithContext(ctx, "[getTargetThreadID] Invalid bookmark target type", zap.String("target_type", targetType))
+	return "", oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid bookmark target type")
+}
+
+func (s *bookmarkService) DeleteBookmark(ctx context.Context, req request.DeleteBookmarkReq) error {
+	//ctx, endFunc := trace.Start(ctx, "BookmarkService.DeleteBookmark", "service")
+	//defer endFunc()
+
+	isDeleted, err := s.bookmarkRepo.DeleteByTarget(req.UserID, req.TargetType, req.TargetID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteBookmark] Failed to delete bookmark", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete bookmark")
+	}
+
+	if !isDeleted {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteBookmark] Bookmark not found", zap.String("target_id", req.TargetID))
+		return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Bookmark not found")
+	}
+
+	return nil
+}
+
+func (s *bookmarkService) GetBookmarkList(ctx context.Context, req request.GetBookmarkListReq) (response.GetBookmarkListResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "BookmarkService.GetBookmarkList", "service")
+	//defer endFunc()
+
+	var resp response.GetBookmarkListResponse
+
+	bookmarks, pagination, err := s.bookmarkRepo.GetList(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetBookmarkList] Failed to get bookmark list", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get bookmark list")
+	}
+
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	commentIDs := []string{}
+	replyIDs := []string{}
+
+	for _, b := range bookmarks {
+		if b.TargetType == constants.BOOKMARK_TARGET_TYPE_THREAD_COMMENT {
+			commentIDs = append(commentIDs, b.TargetID)
+		}
+
+		if b.TargetType == constants.BOOKMARK_TARGET_TYPE_THREAD_COMMENT_REPLY {
+			replyIDs = append(replyIDs, b.TargetID)
+		}
+	}
+
+	commentsByID := map[string]*response.BookmarkComment{}
+
+	if len(commentIDs) > 0 {
+		threadComments, err := s.threadRepo.GetThreadCommentsByIDs(commentIDs)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetBookmarkList] Failed to get bookmarked thread comments", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get bookmark list")
+		}
+
+		for _, tc := range threadComments {
+			commentsByID[tc.ID] = &response.BookmarkComment{
+				ID:        tc.ID,
+				UserID:    tc.UserID,
+				UserName:  tc.User.Username,
+				Content:   tc.Content,
+				CreatedAt: tc.CreatedAt,
+				EditedAt:  tc.EditedAt,
+			}
+		}
+	}
+
+	if len(replyIDs) > 0 {
+		threadCommentReplies, err := s.threadRepo.GetThreadCommentRepliesByIDs(replyIDs)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetBookmarkList] Failed to get bookmarked thread comment replies", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get bookmark list")
+		}
+
+		for _, tcr := range threadCommentReplies {
+			commentsByID[tcr.ID] = &response.BookmarkComment{
+				ID:        tcr.ID,
+				UserID:    tcr.UserID,
+				UserName:  tcr.User.Username,
+				Content:   tcr.Content,
+				CreatedAt: tcr.CreatedAt,
+				EditedAt:  tcr.EditedAt,
+			}
+		}
+	}
+
+	resp.Data = []response.BookmarkData{}
+
+	for _, b := range bookmarks {
+		if b.Thread == nil {
+			continue
+		}
+
+		bd := response.BookmarkData{
+			ID:           b.ID,
+			TargetType:   b.TargetType,
+			TargetID:     b.TargetID,
+			ThreadID:     b.ThreadID,
+			ThreadTitle:  b.Thread.Title,
+			CollectionID: b.CollectionID,
+			CreatedAt:    b.CreatedAt,
+		}
+
+		if b.TargetType == constants.BOOKMARK_TARGET_TYPE_THREAD {
+			b.Thread.IsBookmarked = true
+			bd.Thread = &mapThreadListData([]model.Thread{*b.Thread})[0]
+		} else {
+			// The bookmarked comment or reply was deleted
+			if commentsByID[b.TargetID] == nil {
+				continue
+			}
+
+			bd.Comment = commentsByID[b.TargetID]
+		}
+
+		resp.Data = append(resp.Data, bd)
+	}
+
+	return resp, nil
+}
+
+func (s *bookmarkService) GetBookmarkCollections(ctx context.Context, req request.GetBookmarkCollectionsReq) (response.GetBookmarkCollectionsResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "BookmarkService.GetBookmarkCollections", "service")
+	//defer endFunc()
+
+	var resp response.GetBookmarkCollectionsResponse
+
+	collections, err := s.bookmarkRepo.GetCollections(req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetBookmarkCollections] Failed to get bookmark collections", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get bookmark collections")
+	}
+
+	resp.Data = collections
+
+	return resp, nil
+}
+
+func (s *bookmarkService) CreateBookmarkCollection(ctx context.Context, req request.CreateBookmarkCollectionReq) error {
+	//ctx, endFunc := trace.Start(ctx, "BookmarkService.CreateBookmarkCollection", "service")
+	//defer endFunc()
+
+	name := strings.TrimSpace(req.Name)
+
+	collectionCount, err := s.bookmarkRepo.CountCollections(req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateBookmarkCollection] Failed to count bookmark collections", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if collectionCount >= constants.BOOKMARK_MAX_COLLECTIONS {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateBookmarkCollection] Bookmark collection limit reached", zap.String("user_id", req.UserID))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("A user can have at most %d bookmark collections", constants.BOOKMARK_MAX_COLLECTIONS)
+	}
+
+	err = s.checkCollectionName(ctx, "CreateBookmarkCollection", req.UserID, "", name)
+	if err != nil {
+		return err
+	}
+
+	collection := &model.BookmarkCollection{
+		ID:        uuid.NewString(),
+		UserID:    req.UserID,
+		Name:      name,
+		CreatedBy: req.UserEmail,
+	}
+
+	err = s.bookmarkRepo.SaveCollection(collection)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateBookmarkCollection] Failed to save bookmark collection", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save bookmark collection")
+	}
+
+	return nil
+}
+
+func (s *bookmarkService) UpdateBookmarkCollection(ctx context.Context, req request.UpdateBookmarkCollectionReq) error {
+	//ctx, endFunc := trace.Start(ctx, "BookmarkService.UpdateBookmarkCollection", "service")
+	//defer endFunc()
+
+	name := strings.TrimSpace(req.Name)
+
+	_, err := s.getCollection(ctx, "UpdateBookmarkCollection", req.UserID, req.CollectionID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkCollectionName(ctx, "UpdateBookmarkCollection", req.UserID, req.CollectionID, name)
+	if err != nil {
+		return err
+	}
+
+	updateValues := map[string]interface{}{
+		"name":       name,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	err = s.bookmarkRepo.UpdateCollectionByID(req.CollectionID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateBookmarkCollection] Failed to update bookmark collection", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update bookmark collection")
+	}
+
+	return nil
+}
+
+func (s *bookmarkService) DeleteBookmarkCollection(ctx context.Context, req request.DeleteBookmarkCollectionReq) error {
+	//ctx, endFunc := trace.Start(ctx, "BookmarkService.DeleteBookmarkCollection", "service")
+	//defer endFunc()
+
+	_, err := s.getCollection(ctx, "DeleteBookmarkCollection", req.UserID, req.CollectionID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteBookmarkCollection] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.bookmarkRepo.DeleteCollectionByIDTx(req.CollectionID, req.UserEmail, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteBookmarkCollection] Failed to delete bookmark collection", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete bookmark collection")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteBookmarkCollection] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// getCollection gets a collection of the user, the collections of other users are reported as not found
+func (s *bookmarkService) getCollection(ctx context.Context, funcName string, userID string, collectionID string) (model.BookmarkCollection, error) {
+
+	collection, err := s.bookmarkRepo.GetCollectionByID(userID, collectionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Bookmark collection not found", zap.Error(err))
+			return collection, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Bookmark collection not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get bookmark collection by id", zap.Error(err))
+		return collection, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return collection, nil
+}
+
+// checkCollectionName rejects an empty name and a name already used by another collection of the user
+func (s *bookmarkService) checkCollectionName(ctx context.Context, funcName string, userID string, collectionID string, name string) error {
+
+	if name == "" {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Bookmark collection name is empty")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Bookmark collection name is required")
+	}
+
+	existingCollection, err := s.bookmarkRepo.GetCollectionByName(userID, name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get bookmark collection by name", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingCollection.ID != "" && existingCollection.ID != collectionID {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Bookmark collection name already used", zap.String("name", name))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("A bookmark collection with this name already exists")
+	}
+
+	return nil
+}
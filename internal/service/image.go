@@ -1,62 +1,501 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
 	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/andibalo/meowhasiswa-be/pkg/imageproc"
+	"github.com/google/uuid"
 	"github.com/samber/oops"
+	"github.com/uptrace/bun"
 	"go.uber.org/zap"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 type imageService struct {
-	cfg      config.Config
-	fileRepo repository.FileRepository
+	cfg             config.Config
+	fileRepo        repository.FileRepository
+	attachmentRepo  repository.AttachmentRepository
+	imageUploadRepo repository.ImageUploadRepository
+	db              *bun.DB
 }
 
-func NewImageService(cfg config.Config, fileRepo repository.FileRepository) ImageService {
+func NewImageService(cfg config.Config, fileRepo repository.FileRepository, attachmentRepo repository.AttachmentRepository, imageUploadRepo repository.ImageUploadRepository, db *bun.DB) ImageService {
 
 	return &imageService{
-		cfg:      cfg,
-		fileRepo: fileRepo,
+		cfg:             cfg,
+		fileRepo:        fileRepo,
+		attachmentRepo:  attachmentRepo,
+		imageUploadRepo: imageUploadRepo,
+		db:              db,
 	}
 }
 
-func (s *imageService) UploadImage(ctx context.Context, fileData model.File) (response.UploadImageResp, error) {
+func (s *imageService) UploadImage(ctx context.Context, req request.UploadImageReq) (response.UploadImageResp, error) {
 	//ctx, endFunc := trace.Start(ctx, "ImageService.UploadImage", "service")
 	//defer endFunc()
 
 	var resp response.UploadImageResp
 
-	uploadData := model.UploadFileDTO{
-		File:        fileData.Reader,
-		Name:        fileData.FileName,
-		Bucket:      s.cfg.GetAWSCfg().DefaultBucket,
-		IsPrivate:   false,
-		MegaBytes:   float64(fileData.Size),
-		ContentType: fileData.ContentType,
+	data, err := io.ReadAll(req.File.Reader)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UploadImage] Failed to read image", zap.Error(err))
+
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to read image")
+	}
+
+	processed, err := s.processImage(ctx, data)
+	if err != nil {
+		return resp, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UploadImage] Failed to begin transaction", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	attachment, originalUploadResp, err := s.storeImageTx(ctx, processed, req.AltText, req.UserID, req.UserEmail, tx)
+	if err != nil {
+		tx.Rollback()
+		return resp, err
 	}
 
-	uploadResp, err := s.fileRepo.Upload(ctx, uploadData)
+	// The upload stays unattached until a thread or comment references it, unattached uploads are garbage collected
+	err = s.attachmentRepo.SaveTx(attachment, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[UploadImage] Failed to upload image to remote file storage", zap.Error(err))
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UploadImage] Failed to insert attachment to database", zap.Error(err))
+
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save attachment")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UploadImage] Failed to commit transaction", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return mapUploadImageResp(attachment, originalUploadResp), nil
+}
+
+func (s *imageService) PresignImageUpload(ctx context.Context, req request.PresignImageUploadReq) (response.PresignImageUploadResp, error) {
+	//ctx, endFunc := trace.Start(ctx, "ImageService.PresignImageUpload", "service")
+	//defer endFunc()
+
+	var resp response.PresignImageUploadResp
 
-		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to upload image to remote file storage")
+	storageCfg := s.cfg.GetStorageCfg()
+	contentType := strings.ToLower(strings.TrimSpace(req.ContentType))
 
+	if !imageproc.IsAllowedContentType(contentType) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[PresignImageUpload] Unsupported image type", zap.String("content_type", contentType))
+		return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Unsupported image type, only jpeg, png and gif are allowed")
 	}
 
-	resp = response.UploadImageResp{
-		URL:         uploadResp.URL,
-		Name:        uploadResp.Name,
-		ETag:        uploadResp.ETag,
-		VersionID:   uploadResp.VersionID,
-		MegaBytes:   uploadResp.MegaBytes,
-		ContentType: uploadResp.ContentType,
-		Bucket:      uploadResp.Bucket,
+	if req.SizeBytes > int64(storageCfg.DirectUploadMaxSizeMB*1024*1024) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[PresignImageUpload] Image size exceeds limit", zap.Int64("size_bytes", req.SizeBytes))
+		return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Image size exceeds %d mb", storageCfg.DirectUploadMaxSizeMB)
+	}
+
+	imageUpload := &model.ImageUpload{
+		ID:             uuid.NewString(),
+		UserID:         req.UserID,
+		Bucket:         storageCfg.DefaultBucket,
+		ContentType:    contentType,
+		SizeBytes:      req.SizeBytes,
+		ChecksumSHA256: strings.ToLower(req.ChecksumSHA256),
+		AltText:        req.AltText,
+		Status:         constants.IMAGE_UPLOAD_STATUS_PENDING,
+		CreatedBy:      req.UserEmail,
+	}
+
+	// The raw upload lives under its own prefix, it is replaced by the processed variants once confirmed
+	imageUpload.FileKey = fmt.Sprintf("uploads/%s/%s", req.UserID, imageUpload.ID)
+
+	presigned, err := s.fileRepo.PresignUpload(ctx, imageUpload.Bucket, imageUpload.FileKey, contentType, imageUpload.SizeBytes, imageUpload.ChecksumSHA256, time.Duration(storageCfg.PresignExpiryMins)*time.Minute)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[PresignImageUpload] Failed to presign upload url", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to presign upload url")
+	}
+
+	imageUpload.ExpiresAt = presigned.ExpiresAt
+
+	err = s.imageUploadRepo.Save(imageUpload)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[PresignImageUpload] Failed to insert image upload to database", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save image upload")
+	}
+
+	resp = response.PresignImageUploadResp{
+		UploadID:  imageUpload.ID,
+		URL:       presigned.URL,
+		Method:    presigned.Method,
+		Headers:   presigned.Headers,
+		ExpiresAt: presigned.ExpiresAt,
 	}
 
 	return resp, nil
 }
+
+// ConfirmImageUpload verifies the object the client uploaded against what it declared when presigning, then processes
+// it like a multipart upload. Confirming an upload again returns the attachment created the first time
+func (s *imageService) ConfirmImageUpload(ctx context.Context, req request.ConfirmImageUploadReq) (response.UploadImageResp, error) {
+	//ctx, endFunc := trace.Start(ctx, "ImageService.ConfirmImageUpload", "service")
+	//defer endFunc()
+
+	var resp response.UploadImageResp
+
+	imageUpload, err := s.imageUploadRepo.GetByID(req.UploadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Image upload not found", zap.Error(err))
+			return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Image upload not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Failed to get image upload", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get image upload")
+	}
+
+	if imageUpload.UserID != req.UserID {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Image upload belongs to another user")
+		return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Image upload not found")
+	}
+
+	if imageUpload.Status == constants.IMAGE_UPLOAD_STATUS_CONFIRMED {
+		return s.getConfirmedImageUpload(ctx, imageUpload)
+	}
+
+	data, err := s.readImageUpload(ctx, imageUpload)
+	if err != nil {
+		return resp, err
+	}
+
+	processed, err := s.processImage(ctx, data)
+	if err != nil {
+		return resp, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Failed to begin transaction", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	attachment, originalUploadResp, err := s.storeImageTx(ctx, processed, imageUpload.AltText, req.UserID, req.UserEmail, tx)
+	if err != nil {
+		tx.Rollback()
+		return resp, err
+	}
+
+	err = s.attachmentRepo.SaveTx(attachment, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Failed to insert attachment to database", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save attachment")
+	}
+
+	isConfirmed, err := s.imageUploadRepo.ConfirmTx(imageUpload.ID, attachment.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Failed to confirm image upload", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to confirm image upload")
+	}
+
+	// A concurrent confirm won, its attachment is returned instead
+	if !isConfirmed {
+		tx.Rollback()
+
+		imageUpload, err = s.imageUploadRepo.GetByID(req.UploadID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Image upload was collected", zap.Error(err))
+				return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Image upload not found")
+			}
+
+			s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Failed to get image upload", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get image upload")
+		}
+
+		return s.getConfirmedImageUpload(ctx, imageUpload)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ConfirmImageUpload] Failed to commit transaction", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// The raw upload still carries its metadata, only the processed variants are kept
+	err = s.fileRepo.Delete(ctx, imageUpload.Bucket, imageUpload.FileKey)
+	if err != nil {
+		s.cfg.Logger().WarnWithContext(ctx, "[ConfirmImageUpload] Failed to delete raw upload from storage", zap.String("file_key", imageUpload.FileKey), zap.Error(err))
+	}
+
+	return mapUploadImageResp(attachment, originalUploadResp), nil
+}
+
+// readImageUpload downloads the raw upload and checks its size, checksum and sniffed type against the declared ones
+func (s *imageService) readImageUpload(ctx context.Context, imageUpload *model.ImageUpload) ([]byte, error) {
+
+	fileInfo, err := s.fileRepo.Stat(ctx, imageUpload.Bucket, imageUpload.FileKey)
+	if err != nil {
+		if errors.Is(err, model.ErrFileNotFound) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Uploaded file not found", zap.String("file_key", imageUpload.FileKey))
+			return nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Image has not been uploaded")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Failed to get uploaded file info", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get uploaded image")
+	}
+
+	if fileInfo.Size != imageUpload.SizeBytes {
+		s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Uploaded file size mismatch", zap.Int64("size_bytes", fileInfo.Size), zap.Int64("expected_size_bytes", imageUpload.SizeBytes))
+		return nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Uploaded image size does not match")
+	}
+
+	body, err := s.fileRepo.Download(ctx, imageUpload.Bucket, imageUpload.FileKey)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Failed to download uploaded file", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get uploaded image")
+	}
+
+	defer body.Close()
+
+	// One extra byte is read so an object replaced after the stat is still caught by the size check
+	data, err := io.ReadAll(io.LimitReader(body, imageUpload.SizeBytes+1))
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Failed to read uploaded file", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get uploaded image")
+	}
+
+	if int64(len(data)) != imageUpload.SizeBytes {
+		s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Uploaded file size mismatch", zap.Int("size_bytes", len(data)), zap.Int64("expected_size_bytes", imageUpload.SizeBytes))
+		return nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Uploaded image size does not match")
+	}
+
+	checksum := sha256.Sum256(data)
+	if hex.EncodeToString(checksum[:]) != imageUpload.ChecksumSHA256 {
+		s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Uploaded file checksum mismatch", zap.String("upload_id", imageUpload.ID))
+		return nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Uploaded image checksum does not match")
+	}
+
+	contentType := imageproc.DetectContentType(data)
+	if contentType != imageUpload.ContentType {
+		s.cfg.Logger().ErrorWithContext(ctx, "[readImageUpload] Uploaded file type mismatch", zap.String("content_type", contentType), zap.String("expected_content_type", imageUpload.ContentType))
+		return nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Uploaded image type does not match")
+	}
+
+	return data, nil
+}
+
+func (s *imageService) getConfirmedImageUpload(ctx context.Context, imageUpload *model.ImageUpload) (response.UploadImageResp, error) {
+
+	var resp response.UploadImageResp
+
+	if imageUpload.AttachmentID == nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getConfirmedImageUpload] Confirmed image upload has no attachment", zap.String("upload_id", imageUpload.ID))
+		return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Image upload not found")
+	}
+
+	attachment, err := s.attachmentRepo.GetByID(*imageUpload.AttachmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[getConfirmedImageUpload] Attachment not found", zap.Error(err))
+			return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Image upload not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[getConfirmedImageUpload] Failed to get attachment", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get attachment")
+	}
+
+	return mapUploadImageResp(attachment, model.UploadFileOutputDTO{
+		URL:         attachment.URL,
+		Name:        attachment.FileKey,
+		MegaBytes:   float64(attachment.SizeBytes) / (1024 * 1024),
+		ContentType: attachment.ContentType,
+		Bucket:      attachment.Bucket,
+	}), nil
+}
+
+// processImage re-encodes the image into its variants
+func (s *imageService) processImage(ctx context.Context, data []byte) (imageproc.Result, error) {
+
+	imageCfg := s.cfg.GetImageCfg()
+
+	processed, err := imageproc.Process(data, imageproc.Options{
+		MinWidth:       imageCfg.MinWidthPx,
+		MinHeight:      imageCfg.MinHeightPx,
+		MaxWidth:       imageCfg.MaxWidthPx,
+		MaxHeight:      imageCfg.MaxHeightPx,
+		MaxPixels:      imageCfg.MaxMegaPixels * 1000000,
+		JPEGQuality:    imageCfg.JPEGQuality,
+		ThumbnailSizes: imageCfg.ThumbnailSizesPx,
+	})
+	if err != nil {
+		s.cfg.Logger().WarnWithContext(ctx, "[processImage] Failed to process image", zap.Error(err))
+
+		return processed, s.mapProcessImageErr(err)
+	}
+
+	return processed, nil
+}
+
+// storeImageTx stores the variants of the processed image, the returned attachment is not saved yet. The content hash
+// stays locked until tx ends so the collector cannot delete the files before the attachment is saved
+func (s *imageService) storeImageTx(ctx context.Context, processed imageproc.Result, altText *string, userID string, userEmail string, tx bun.Tx) (*model.Attachment, model.UploadFileOutputDTO, error) {
+
+	var originalUploadResp model.UploadFileOutputDTO
+
+	err := s.attachmentRepo.LockContentHashTx(processed.Hash, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[storeImageTx] Failed to lock content hash", zap.Error(err))
+
+		return nil, originalUploadResp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	attachment := &model.Attachment{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		Bucket:      s.cfg.GetStorageCfg().DefaultBucket,
+		ContentType: processed.ContentType,
+		ContentHash: pkg.ToPointer(processed.Hash),
+		AltText:     altText,
+		CreatedBy:   userEmail,
+	}
+
+	// Identical images resolve to the same keys, uploading them again overwrites the objects with the same content
+	for _, v := range processed.Variants {
+		uploadResp, err := s.fileRepo.Upload(ctx, model.UploadFileDTO{
+			File:        bytes.NewReader(v.Data),
+			Name:        fmt.Sprintf("images/%s/%s%s", processed.Hash, v.Name, v.Extension),
+			Bucket:      attachment.Bucket,
+			IsPrivate:   false,
+			MegaBytes:   float64(len(v.Data)) / (1024 * 1024),
+			ContentType: v.ContentType,
+		})
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[storeImageTx] Failed to upload image to remote file storage", zap.String("variant", v.Name), zap.Error(err))
+
+			return nil, originalUploadResp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to upload image to remote file storage")
+		}
+
+		attachment.Variants = append(attachment.Variants, model.AttachmentVariant{
+			Name:      v.Name,
+			FileKey:   uploadResp.Name,
+			URL:       uploadResp.URL,
+			Width:     v.Width,
+			Height:    v.Height,
+			SizeBytes: int64(len(v.Data)),
+		})
+
+		if v.Name == imageproc.VariantOriginal {
+			originalUploadResp = uploadResp
+
+			attachment.FileKey = uploadResp.Name
+			attachment.URL = uploadResp.URL
+			attachment.SizeBytes = int64(len(v.Data))
+			attachment.Width = pkg.ToPointer(v.Width)
+			attachment.Height = pkg.ToPointer(v.Height)
+		}
+	}
+
+	return attachment, originalUploadResp, nil
+}
+
+func (s *imageService) mapProcessImageErr(err error) error {
+
+	imageCfg := s.cfg.GetImageCfg()
+
+	switch {
+	case errors.Is(err, imageproc.ErrUnsupportedType):
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Unsupported image type, only jpeg, png and gif are allowed")
+	case errors.Is(err, imageproc.ErrDimensionTooLow):
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Image must be at least %dx%d px", imageCfg.MinWidthPx, imageCfg.MinHeightPx)
+	case errors.Is(err, imageproc.ErrDimensionTooHigh):
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Image must be at most %dx%d px and %d megapixels", imageCfg.MaxWidthPx, imageCfg.MaxHeightPx, imageCfg.MaxMegaPixels)
+	case errors.Is(err, imageproc.ErrInvalidImage):
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid image")
+	default:
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to process image")
+	}
+}
+
+func mapImageVariants(variants []model.AttachmentVariant) []response.ImageVariant {
+
+	imageVariants := make([]response.ImageVariant, 0, len(variants))
+
+	for _, v := range variants {
+		imageVariants = append(imageVariants, response.ImageVariant{
+			Name:      v.Name,
+			URL:       v.URL,
+			Width:     v.Width,
+			Height:    v.Height,
+			SizeBytes: v.SizeBytes,
+		})
+	}
+
+	return imageVariants
+}
+
+func mapUploadImageResp(attachment *model.Attachment, originalUploadResp model.UploadFileOutputDTO) response.UploadImageResp {
+
+	return response.UploadImageResp{
+		ID:          attachment.ID,
+		Width:       attachment.Width,
+		Height:      attachment.Height,
+		Variants:    mapImageVariants(attachment.Variants),
+		URL:         originalUploadResp.URL,
+		Name:        originalUploadResp.Name,
+		ETag:        originalUploadResp.ETag,
+		VersionID:   originalUploadResp.VersionID,
+		MegaBytes:   originalUploadResp.MegaBytes,
+		ContentType: originalUploadResp.ContentType,
+		Bucket:      originalUploadResp.Bucket,
+	}
+}
+
+// attachImageTx makes the upload the single image of the target, the image it replaces is released to the garbage collector
+func attachImageTx(ctx context.Context, cfg config.Config, attachmentRepo repository.AttachmentRepository, attachmentID string, userID string, targetType string, targetID string, tx bun.Tx) (*model.Attachment, error) {
+
+	err := attachmentRepo.DetachByTargetTx(targetType, targetID, tx)
+	if err != nil {
+		cfg.Logger().ErrorWithContext(ctx, "[attachImageTx] Failed to detach previous image", zap.String("target_type", targetType), zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to attach image")
+	}
+
+	attachedIDs, err := attachmentRepo.AttachTx([]string{attachmentID}, userID, targetType, targetID, tx)
+	if err != nil {
+		cfg.Logger().ErrorWithContext(ctx, "[attachImageTx] Failed to attach image", zap.String("target_type", targetType), zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to attach image")
+	}
+
+	if len(attachedIDs) != 1 {
+		cfg.Logger().ErrorWithContext(ctx, "[attachImageTx] Image does not exist or is already attached", zap.String("attachment_id", attachmentID))
+		return nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Attachment does not exist or is already attached")
+	}
+
+	attachment, err := attachmentRepo.GetByID(attachmentID)
+	if err != nil {
+		cfg.Logger().ErrorWithContext(ctx, "[attachImageTx] Failed to get attachment", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to attach image")
+	}
+
+	return attachment, nil
+}
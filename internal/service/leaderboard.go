@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/google/uuid"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// leaderboardPeriodDays is the number of days counted by each leaderboard period, today included
+var leaderboardPeriodDays = map[string]int{
+	constants.LEADERBOARD_PERIOD_WEEK:     7,
+	constants.LEADERBOARD_PERIOD_MONTH:    30,
+	constants.LEADERBOARD_PERIOD_ALL_TIME: 0,
+}
+
+type leaderboardService struct {
+	cfg             config.Config
+	leaderboardRepo repository.LeaderboardRepository
+	subThreadRepo   repository.SubThreadRepository
+	userRepo        repository.UserRepository
+	roleRepo        repository.RoleRepository
+}
+
+func NewLeaderboardService(cfg config.Config, leaderboardRepo repository.LeaderboardRepository, subThreadRepo repository.SubThreadRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository) LeaderboardService {
+
+	return &leaderboardService{
+		cfg:             cfg,
+		leaderboardRepo: leaderboardRepo,
+		subThreadRepo:   subThreadRepo,
+		userRepo:        userRepo,
+		roleRepo:        roleRepo,
+	}
+}
+
+func (s *leaderboardService) GetLeaderboard(ctx context.Context, req request.GetLeaderboardReq) (response.GetLeaderboardResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "LeaderboardService.GetLeaderboard", "service")
+	//defer endFunc()
+
+	var resp response.GetLeaderboardResponse
+
+	if req.Period == "" {
+		req.Period = constants.LEADERBOARD_PERIOD_WEEK
+	}
+
+	days, ok := leaderboardPeriodDays[req.Period]
+	if !ok {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetLeaderboard] Invalid leaderboard period", zap.String("period", req.Period))
+		return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Period must be one of WEEK, MONTH or ALL_TIME")
+	}
+
+	if req.UniversityID != "" {
+		if _, err := uuid.Parse(req.UniversityID); err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetLeaderboard] Invalid university id", zap.Error(err))
+			return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid university id")
+		}
+
+		resp.UniversityID = &req.UniversityID
+	}
+
+	if req.SubThreadID != "" {
+		if _, err := uuid.Parse(req.SubThreadID); err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetLeaderboard] Invalid subthread id", zap.Error(err))
+			return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid subthread id")
+		}
+
+		subThread, err := s.subThreadRepo.GetByID(req.SubThreadID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				s.cfg.Logger().ErrorWithContext(ctx, "[GetLeaderboard] SubThread not found", zap.Error(err))
+				return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("SubThread not found")
+			}
+
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetLeaderboard] Failed to get subthread by id", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		err = checkUniversitySubThreadAccess(ctx, s.cfg, s.userRepo, s.roleRepo, "GetLeaderboard", subThread, req.UserID)
+		if err != nil {
+			return resp, err
+		}
+
+		resp.SubThreadID = &req.SubThreadID
+	}
+
+	// The points gained in the university subthreads the user cannot access are left out of the other leaderboards
+	universityID, canAccessAll, err := getUserUniversityAccess(s.userRepo, s.roleRepo, req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetLeaderboard] Failed to get user university access", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	req.UserUniversityID = universityID
+	req.CanAccessAllUniversities = canAccessAll
+
+	if req.Limit <= 0 || req.Limit > constants.LEADERBOARD_MAX_LIMIT {
+		req.Limit = constants.LEADERBOARD_MAX_LIMIT
+	}
+
+	entries, err := s.leaderboardRepo.GetList(req, days)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetLeaderboard] Failed to get leaderboard", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get leaderboard")
+	}
+
+	resp.Period = req.Period
+	resp.Data = entries
+
+	return resp, nil
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/realtime"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/google/uuid"
+	"github.com/samber/oops"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type moderationService struct {
+	cfg         config.Config
+	reportRepo  repository.ReportRepository
+	threadRepo  repository.ThreadRepository
+	uniRepo     repository.UniversityRepository
+	userRepo    repository.UserRepository
+	roleRepo    repository.RoleRepository
+	userSvc     UserService
+	realtimeHub *realtime.Hub
+	db          *bun.DB
+}
+
+func NewModerationService(cfg config.Config, reportRepo repository.ReportRepository, threadRepo repository.ThreadRepository, uniRepo repository.UniversityRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, userSvc UserService, realtimeHub *realtime.Hub, db *bun.DB) ModerationService {
+
+	return &moderationService{
+		cfg:         cfg,
+		reportRepo:  reportRepo,
+		threadRepo:  threadRepo,
+		uniRepo:     uniRepo,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		userSvc:     userSvc,
+		realtimeHub: realtimeHub,
+		db:          db,
+	}
+}
+
+// reportedContent holds the parts of a reported content needed to moderate it
+type reportedContent struct {
+	AuthorID        string
+	ThreadID        string
+	ThreadCommentID string
+	ParentReplyID   *string
+	UniversityID    string
+}
+
+func (s *moderationService) CreateReport(ctx context.Context, req request.CreateReportReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ModerationService.CreateReport", "service")
+	//defer endFunc()
+
+	content, err := s.getReportedContent(req.ContentType, req.ContentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[CreateReport] Reported content not found", zap.String("content_type", req.ContentType), zap.String("content_id", req.ContentID), zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Content not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateReport] Failed to get reported content", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if content.AuthorID == req.UserID {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateReport] User cannot report their own content")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Cannot report your own content")
+	}
+
+	existingReport, err := s.reportRepo.GetPendingReportByReporterAndContent(req.UserID, req.ContentType, req.ContentID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateReport] Failed to get existing report", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingReport != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateReport] Content already reported by user")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Content already reported")
+	}
+
+	report := &model.Report{
+		ID:              uuid.NewString(),
+		ReporterID:      req.UserID,
+		ContentType:     req.ContentType,
+		ContentID:       req.ContentID,
+		ContentAuthorID: content.AuthorID,
+		Reason:          req.Reason,
+		Status:          constants.REPORT_STATUS_PENDING,
+		CreatedBy:       req.UserEmail,
+	}
+
+	if req.Description != "" {
+		report.Description = pkg.ToPointer(req.Description)
+	}
+
+	err = s.reportRepo.Save(report)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateReport] Failed to insert report to database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create report")
+	}
+
+	return nil
+}
+
+func (s *moderationService) GetModerationQueue(ctx context.Context, req request.GetModerationQueueReq) (response.GetModerationQueueResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ModerationService.GetModerationQueue", "service")
+	//defer endFunc()
+
+	var resp response.GetModerationQueueResponse
+
+	if req.Status == "" {
+		req.Status = constants.REPORT_STATUS_PENDING
+	}
+
+	queueItems, pagination, err := s.reportRepo.GetQueue(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetModerationQueue] Failed to get moderation queue", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get moderation queue")
+	}
+
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	resp.Data = queueItems
+
+	return resp, nil
+}
+
+func (s *moderationService) GetContentReports(ctx context.Context, req request.GetContentReportsReq) ([]model.Report, error) {
+	//ctx, endFunc := trace.Start(ctx, "ModerationService.GetContentReports", "service")
+	//defer endFunc()
+
+	reports, err := s.reportRepo.GetReportsByContent(req.ContentType, req.ContentID, req.Status)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetContentReports] Failed to get content reports", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get content reports")
+	}
+
+	return reports, nil
+}
+
+func (s *moderationService) ResolveReport(ctx context.Context, req request.ResolveReportReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ModerationService.ResolveReport", "service")
+	//defer endFunc()
+
+	pendingReports, err := s.reportRepo.GetReportsByContent(req.ContentType, req.ContentID, constants.REPORT_STATUS_PENDING)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to get pending reports", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if len(pendingReports) == 0 {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] No pending reports for content", zap.String("content_id", req.ContentID))
+		return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("No pending reports for content")
+	}
+
+	authorID := pendingReports[0].ContentAuthorID
+
+	if req.Action == constants.MODERATION_ACTION_BAN_AUTHOR {
+		canBan, err := s.roleRepo.HasPermission(req.UserID, constants.PERMISSION_BAN_USER, "", "")
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to check ban permission", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		if !canBan {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] User does not have permission to ban", zap.String("user_id", req.UserID))
+			return oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("User does not have permission")
+		}
+
+		// Ban before resolving the reports, BanUser is a no-op for users that are already banned so a retry is safe
+		err = s.userSvc.BanUser(ctx, request.BanUserReq{
+			BanUserID: authorID,
+			UserID:    req.UserID,
+			UserEmail: req.UserEmail,
+		})
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to ban content author", zap.Error(err))
+			return err
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	shouldHideContent := req.Action == constants.MODERATION_ACTION_HIDE_CONTENT ||
+		(req.HideContent && req.Action != constants.MODERATION_ACTION_DISMISS)
+
+	if shouldHideContent {
+		err = s.hideContentTx(req.ContentType, req.ContentID, req.UserEmail, tx)
+		if err != nil {
+			tx.Rollback()
+			s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to hide reported content", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to hide reported content")
+		}
+	}
+
+	if req.Action == constants.MODERATION_ACTION_WARN_AUTHOR {
+		userWarning := &model.UserWarning{
+			ID:          uuid.NewString(),
+			UserID:      authorID,
+			ContentType: req.ContentType,
+			ContentID:   req.ContentID,
+			CreatedBy:   req.UserEmail,
+		}
+
+		if req.Note != "" {
+			userWarning.Note = pkg.ToPointer(req.Note)
+		}
+
+		err = s.reportRepo.SaveUserWarningTx(userWarning, tx)
+		if err != nil {
+			tx.Rollback()
+			s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to insert user warning to database", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to warn content author")
+		}
+	}
+
+	status := constants.REPORT_STATUS_RESOLVED
+	if req.Action == constants.MODERATION_ACTION_DISMISS {
+		status = constants.REPORT_STATUS_DISMISSED
+	}
+
+	updateValues := map[string]interface{}{
+		"status":            status,
+		"resolution_action": req.Action,
+		"resolved_by":       req.UserEmail,
+		"resolved_at":       time.Now(),
+		"updated_by":        req.UserEmail,
+		"updated_at":        time.Now(),
+	}
+
+	if req.Note != "" {
+		updateValues["resolution_note"] = req.Note
+	}
+
+	err = s.reportRepo.ResolvePendingReportsByContentTx(req.ContentType, req.ContentID, updateValues, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to resolve reports", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to resolve reports")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ResolveReport] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if shouldHideContent && req.ContentType == constants.REPORT_CONTENT_TYPE_THREAD {
+		s.realtimeHub.Publish(ctx, realtime.Event{
+			Type:     constants.THREAD_EVENT_THREAD_DELETED,
+			ThreadID: req.ContentID,
+		})
+	}
+
+	return nil
+}
+
+func (s *moderationService) getReportedContent(contentType string, contentID string) (reportedContent, error) {
+
+	var content reportedContent
+
+	switch contentType {
+	case constants.REPORT_CONTENT_TYPE_THREAD:
+		thread, err := s.threadRepo.GetByIDSimple(contentID)
+		if err != nil {
+			return content, err
+		}
+
+		content.AuthorID = thread.UserID
+		content.ThreadID = thread.ID
+	case constants.REPORT_CONTENT_TYPE_THREAD_COMMENT:
+		tc, err := s.threadRepo.GetThreadCommentByID(contentID)
+		if err != nil {
+			return content, err
+		}
+
+		content.AuthorID = tc.UserID
+		content.ThreadID = tc.ThreadID
+	case constants.REPORT_CONTENT_TYPE_THREAD_COMMENT_REPLY:
+		tcr, err := s.threadRepo.GetThreadCommentReplyByID(contentID)
+		if err != nil {
+			return content, err
+		}
+
+		content.AuthorID = tcr.UserID
+		content.ThreadID = tcr.ThreadID
+		content.ThreadCommentID = tcr.ThreadCommentID
+		content.ParentReplyID = tcr.ParentReplyID
+	case constants.REPORT_CONTENT_TYPE_UNIVERSITY_RATING:
+		unir, err := s.uniRepo.GetUniversityRatingByID(contentID)
+		if err != nil {
+			return content, err
+		}
+
+		content.AuthorID = unir.UserID
+		content.UniversityID = unir.UniversityID
+	default:
+		return content, sql.ErrNoRows
+	}
+
+	return content, nil
+}
+
+// hideContentTx soft deletes the reported content, content that is already deleted is skipped and its counters are left as is
+func (s *moderationService) hideContentTx(contentType string, contentID string, moderatorEmail string, tx bun.Tx) error {
+
+	content, err := s.getReportedContent(contentType, contentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	}
+
+	updateValues := map[string]interface{}{
+		"deleted_by": moderatorEmail,
+		"deleted_at": time.Now(),
+	}
+
+	switch contentType {
+	case constants.REPORT_CONTENT_TYPE_THREAD:
+		_, err = s.threadRepo.DeleteByIDTx(contentID, updateValues, tx)
+		return err
+	case constants.REPORT_CONTENT_TYPE_THREAD_COMMENT:
+		isDeleted, err := s.threadRepo.DeleteThreadCommentByIDTx(contentID, updateValues, tx)
+		if err != nil || !isDeleted {
+			return err
+		}
+
+		return s.threadRepo.DecrementCommentsCountTx(content.ThreadID, tx)
+	case constants.REPORT_CONTENT_TYPE_THREAD_COMMENT_REPLY:
+		isDeleted, err := s.threadRepo.DeleteThreadCommentReplyByIDTx(contentID, updateValues, tx)
+		if err != nil || !isDeleted {
+			return err
+		}
+
+		err = s.threadRepo.DecrementCommentReplyCountTx(content.ThreadCommentID, tx)
+		if err != nil {
+			return err
+		}
+
+		if content.ParentReplyID != nil {
+			err = s.threadRepo.DecrementCommentReplyReplyCountTx(*content.ParentReplyID, tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.threadRepo.DecrementCommentsCountTx(content.ThreadID, tx)
+	case constants.REPORT_CONTENT_TYPE_UNIVERSITY_RATING:
+		err = s.uniRepo.UpdateUniversityRatingByIDTx(contentID, updateValues, tx)
+		if err != nil {
+			return err
+		}
+
+		// Let the author rate the university again once their rating is taken down
+		return s.userRepo.SetUserHasRateUniversityTx(content.AuthorID, false, tx)
+	}
+
+	return nil
+}
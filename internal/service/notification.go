@@ -2,26 +2,36 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
 	"github.com/andibalo/meowhasiswa-be/pkg/integration/notifsvc"
+	"github.com/google/uuid"
 	"github.com/samber/oops"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
 type notificationService struct {
-	cfg         config.Config
-	notifClient notifsvc.INotifSvc
+	cfg              config.Config
+	notifClient      notifsvc.INotifSvc
+	notificationRepo repository.NotificationRepository
 }
 
-func NewNotificationService(cfg config.Config, notifClient notifsvc.INotifSvc) NotificationService {
+func NewNotificationService(cfg config.Config, notifClient notifsvc.INotifSvc, notificationRepo repository.NotificationRepository) NotificationService {
 
 	return &notificationService{
-		cfg:         cfg,
-		notifClient: notifClient,
+		cfg:              cfg,
+		notifClient:      notifClient,
+		notificationRepo: notificationRepo,
 	}
 }
 
@@ -44,3 +54,125 @@ func (s *notificationService) SendPushNotification(ctx context.Context, req requ
 
 	return nil
 }
+
+func (s *notificationService) GetNotificationList(ctx context.Context, req request.GetNotificationListReq) (response.GetNotificationListResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "NotificationService.GetNotificationList", "service")
+	//defer endFunc()
+
+	var (
+		resp response.GetNotificationListResponse
+	)
+
+	notifications, pagination, err := s.notificationRepo.GetList(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetNotificationList] Failed to get notification list", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get notification list")
+	}
+
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	resp.Data = notifications
+
+	return resp, nil
+}
+
+func (s *notificationService) GetUnreadNotificationCount(ctx context.Context, req request.GetUnreadNotificationCountReq) (response.UnreadNotificationCountResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "NotificationService.GetUnreadNotificationCount", "service")
+	//defer endFunc()
+
+	var (
+		resp response.UnreadNotificationCountResponse
+	)
+
+	unreadCount, err := s.notificationRepo.CountUnreadByUserID(req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetUnreadNotificationCount] Failed to count unread notifications", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to count unread notifications")
+	}
+
+	resp.UnreadCount = unreadCount
+
+	return resp, nil
+}
+
+func (s *notificationService) MarkNotificationAsRead(ctx context.Context, req request.MarkNotificationAsReadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "NotificationService.MarkNotificationAsRead", "service")
+	//defer endFunc()
+
+	notification, err := s.notificationRepo.GetByIDAndUserID(req.NotificationID, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[MarkNotificationAsRead] Notification not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Notification not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[MarkNotificationAsRead] Failed to get notification by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if notification.IsRead {
+		return nil
+	}
+
+	updateValues := map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	}
+
+	err = s.notificationRepo.MarkAsRead(notification.ID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[MarkNotificationAsRead] Failed to mark notification as read", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to mark notification as read")
+	}
+
+	return nil
+}
+
+func (s *notificationService) MarkAllNotificationsAsRead(ctx context.Context, req request.MarkAllNotificationsAsReadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "NotificationService.MarkAllNotificationsAsRead", "service")
+	//defer endFunc()
+
+	updateValues := map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	}
+
+	err := s.notificationRepo.MarkAllAsRead(req.UserID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[MarkAllNotificationsAsRead] Failed to mark all notifications as read", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to mark all notifications as read")
+	}
+
+	return nil
+}
+
+// newNotification builds an inbox entry for recipientID. A non-empty dedupeKey makes the insert a no-op
+// when the recipient was already notified about the same event
+func newNotification(recipientID string, actorID string, eventType string, title string, content string, route string, dedupeKey string, createdBy string) model.Notification {
+
+	notification := model.Notification{
+		ID:      uuid.NewString(),
+		UserID:  recipientID,
+		Type:    eventType,
+		Title:   title,
+		Content: content,
+		Data: map[string]string{
+			constants.APP_ROUTE_KEY:  route,
+			constants.EVENT_TYPE_KEY: eventType,
+		},
+		CreatedBy: createdBy,
+	}
+
+	if actorID != "" {
+		notification.ActorID = &actorID
+	}
+
+	if dedupeKey != "" {
+		notification.DedupeKey = &dedupeKey
+	}
+
+	return notification
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/google/uuid"
+)
+
+// newOutboxMessage wraps payload into an outbox message, it is delivered by the outbox dispatcher once
+// the transaction it is saved in commits
+func newOutboxMessage(outboxType string, payload interface{}, createdBy string) (*model.OutboxMessage, error) {
+
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OutboxMessage{
+		ID:        uuid.NewString(),
+		Type:      outboxType,
+		Payload:   rawPayload,
+		Status:    constants.OUTBOX_STATUS_PENDING,
+		CreatedBy: createdBy,
+	}, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/google/uuid"
+	"github.com/samber/oops"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type reputationService struct {
+	cfg            config.Config
+	reputationRepo repository.ReputationRepository
+	userRepo       repository.UserRepository
+}
+
+func NewReputationService(cfg config.Config, reputationRepo repository.ReputationRepository, userRepo repository.UserRepository) ReputationService {
+
+	return &reputationService{
+		cfg:            cfg,
+		reputationRepo: reputationRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *reputationService) GetReputationHistory(ctx context.Context, req request.GetReputationHistoryReq) (response.GetReputationHistoryResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ReputationService.GetReputationHistory", "service")
+	//defer endFunc()
+
+	var resp response.GetReputationHistoryResponse
+
+	user, err := s.userRepo.GetPublicProfileByUsername(req.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetReputationHistory] User not found", zap.Error(err))
+			return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetReputationHistory] Failed to get user", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get reputation history")
+	}
+
+	err = checkProfileVisibility(ctx, s.cfg, s.userRepo, user, req.UserID)
+	if err != nil {
+		return resp, err
+	}
+
+	req.TargetUserID = user.ID
+
+	reputationEvents, pagination, err := s.reputationRepo.GetList(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetReputationHistory] Failed to get reputation events", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get reputation history")
+	}
+
+	resp.ReputationPoints = user.ReputationPoints
+	resp.Data = reputationEvents
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	return resp, nil
+}
+
+func (s *reputationService) RecomputeReputation(ctx context.Context, req request.RecomputeReputationReq) (response.RecomputeReputationResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ReputationService.RecomputeReputation", "service")
+	//defer endFunc()
+
+	var resp response.RecomputeReputationResponse
+
+	updatedUsers, err := s.reputationRepo.RecomputeUserTotals(req.TargetUserID, req.UserEmail)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RecomputeReputation] Failed to recompute user reputation", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to recompute user reputation")
+	}
+
+	s.cfg.Logger().InfoWithContext(ctx, "[RecomputeReputation] Recomputed user reputation", zap.String("target_user_id", req.TargetUserID), zap.Int64("updated_users", updatedUsers))
+
+	resp.UpdatedUsers = updatedUsers
+
+	return resp, nil
+}
+
+// applyVoteReputationTx moves the reputation a voter gives to the author of a content to target. Only the difference
+// with what the ledger already holds for the vote is appended, so switching or undoing a vote can't drift the total
+func applyVoteReputationTx(reputationRepo repository.ReputationRepository, userRepo repository.UserRepository, authorID string, sourceType string, sourceID string, voterID string, target int64, createdBy string, tx bun.Tx) error {
+
+	netDelta, err := reputationRepo.GetNetDeltaBySourceTx(authorID, sourceType, sourceID, voterID, tx)
+	if err != nil {
+		return err
+	}
+
+	delta := target - netDelta
+	if delta == 0 {
+		return nil
+	}
+
+	reason := constants.REPUTATION_REASON_VOTE_REMOVED
+	if target > 0 {
+		reason = constants.REPUTATION_REASON_LIKE_RECEIVED
+	} else if target < 0 {
+		reason = constants.REPUTATION_REASON_DISLIKE_RECEIVED
+	}
+
+	err = reputationRepo.SaveTx(&model.ReputationEvent{
+		ID:         uuid.NewString(),
+		UserID:     authorID,
+		SourceType: sourceType,
+		SourceID:   &sourceID,
+		ActorID:    &voterID,
+		Delta:      delta,
+		Reason:     reason,
+		CreatedBy:  createdBy,
+	}, tx)
+	if err != nil {
+		return err
+	}
+
+	return userRepo.AddUserReputationPointsTx(authorID, map[string]interface{}{
+		"reputation_points": delta,
+		"updated_by":        createdBy,
+	}, tx)
+}
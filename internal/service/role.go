@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/google/uuid"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type roleService struct {
+	cfg           config.Config
+	roleRepo      repository.RoleRepository
+	userRepo      repository.UserRepository
+	subThreadRepo repository.SubThreadRepository
+	uniRepo       repository.UniversityRepository
+}
+
+func NewRoleService(cfg config.Config, roleRepo repository.RoleRepository, userRepo repository.UserRepository, subThreadRepo repository.SubThreadRepository, uniRepo repository.UniversityRepository) RoleService {
+
+	return &roleService{
+		cfg:           cfg,
+		roleRepo:      roleRepo,
+		userRepo:      userRepo,
+		subThreadRepo: subThreadRepo,
+		uniRepo:       uniRepo,
+	}
+}
+
+func (s *roleService) GetRoles(ctx context.Context) ([]model.Role, error) {
+	//ctx, endFunc := trace.Start(ctx, "RoleService.GetRoles", "service")
+	//defer endFunc()
+
+	roles, err := s.roleRepo.GetList()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetRoles] Failed to get roles", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get roles")
+	}
+
+	return roles, nil
+}
+
+func (s *roleService) GetUserRoles(ctx context.Context, req request.GetUserRolesReq) ([]model.UserRole, error) {
+	//ctx, endFunc := trace.Start(ctx, "RoleService.GetUserRoles", "service")
+	//defer endFunc()
+
+	userRoles, err := s.roleRepo.GetUserRolesByUserID(req.TargetUserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetUserRoles] Failed to get user roles", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user roles")
+	}
+
+	return userRoles, nil
+}
+
+func (s *roleService) GrantRole(ctx context.Context, req request.GrantRoleReq) error {
+	//ctx, endFunc := trace.Start(ctx, "RoleService.GrantRole", "service")
+	//defer endFunc()
+
+	_, err := s.userRepo.GetByID(req.TargetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] User not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] Failed to get user by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	role, err := s.getRoleByName(ctx, "GrantRole", req.RoleName)
+	if err != nil {
+		return err
+	}
+
+	err = s.validateScope(ctx, role, req.ScopeID)
+	if err != nil {
+		return err
+	}
+
+	existingUserRole, err := s.roleRepo.GetUserRole(req.TargetUserID, role.ID, req.ScopeID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] Failed to get user role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingUserRole != nil {
+		s.cfg.Logger().WarnWithContext(ctx, "[GrantRole] User already has role")
+		return nil
+	}
+
+	userRole := &model.UserRole{
+		ID:        uuid.NewString(),
+		UserID:    req.TargetUserID,
+		RoleID:    role.ID,
+		ScopeType: role.ScopeType,
+		ScopeID:   req.ScopeID,
+		CreatedBy: req.UserEmail,
+	}
+
+	err = s.roleRepo.SaveUserRole(userRole)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] Failed to insert user role to database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to grant role")
+	}
+
+	return nil
+}
+
+func (s *roleService) RevokeRole(ctx context.Context, req request.RevokeRoleReq) error {
+	//ctx, endFunc := trace.Start(ctx, "RoleService.RevokeRole", "service")
+	//defer endFunc()
+
+	role, err := s.getRoleByName(ctx, "RevokeRole", req.RoleName)
+	if err != nil {
+		return err
+	}
+
+	if role.Name == constants.ADMIN_ROLE && req.TargetUserID == req.UserID {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RevokeRole] Admin cannot revoke their own admin role")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Cannot revoke your own admin role")
+	}
+
+	userRole, err := s.roleRepo.GetUserRole(req.TargetUserID, role.ID, req.ScopeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[RevokeRole] User role not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User role not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[RevokeRole] Failed to get user role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	updateValues := map[string]interface{}{
+		"deleted_by": req.UserEmail,
+		"deleted_at": time.Now(),
+	}
+
+	err = s.roleRepo.DeleteUserRoleByID(userRole.ID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RevokeRole] Failed to delete user role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to revoke role")
+	}
+
+	return nil
+}
+
+func (s *roleService) getRoleByName(ctx context.Context, funcName string, roleName string) (*model.Role, error) {
+
+	role, err := s.roleRepo.GetByName(roleName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Role not found", zap.String("role", roleName), zap.Error(err))
+			return nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Role not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get role by name", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return role, nil
+}
+
+// validateScope makes sure scoped roles are granted for an existing subthread/university
+// and global roles are not granted with a scope
+func (s *roleService) validateScope(ctx context.Context, role *model.Role, scopeID *string) error {
+
+	if role.ScopeType == nil {
+		if scopeID != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] Global role cannot be scoped", zap.String("role", role.Name))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Role %s cannot be scoped", role.Name)
+		}
+
+		return nil
+	}
+
+	if scopeID == nil || *scopeID == "" {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] Scope is required for role", zap.String("role", role.Name))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Role %s requires a scope_id", role.Name)
+	}
+
+	var err error
+
+	switch *role.ScopeType {
+	case constants.SCOPE_TYPE_SUBTHREAD:
+		_, err = s.subThreadRepo.GetByID(*scopeID)
+	case constants.SCOPE_TYPE_UNIVERSITY:
+		_, err = s.uniRepo.GetByID(*scopeID)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] Scope not found", zap.String("scope_id", *scopeID), zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("%s not found", *role.ScopeType)
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[GrantRole] Failed to get scope", zap.String("scope_id", *scopeID), zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
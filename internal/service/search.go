@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/request"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+type searchService struct {
+	cfg        config.Config
+	searchRepo repository.SearchRepository
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+}
+
+func NewSearchService(cfg config.Config, searchRepo repository.SearchRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository) SearchService {
+
+	return &searchService{
+		cfg:        cfg,
+		searchRepo: searchRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+	}
+}
+
+func (s *searchService) Search(ctx context.Context, req request.SearchReq) (response.SearchResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "SearchService.Search", "service")
+	//defer endFunc()
+
+	var resp response.SearchResponse
+
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Search] Search query is empty")
+		return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Search query is required")
+	}
+
+	universityID, canAccessAll, err := getUserUniversityAccess(s.userRepo, s.roleRepo, req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Search] Failed to get user university access", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	req.UserUniversityID = universityID
+	req.CanAccessAllUniversities = canAccessAll
+
+	results, pagination, err := s.searchRepo.Search(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[Search] Failed to search", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to search")
+	}
+
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	resp.Data = results
+
+	return resp, nil
+}
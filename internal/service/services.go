@@ -9,15 +9,27 @@ import (
 
 type UserService interface {
 	GetUserProfile(ctx context.Context, req request.GetUserProfileReq) (*model.User, error)
+	UpdateUserProfile(ctx context.Context, req request.UpdateUserProfileReq) (*model.User, error)
+	GetPublicUserProfile(ctx context.Context, req request.GetPublicUserProfileReq) (response.PublicUserProfile, error)
 	CreateUserDevice(ctx context.Context, req request.CreateUserDeviceReq) error
 	GetUserDevices(ctx context.Context, req request.GetUserDevicesReq) ([]model.UserDevice, error)
 	BanUser(ctx context.Context, req request.BanUserReq) error
 	UnBanUser(ctx context.Context, req request.UnBanUserReq) error
+	BlockUser(ctx context.Context, req request.BlockUserReq) error
+	UnblockUser(ctx context.Context, req request.UnblockUserReq) error
+	GetUserBlockList(ctx context.Context, req request.GetUserBlockListReq) (response.GetUserBlockListResponse, error)
+	FollowUser(ctx context.Context, req request.FollowUserReq) error
+	UnFollowUser(ctx context.Context, req request.UnFollowUserReq) error
+	GetUserFollowers(ctx context.Context, req request.GetUserFollowListReq) (response.GetUserFollowListResponse, error)
+	GetUserFollowing(ctx context.Context, req request.GetUserFollowListReq) (response.GetUserFollowListResponse, error)
 }
 
 type AuthService interface {
 	Register(ctx context.Context, req request.RegisterUserReq) error
-	Login(ctx context.Context, req request.LoginUserReq) (token string, err error)
+	Login(ctx context.Context, req request.LoginUserReq) (response.AuthTokenResponse, error)
+	RefreshToken(ctx context.Context, req request.RefreshTokenReq) (response.AuthTokenResponse, error)
+	Logout(ctx context.Context, req request.LogoutReq) error
+	LogoutAllDevices(ctx context.Context, req request.LogoutAllDevicesReq) error
 	VerifyEmail(ctx context.Context, req request.VerifyEmailReq) (err error)
 	ResetPassword(ctx context.Context, req request.ResetPasswordReq) (err error)
 	VerifyResetPassword(ctx context.Context, req request.VerifyResetPasswordReq) (err error)
@@ -32,11 +44,30 @@ type SubThreadService interface {
 	FollowSubThread(ctx context.Context, req request.FollowSubThreadReq) error
 	UnFollowSubThread(ctx context.Context, req request.UnFollowSubThreadReq) error
 	DeleteSubThread(ctx context.Context, req request.DeleteSubThreadReq) error
+	GetSubThreadModerators(ctx context.Context, req request.GetSubThreadModeratorsReq) ([]response.SubThreadModerator, error)
+	AddSubThreadModerator(ctx context.Context, req request.AddSubThreadModeratorReq) error
+	RemoveSubThreadModerator(ctx context.Context, req request.RemoveSubThreadModeratorReq) error
+	GetSubThreadRules(ctx context.Context, req request.GetSubThreadRulesReq) ([]model.SubThreadRule, error)
+	CreateSubThreadRule(ctx context.Context, req request.CreateSubThreadRuleReq) error
+	UpdateSubThreadRule(ctx context.Context, req request.UpdateSubThreadRuleReq) error
+	DeleteSubThreadRule(ctx context.Context, req request.DeleteSubThreadRuleReq) error
+	GetSubThreadBans(ctx context.Context, req request.GetSubThreadBansReq) ([]model.SubThreadBan, error)
+	BanSubThreadUser(ctx context.Context, req request.BanSubThreadUserReq) error
+	UnbanSubThreadUser(ctx context.Context, req request.UnbanSubThreadUserReq) error
 }
 
 type ThreadService interface {
 	CreateThread(ctx context.Context, req request.CreateThreadReq) error
 	UpdateThread(ctx context.Context, req request.UpdateThreadReq) error
+	PinThread(ctx context.Context, req request.PinThreadReq) error
+	UnpinThread(ctx context.Context, req request.PinThreadReq) error
+	GetThreadRevisions(ctx context.Context, req request.GetThreadRevisionsReq) (response.GetContentRevisionsResponse, error)
+	GetThreadCommentRevisions(ctx context.Context, req request.GetThreadCommentRevisionsReq) (response.GetContentRevisionsResponse, error)
+	GetThreadCommentReplyRevisions(ctx context.Context, req request.GetThreadCommentRevisionsReq) (response.GetContentRevisionsResponse, error)
+	LockThread(ctx context.Context, req request.LockThreadReq) error
+	UnlockThread(ctx context.Context, req request.LockThreadReq) error
+	ArchiveThread(ctx context.Context, req request.ArchiveThreadReq) error
+	UnarchiveThread(ctx context.Context, req request.ArchiveThreadReq) error
 	DeleteThread(ctx context.Context, req request.DeleteThreadReq) error
 	GetThreadList(ctx context.Context, req request.GetThreadListReq) (response.GetThreadListResponse, error)
 	GetThreadDetail(ctx context.Context, req request.GetThreadDetailReq) (response.GetThreadDetailResponse, error)
@@ -47,12 +78,16 @@ type ThreadService interface {
 	LikeComment(ctx context.Context, req request.LikeCommentReq) error
 	DislikeComment(ctx context.Context, req request.DislikeCommentReq) error
 	GetThreadComments(ctx context.Context, req request.GetThreadCommentsReq) (response.GetThreadCommentsResponse, error)
+	GetThreadCommentReplies(ctx context.Context, req request.GetThreadCommentRepliesReq) (response.GetThreadCommentRepliesResponse, error)
 	DeleteThreadComment(ctx context.Context, req request.DeleteThreadCommentReq) error
 	UpdateThreadComment(ctx context.Context, req request.UpdateThreadCommentReq) error
 	DeleteThreadCommentReply(ctx context.Context, req request.DeleteThreadCommentReplyReq) error
 	UpdateThreadCommentReply(ctx context.Context, req request.UpdateThreadCommentReplyReq) error
 	SubscribeThread(ctx context.Context, req request.SubscribeThreadReq) error
 	UnSubscribeThread(ctx context.Context, req request.UnSubscribeThreadReq) error
+	SubscribeThreadEvents(ctx context.Context, req request.SubscribeThreadEventsReq) (<-chan []byte, func(), error)
+	VoteThreadPoll(ctx context.Context, req request.VoteThreadPollReq) error
+	UnVoteThreadPoll(ctx context.Context, req request.UnVoteThreadPollReq) error
 }
 
 type UniversityService interface {
@@ -63,9 +98,52 @@ type UniversityService interface {
 }
 
 type ImageService interface {
-	UploadImage(ctx context.Context, fileData model.File) (response.UploadImageResp, error)
+	UploadImage(ctx context.Context, req request.UploadImageReq) (response.UploadImageResp, error)
+	PresignImageUpload(ctx context.Context, req request.PresignImageUploadReq) (response.PresignImageUploadResp, error)
+	ConfirmImageUpload(ctx context.Context, req request.ConfirmImageUploadReq) (response.UploadImageResp, error)
 }
 
 type NotificationService interface {
 	SendPushNotification(ctx context.Context, req request.SendPushNotificationReq) error
+	GetNotificationList(ctx context.Context, req request.GetNotificationListReq) (response.GetNotificationListResponse, error)
+	GetUnreadNotificationCount(ctx context.Context, req request.GetUnreadNotificationCountReq) (response.UnreadNotificationCountResponse, error)
+	MarkNotificationAsRead(ctx context.Context, req request.MarkNotificationAsReadReq) error
+	MarkAllNotificationsAsRead(ctx context.Context, req request.MarkAllNotificationsAsReadReq) error
+}
+
+type RoleService interface {
+	GetRoles(ctx context.Context) ([]model.Role, error)
+	GetUserRoles(ctx context.Context, req request.GetUserRolesReq) ([]model.UserRole, error)
+	GrantRole(ctx context.Context, req request.GrantRoleReq) error
+	RevokeRole(ctx context.Context, req request.RevokeRoleReq) error
+}
+
+type ModerationService interface {
+	CreateReport(ctx context.Context, req request.CreateReportReq) error
+	GetModerationQueue(ctx context.Context, req request.GetModerationQueueReq) (response.GetModerationQueueResponse, error)
+	GetContentReports(ctx context.Context, req request.GetContentReportsReq) ([]model.Report, error)
+	ResolveReport(ctx context.Context, req request.ResolveReportReq) error
+}
+
+type SearchService interface {
+	Search(ctx context.Context, req request.SearchReq) (response.SearchResponse, error)
+}
+
+type ReputationService interface {
+	GetReputationHistory(ctx context.Context, req request.GetReputationHistoryReq) (response.GetReputationHistoryResponse, error)
+	RecomputeReputation(ctx context.Context, req request.RecomputeReputationReq) (response.RecomputeReputationResponse, error)
+}
+
+type LeaderboardService interface {
+	GetLeaderboard(ctx context.Context, req request.GetLeaderboardReq) (response.GetLeaderboardResponse, error)
+}
+
+type BookmarkService interface {
+	SaveBookmark(ctx context.Context, req request.SaveBookmarkReq) error
+	DeleteBookmark(ctx context.Context, req request.DeleteBookmarkReq) error
+	GetBookmarkList(ctx context.Context, req request.GetBookmarkListReq) (response.GetBookmarkListResponse, error)
+	GetBookmarkCollections(ctx context.Context, req request.GetBookmarkCollectionsReq) (response.GetBookmarkCollectionsResponse, error)
+	CreateBookmarkCollection(ctx context.Context, req request.CreateBookmarkCollectionReq) error
+	UpdateBookmarkCollection(ctx context.Context, req request.UpdateBookmarkCollectionReq) error
+	DeleteBookmarkCollection(ctx context.Context, req request.DeleteBookmarkCollectionReq) error
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
 	"github.com/andibalo/meowhasiswa-be/internal/repository"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
@@ -20,17 +21,23 @@ import (
 )
 
 type subThreadService struct {
-	cfg           config.Config
-	subThreadRepo repository.SubThreadRepository
-	db            *bun.DB
+	cfg            config.Config
+	subThreadRepo  repository.SubThreadRepository
+	roleRepo       repository.RoleRepository
+	userRepo       repository.UserRepository
+	attachmentRepo repository.AttachmentRepository
+	db             *bun.DB
 }
 
-func NewSubThreadService(cfg config.Config, subThreadRepo repository.SubThreadRepository, db *bun.DB) SubThreadService {
+func NewSubThreadService(cfg config.Config, subThreadRepo repository.SubThreadRepository, roleRepo repository.RoleRepository, userRepo repository.UserRepository, attachmentRepo repository.AttachmentRepository, db *bun.DB) SubThreadService {
 
 	return &subThreadService{
-		cfg:           cfg,
-		subThreadRepo: subThreadRepo,
-		db:            db,
+		cfg:            cfg,
+		subThreadRepo:  subThreadRepo,
+		roleRepo:       roleRepo,
+		userRepo:       userRepo,
+		attachmentRepo: attachmentRepo,
+		db:             db,
 	}
 }
 
@@ -75,7 +82,14 @@ func (s *subThreadService) GetSubThreadByID(ctx context.Context, req request.Get
 		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update subthread by id")
 	}
 
+	rules, err := s.subThreadRepo.GetRulesBySubThreadID(req.SubThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetSubThreadByID] Failed to get subthread rules", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get subthread rules")
+	}
+
 	resp.Data = subthread
+	resp.Rules = rules
 
 	return resp, nil
 }
@@ -103,16 +117,65 @@ func (s *subThreadService) CreateSubThread(ctx context.Context, req request.Crea
 		LabelColor:            req.LabelColor,
 		UniversityID:          req.UniversityID,
 		IsUniversitySubThread: req.IsUniversitySubThread,
+		OwnerID:               &req.UserID,
 		CreatedBy:             req.UserEmail,
 	}
 
-	err = s.subThreadRepo.Save(subThread)
+	subThreadOwnerRole, err := s.roleRepo.GetByName(constants.SUBTHREAD_OWNER_ROLE)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThread] Failed to get subthread owner role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	tx, err := s.db.Begin()
 	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThread] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// An uploaded image is attached before the insert so the subthread is saved with its url
+	if req.ImageAttachmentID != nil {
+		image, err := attachImageTx(ctx, s.cfg, s.attachmentRepo, *req.ImageAttachmentID, req.UserID, constants.ATTACHMENT_TARGET_TYPE_SUBTHREAD, subThread.ID, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		subThread.ImageUrl = image.URL
+	}
+
+	err = s.subThreadRepo.SaveTx(subThread, tx)
+	if err != nil {
+		tx.Rollback()
 		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThread] Failed to insert subthread to database", zap.Error(err))
 
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create subthread")
 	}
 
+	// The creator owns the subthread they created
+	userRole := &model.UserRole{
+		ID:        uuid.NewString(),
+		UserID:    req.UserID,
+		RoleID:    subThreadOwnerRole.ID,
+		ScopeType: subThreadOwnerRole.ScopeType,
+		ScopeID:   &subThread.ID,
+		CreatedBy: req.UserEmail,
+	}
+
+	err = s.roleRepo.SaveUserRoleTx(userRole, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThread] Failed to insert subthread owner role to database", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create subthread")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThread] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
 	return nil
 }
 
@@ -148,14 +211,40 @@ func (s *subThreadService) UpdateSubThread(ctx context.Context, req request.Upda
 		updateValues["is_university_subthread"] = req.IsUniversitySubThread
 	}
 
-	err = s.subThreadRepo.UpdateByID(req.SubThreadID, updateValues)
+	if req.IsRevisionPublic != nil {
+		updateValues["is_revision_public"] = req.IsRevisionPublic
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateSubThread] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if req.ImageAttachmentID != nil {
+		image, err := attachImageTx(ctx, s.cfg, s.attachmentRepo, *req.ImageAttachmentID, req.UserID, constants.ATTACHMENT_TARGET_TYPE_SUBTHREAD, req.SubThreadID, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		updateValues["image_url"] = image.URL
+	}
 
+	err = s.subThreadRepo.UpdateByIDTx(req.SubThreadID, updateValues, tx)
 	if err != nil {
+		tx.Rollback()
 		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateSubThread] Failed to update subthread in database", zap.Error(err))
 
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update subthread")
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateSubThread] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
 	return nil
 }
 
@@ -303,3 +392,452 @@ func (s *subThreadService) UnFollowSubThread(ctx context.Context, req request.Un
 
 	return nil
 }
+
+func (s *subThreadService) GetSubThreadModerators(ctx context.Context, req request.GetSubThreadModeratorsReq) ([]response.SubThreadModerator, error) {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.GetSubThreadModerators", "service")
+	//defer endFunc()
+
+	_, err := s.getSubThreadByID(ctx, "GetSubThreadModerators", req.SubThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	userRoles, err := s.roleRepo.GetUserRolesByScope(constants.SCOPE_TYPE_SUBTHREAD, req.SubThreadID, []string{constants.SUBTHREAD_OWNER_ROLE, constants.SUBTHREAD_MODERATOR_ROLE})
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetSubThreadModerators] Failed to get subthread moderators", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get subthread moderators")
+	}
+
+	moderators := []response.SubThreadModerator{}
+
+	for _, ur := range userRoles {
+		if ur.User == nil || ur.Role == nil {
+			continue
+		}
+
+		moderators = append(moderators, response.SubThreadModerator{
+			UserID:    ur.UserID,
+			Username:  ur.User.Username,
+			AvatarURL: ur.User.AvatarURL,
+			Role:      ur.Role.Name,
+			CreatedAt: ur.CreatedAt,
+		})
+	}
+
+	return moderators, nil
+}
+
+func (s *subThreadService) AddSubThreadModerator(ctx context.Context, req request.AddSubThreadModeratorReq) error {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.AddSubThreadModerator", "service")
+	//defer endFunc()
+
+	_, err := s.getSubThreadByID(ctx, "AddSubThreadModerator", req.SubThreadID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(req.TargetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[AddSubThreadModerator] User not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[AddSubThreadModerator] Failed to get user by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if user.IsBanned {
+		s.cfg.Logger().ErrorWithContext(ctx, "[AddSubThreadModerator] Banned user cannot moderate", zap.String("user_id", user.ID))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Banned user cannot moderate a subthread")
+	}
+
+	moderatorRole, err := s.roleRepo.GetByName(constants.SUBTHREAD_MODERATOR_ROLE)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[AddSubThreadModerator] Failed to get subthread moderator role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	existingUserRole, err := s.roleRepo.GetUserRole(req.TargetUserID, moderatorRole.ID, &req.SubThreadID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[AddSubThreadModerator] Failed to get user role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingUserRole != nil {
+		s.cfg.Logger().WarnWithContext(ctx, "[AddSubThreadModerator] User already moderates the subthread")
+		return nil
+	}
+
+	userRole := &model.UserRole{
+		ID:        uuid.NewString(),
+		UserID:    req.TargetUserID,
+		RoleID:    moderatorRole.ID,
+		ScopeType: moderatorRole.ScopeType,
+		ScopeID:   &req.SubThreadID,
+		CreatedBy: req.UserEmail,
+	}
+
+	err = s.roleRepo.SaveUserRole(userRole)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[AddSubThreadModerator] Failed to insert subthread moderator role to database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to add subthread moderator")
+	}
+
+	return nil
+}
+
+func (s *subThreadService) RemoveSubThreadModerator(ctx context.Context, req request.RemoveSubThreadModeratorReq) error {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.RemoveSubThreadModerator", "service")
+	//defer endFunc()
+
+	moderatorRole, err := s.roleRepo.GetByName(constants.SUBTHREAD_MODERATOR_ROLE)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RemoveSubThreadModerator] Failed to get subthread moderator role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	userRole, err := s.roleRepo.GetUserRole(req.TargetUserID, moderatorRole.ID, &req.SubThreadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[RemoveSubThreadModerator] Subthread moderator not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Subthread moderator not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[RemoveSubThreadModerator] Failed to get user role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	updateValues := map[string]interface{}{
+		"deleted_by": req.UserEmail,
+		"deleted_at": time.Now(),
+	}
+
+	err = s.roleRepo.DeleteUserRoleByID(userRole.ID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[RemoveSubThreadModerator] Failed to delete subthread moderator role", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to remove subthread moderator")
+	}
+
+	return nil
+}
+
+func (s *subThreadService) GetSubThreadRules(ctx context.Context, req request.GetSubThreadRulesReq) ([]model.SubThreadRule, error) {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.GetSubThreadRules", "service")
+	//defer endFunc()
+
+	_, err := s.getSubThreadByID(ctx, "GetSubThreadRules", req.SubThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.subThreadRepo.GetRulesBySubThreadID(req.SubThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetSubThreadRules] Failed to get subthread rules", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get subthread rules")
+	}
+
+	return rules, nil
+}
+
+func (s *subThreadService) CreateSubThreadRule(ctx context.Context, req request.CreateSubThreadRuleReq) error {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.CreateSubThreadRule", "service")
+	//defer endFunc()
+
+	_, err := s.getSubThreadByID(ctx, "CreateSubThreadRule", req.SubThreadID)
+	if err != nil {
+		return err
+	}
+
+	rules, err := s.subThreadRepo.GetRulesBySubThreadID(req.SubThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThreadRule] Failed to get subthread rules", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get subthread rules")
+	}
+
+	if len(rules) >= constants.SUBTHREAD_MAX_RULES {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThreadRule] Subthread rule limit reached")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("A subthread can have at most %d rules", constants.SUBTHREAD_MAX_RULES)
+	}
+
+	// New rules are appended after the last one
+	position := 1
+	if len(rules) > 0 {
+		position = rules[len(rules)-1].Position + 1
+	}
+
+	rule := &model.SubThreadRule{
+		ID:          uuid.NewString(),
+		SubThreadID: req.SubThreadID,
+		Title:       req.Title,
+		Description: req.Description,
+		Position:    position,
+		CreatedBy:   req.UserEmail,
+	}
+
+	err = s.subThreadRepo.SaveRule(rule)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateSubThreadRule] Failed to insert subthread rule to database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create subthread rule")
+	}
+
+	return nil
+}
+
+func (s *subThreadService) UpdateSubThreadRule(ctx context.Context, req request.UpdateSubThreadRuleReq) error {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.UpdateSubThreadRule", "service")
+	//defer endFunc()
+
+	_, err := s.getSubThreadRule(ctx, "UpdateSubThreadRule", req.SubThreadID, req.RuleID)
+	if err != nil {
+		return err
+	}
+
+	updateValues := map[string]interface{}{
+		"title":       req.Title,
+		"description": req.Description,
+		"updated_by":  req.UserEmail,
+		"updated_at":  time.Now(),
+	}
+
+	if req.Position != nil {
+		updateValues["position"] = *req.Position
+	}
+
+	err = s.subThreadRepo.UpdateRuleByID(req.RuleID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateSubThreadRule] Failed to update subthread rule in database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update subthread rule")
+	}
+
+	return nil
+}
+
+func (s *subThreadService) DeleteSubThreadRule(ctx context.Context, req request.DeleteSubThreadRuleReq) error {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.DeleteSubThreadRule", "service")
+	//defer endFunc()
+
+	_, err := s.getSubThreadRule(ctx, "DeleteSubThreadRule", req.SubThreadID, req.RuleID)
+	if err != nil {
+		return err
+	}
+
+	updateValues := map[string]interface{}{
+		"deleted_by": req.UserEmail,
+		"deleted_at": time.Now(),
+	}
+
+	err = s.subThreadRepo.UpdateRuleByID(req.RuleID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteSubThreadRule] Failed to delete subthread rule in database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete subthread rule")
+	}
+
+	return nil
+}
+
+func (s *subThreadService) GetSubThreadBans(ctx context.Context, req request.GetSubThreadBansReq) ([]model.SubThreadBan, error) {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.GetSubThreadBans", "service")
+	//defer endFunc()
+
+	bans, err := s.subThreadRepo.GetActiveBans(req.SubThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetSubThreadBans] Failed to get subthread bans", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get subthread bans")
+	}
+
+	return bans, nil
+}
+
+func (s *subThreadService) BanSubThreadUser(ctx context.Context, req request.BanSubThreadUserReq) error {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.BanSubThreadUser", "service")
+	//defer endFunc()
+
+	_, err := s.getSubThreadByID(ctx, "BanSubThreadUser", req.SubThreadID)
+	if err != nil {
+		return err
+	}
+
+	if req.TargetUserID == req.UserID {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] User cannot ban themselves")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Cannot ban yourself")
+	}
+
+	if req.ExpiredAt != nil && !req.ExpiredAt.After(time.Now()) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] Ban expiry is in the past")
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("expired_at must be in the future")
+	}
+
+	_, err = s.userRepo.GetByID(req.TargetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] User not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] Failed to get user by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// Moderators of the subthread can only be banned after their role is removed
+	isModerator, err := s.roleRepo.HasPermission(req.TargetUserID, constants.PERMISSION_MODERATE_CONTENT, constants.SCOPE_TYPE_SUBTHREAD, req.SubThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] Failed to check user permission", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if isModerator {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] Cannot ban a moderator of the subthread", zap.String("target_user_id", req.TargetUserID))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Cannot ban a moderator of the subthread")
+	}
+
+	expiredAt := bun.NullTime{}
+	if req.ExpiredAt != nil {
+		expiredAt = bun.NullTime{Time: *req.ExpiredAt}
+	}
+
+	existingBan, err := s.subThreadRepo.GetBanByUserID(req.SubThreadID, req.TargetUserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] Failed to get subthread ban", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// A user has a single ban per subthread, banning again replaces its reason and expiry
+	if existingBan != nil {
+		updateValues := map[string]interface{}{
+			"reason":     req.Reason,
+			"expired_at": expiredAt,
+			"updated_by": req.UserEmail,
+			"updated_at": time.Now(),
+		}
+
+		err = s.subThreadRepo.UpdateBanByID(existingBan.ID, updateValues)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] Failed to update subthread ban", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to ban user")
+		}
+
+		return nil
+	}
+
+	ban := &model.SubThreadBan{
+		ID:          uuid.NewString(),
+		SubThreadID: req.SubThreadID,
+		UserID:      req.TargetUserID,
+		Reason:      req.Reason,
+		ExpiredAt:   expiredAt,
+		CreatedBy:   req.UserEmail,
+	}
+
+	err = s.subThreadRepo.SaveBan(ban)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanSubThreadUser] Failed to insert subthread ban to database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to ban user")
+	}
+
+	return nil
+}
+
+func (s *subThreadService) UnbanSubThreadUser(ctx context.Context, req request.UnbanSubThreadUserReq) error {
+	//ctx, endFunc := trace.Start(ctx, "SubThreadService.UnbanSubThreadUser", "service")
+	//defer endFunc()
+
+	ban, err := s.subThreadRepo.GetBanByUserID(req.SubThreadID, req.TargetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[UnbanSubThreadUser] Subthread ban not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Subthread ban not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnbanSubThreadUser] Failed to get subthread ban", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	updateValues := map[string]interface{}{
+		"deleted_by": req.UserEmail,
+		"deleted_at": time.Now(),
+	}
+
+	err = s.subThreadRepo.UpdateBanByID(ban.ID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnbanSubThreadUser] Failed to delete subthread ban", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unban user")
+	}
+
+	return nil
+}
+
+func (s *subThreadService) getSubThreadByID(ctx context.Context, funcName string, subThreadID string) (*model.SubThread, error) {
+
+	subThread, err := s.subThreadRepo.GetByID(subThreadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] SubThread not found", zap.Error(err))
+			return nil, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("SubThread not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get subthread by id", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return subThread, nil
+}
+
+// getSubThreadRule also makes sure the rule belongs to the subthread the permission was checked for
+func (s *subThreadService) getSubThreadRule(ctx context.Context, funcName string, subThreadID string, ruleID string) (*model.SubThreadRule, error) {
+
+	rule, err := s.subThreadRepo.GetRuleByID(ruleID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get subthread rule by id", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if rule == nil || rule.SubThreadID != subThreadID {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Subthread rule not found", zap.String("rule_id", ruleID))
+		return nil, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Subthread rule not found")
+	}
+
+	return rule, nil
+}
+
+// getUserUniversityAccess returns the university of the user and whether they can access the subthreads of every university
+func getUserUniversityAccess(userRepo repository.UserRepository, roleRepo repository.RoleRepository, userID string) (*string, bool, error) {
+
+	if userID == "" {
+		return nil, false, nil
+	}
+
+	user, err := userRepo.GetByID(userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	canAccessAll, err := roleRepo.HasPermission(userID, constants.PERMISSION_ACCESS_ALL_UNIVERSITIES, "", "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user.UniversityID, canAccessAll, nil
+}
+
+// checkUniversitySubThreadAccess allows everyone in a public subthread. A university subthread is only open to the
+// students of its university, and to the users allowed to access every university
+func checkUniversitySubThreadAccess(ctx context.Context, cfg config.Config, userRepo repository.UserRepository, roleRepo repository.RoleRepository, funcName string, subThread *model.SubThread, userID string) error {
+
+	if !subThread.IsUniversitySubThread || subThread.UniversityID == nil {
+		return nil
+	}
+
+	universityID, canAccessAll, err := getUserUniversityAccess(userRepo, roleRepo, userID)
+	if err != nil {
+		cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get user university access", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if canAccessAll || (universityID != nil && *universityID == *subThread.UniversityID) {
+		return nil
+	}
+
+	cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] User is not a student of the subthread university", zap.String("user_id", userID), zap.String("subthread_id", subThread.ID))
+	return oops.Code(response.NotUniversityMember.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("This subthread is only open to students of its university")
+}
@@ -8,11 +8,13 @@ import (
 	"github.com/andibalo/meowhasiswa-be/internal/config"
 	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/realtime"
 	"github.com/andibalo/meowhasiswa-be/internal/repository"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
 	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/db"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
 	"github.com/andibalo/meowhasiswa-be/pkg/integration/notifsvc"
 	"github.com/google/uuid"
@@ -20,25 +22,50 @@ import (
 	"github.com/uptrace/bun"
 	"go.uber.org/zap"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
+// likeMilestones are the like counts at which the author of a thread or comment gets notified
+var likeMilestones = []int64{10, 50, 100, 500, 1000}
+
 type threadService struct {
-	cfg        config.Config
-	threadRepo repository.ThreadRepository
-	userRepo   repository.UserRepository
-	notifCl    notifsvc.INotifSvc
-	db         *bun.DB
+	cfg              config.Config
+	threadRepo       repository.ThreadRepository
+	threadPollRepo   repository.ThreadPollRepository
+	attachmentRepo   repository.AttachmentRepository
+	userRepo         repository.UserRepository
+	subThreadRepo    repository.SubThreadRepository
+	roleRepo         repository.RoleRepository
+	reputationRepo   repository.ReputationRepository
+	badgeRepo        repository.BadgeRepository
+	notificationRepo repository.NotificationRepository
+	outboxRepo       repository.OutboxRepository
+	revisionRepo     repository.RevisionRepository
+	userBlockRepo    repository.UserBlockRepository
+	realtimeHub      *realtime.Hub
+	db               *bun.DB
 }
 
-func NewThreadService(cfg config.Config, threadRepo repository.ThreadRepository, userRepo repository.UserRepository, notifCl notifsvc.INotifSvc, db *bun.DB) ThreadService {
+func NewThreadService(cfg config.Config, threadRepo repository.ThreadRepository, threadPollRepo repository.ThreadPollRepository, attachmentRepo repository.AttachmentRepository, userRepo repository.UserRepository, subThreadRepo repository.SubThreadRepository, roleRepo repository.RoleRepository, reputationRepo repository.ReputationRepository, badgeRepo repository.BadgeRepository, notificationRepo repository.NotificationRepository, outboxRepo repository.OutboxRepository, revisionRepo repository.RevisionRepository, userBlockRepo repository.UserBlockRepository, realtimeHub *realtime.Hub, db *bun.DB) ThreadService {
 
 	return &threadService{
-		cfg:        cfg,
-		threadRepo: threadRepo,
-		userRepo:   userRepo,
-		notifCl:    notifCl,
-		db:         db,
+		cfg:              cfg,
+		threadRepo:       threadRepo,
+		threadPollRepo:   threadPollRepo,
+		attachmentRepo:   attachmentRepo,
+		userRepo:         userRepo,
+		subThreadRepo:    subThreadRepo,
+		roleRepo:         roleRepo,
+		reputationRepo:   reputationRepo,
+		badgeRepo:        badgeRepo,
+		notificationRepo: notificationRepo,
+		outboxRepo:       outboxRepo,
+		revisionRepo:     revisionRepo,
+		userBlockRepo:    userBlockRepo,
+		realtimeHub:      realtimeHub,
+		db:               db,
 	}
 }
 
@@ -60,22 +87,269 @@ func (s *threadService) CreateThread(ctx context.Context, req request.CreateThre
 		CreatedBy:      req.UserEmail,
 	}
 
-	err := s.threadRepo.Save(thread)
+	var (
+		threadPoll        *model.ThreadPoll
+		threadPollOptions []model.ThreadPollOption
+		err               error
+	)
 
+	err = s.checkSubThreadAccess(ctx, "CreateThread", req.SubThreadID, req.UserID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CreateThread] Failed to insert thread to database", zap.Error(err))
+		return err
+	}
+
+	err = s.checkSubThreadBan(ctx, "CreateThread", req.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if req.Poll != nil {
+		threadPoll, threadPollOptions, err = s.newThreadPoll(ctx, thread.ID, *req.Poll, req.UserEmail)
+		if err != nil {
+			return err
+		}
+	}
+
+	user, err := s.userRepo.GetByID(req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateThread] Failed to get user by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user data")
+	}
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateThread] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.threadRepo.SaveTx(thread, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateThread] Failed to insert thread to database", zap.Error(err))
+		tx.Rollback()
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create thread")
 	}
 
+	err = s.attachTx(ctx, req.AttachmentIDs, req.UserID, constants.ATTACHMENT_TARGET_TYPE_THREAD, thread.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if threadPoll != nil {
+		err = s.saveThreadPollTx(ctx, threadPoll, threadPollOptions, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = evaluateThreadBadgesTx(s.badgeRepo, s.notificationRepo, thread, req.UserEmail, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateThread] Failed to evaluate thread badges", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to evaluate thread badges")
+	}
+
+	err = evaluateVerifiedStudentBadgeTx(s.badgeRepo, s.notificationRepo, user, req.UserEmail, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateThread] Failed to evaluate verified student badge", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to evaluate verified student badge")
+	}
+
+	err = s.notifyFollowersTx(ctx, thread, user.Username, req.UserEmail, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CreateThread] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+// notifyFollowersTx queues the notifications of a new thread for the followers of its author, the outbox dispatcher
+// expands the message page by page so the transaction stays the same size whatever the number of followers. In a
+// university subthread only the students of that university are notified
+func (s *threadService) notifyFollowersTx(ctx context.Context, thread *model.Thread, username string, createdBy string, tx bun.Tx) error {
+
+	subThread, err := s.subThreadRepo.GetByID(thread.SubThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[notifyFollowersTx] Failed to get subthread by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	payload := model.FollowerFanOutPayload{
+		AuthorID: thread.UserID,
+		ThreadID: thread.ID,
+		Title:    fmt.Sprintf("%s posted a new thread!", username),
+		Content:  pkg.TruncateWithEllipsis(thread.Title, 50),
+	}
+
+	if subThread.IsUniversitySubThread {
+		payload.UniversityID = subThread.UniversityID
+	}
+
+	outboxMessage, err := newOutboxMessage(constants.OUTBOX_TYPE_FOLLOWER_FAN_OUT, payload, createdBy)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[notifyFollowersTx] Failed to build follower fan out outbox message", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.outboxRepo.SaveTx(outboxMessage, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[notifyFollowersTx] Failed to save follower fan out outbox message", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save follower fan out outbox message")
+	}
+
+	return nil
+}
+
+func (s *threadService) saveThreadPollTx(ctx context.Context, threadPoll *model.ThreadPoll, threadPollOptions []model.ThreadPollOption, tx bun.Tx) error {
+
+	err := s.threadPollRepo.SaveTx(threadPoll, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[saveThreadPollTx] Failed to insert thread poll to database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create thread poll")
+	}
+
+	err = s.threadPollRepo.BulkSaveOptionsTx(threadPollOptions, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[saveThreadPollTx] Failed to insert thread poll options to database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create thread poll")
+	}
+
 	return nil
 }
 
+// attachTx links the uploads of the user to the target in the given order, the caller rolls back on error
+func (s *threadService) attachTx(ctx context.Context, attachmentIDs []string, userID string, targetType string, targetID string, tx bun.Tx) error {
+
+	if len(attachmentIDs) == 0 {
+		return nil
+	}
+
+	attachedIDs, err := s.attachmentRepo.AttachTx(attachmentIDs, userID, targetType, targetID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[attachTx] Failed to attach uploads", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to attach uploads")
+	}
+
+	if len(attachedIDs) != len(attachmentIDs) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[attachTx] Some uploads do not exist or are already attached", zap.Strings("attachment_ids", attachmentIDs))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Attachment does not exist or is already attached")
+	}
+
+	return nil
+}
+
+// getAttachmentsByTargets returns the attachments of each target keyed by the target id
+func (s *threadService) getAttachmentsByTargets(ctx context.Context, targetType string, targetIDs []string) (map[string][]response.Attachment, error) {
+
+	attachments, err := s.attachmentRepo.GetByTargets(targetType, targetIDs)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getAttachmentsByTargets] Failed to get attachments", zap.String("target_type", targetType), zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get attachments")
+	}
+
+	attachmentsByTarget := map[string][]response.Attachment{}
+
+	for _, a := range attachments {
+		if a.TargetID == nil {
+			continue
+		}
+
+		attachmentsByTarget[*a.TargetID] = append(attachmentsByTarget[*a.TargetID], response.Attachment{
+			ID:          a.ID,
+			URL:         a.URL,
+			ContentType: a.ContentType,
+			Width:       a.Width,
+			Height:      a.Height,
+			AltText:     a.AltText,
+			Position:    a.Position,
+			Variants:    mapImageVariants(a.Variants),
+		})
+	}
+
+	return attachmentsByTarget, nil
+}
+
+// getUserBadgesByUserIDs returns the badges of each user keyed by the user id
+func (s *threadService) getUserBadgesByUserIDs(ctx context.Context, userIDs []string) (map[string][]response.UserBadge, error) {
+
+	userBadges, err := s.badgeRepo.GetUserBadgesByUserIDs(userIDs)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getUserBadgesByUserIDs] Failed to get user badges", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user badges")
+	}
+
+	userBadgesByUser := map[string][]*model.UserBadge{}
+
+	for _, ub := range userBadges {
+		userBadgesByUser[ub.UserID] = append(userBadgesByUser[ub.UserID], ub)
+	}
+
+	badgesByUser := map[string][]response.UserBadge{}
+
+	for _, userID := range userIDs {
+		badgesByUser[userID] = mapUserBadges(userBadgesByUser[userID])
+	}
+
+	return badgesByUser, nil
+}
+func (s *threadService) newThreadPoll(ctx context.Context, threadID string, req request.CreateThreadPollReq, createdBy string) (*model.ThreadPoll, []model.ThreadPollOption, error) {
+
+	if req.ClosesAt != nil && !req.ClosesAt.After(time.Now()) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[newThreadPoll] Poll close time is in the past")
+		return nil, nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Poll close time must be in the future")
+	}
+
+	threadPoll := &model.ThreadPoll{
+		ID:               uuid.NewString(),
+		ThreadID:         threadID,
+		Question:         strings.TrimSpace(req.Question),
+		IsMultipleChoice: req.IsMultipleChoice,
+		CreatedBy:        createdBy,
+	}
+
+	if req.ClosesAt != nil {
+		threadPoll.ClosesAt = bun.NullTime{Time: *req.ClosesAt}
+	}
+
+	threadPollOptions := []model.ThreadPollOption{}
+	seenOptions := map[string]bool{}
+
+	for i, option := range req.Options {
+		content := strings.TrimSpace(option)
+
+		if content == "" || seenOptions[strings.ToLower(content)] {
+			s.cfg.Logger().ErrorWithContext(ctx, "[newThreadPoll] Poll options must be unique and not empty")
+			return nil, nil, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Poll options must be unique and not empty")
+		}
+
+		seenOptions[strings.ToLower(content)] = true
+
+		threadPollOptions = append(threadPollOptions, model.ThreadPollOption{
+			ID:        uuid.NewString(),
+			PollID:    threadPoll.ID,
+			Content:   content,
+			Position:  i,
+			CreatedBy: createdBy,
+		})
+	}
+
+	return threadPoll, threadPollOptions, nil
+}
+
 func (s *threadService) UpdateThread(ctx context.Context, req request.UpdateThreadReq) error {
 	//ctx, endFunc := trace.Start(ctx, "ThreadService.UpdateThread", "service")
 	//defer endFunc()
 
-	_, err := s.threadRepo.GetByID(req.ThreadID)
+	thread, err := s.threadRepo.GetByID(req.ThreadID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThread] Thread not found", zap.Error(err))
@@ -86,22 +360,67 @@ func (s *threadService) UpdateThread(ctx context.Context, req request.UpdateThre
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread by id")
 	}
 
+	// Moderators of the subthread can edit the threads of other users
+	if thread.UserID != req.UserID {
+		err = s.checkSubThreadPermission(ctx, "UpdateThread", req.UserID, constants.PERMISSION_MODERATE_CONTENT, thread.SubThreadID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if thread.Title == req.Title && thread.Content == req.Content && thread.ContentSummary == req.ContentSummary {
+		return nil
+	}
+
+	now := time.Now()
+
 	updateValues := map[string]interface{}{
 		"title":           req.Title,
 		"content":         req.Content,
 		"content_summary": req.ContentSummary,
+		"edited_at":       now,
 		"updated_by":      req.UserEmail,
-		"updated_at":      time.Now(),
+		"updated_at":      now,
 	}
 
-	err = s.threadRepo.UpdateByID(req.ThreadID, updateValues)
+	revision := &model.ContentRevision{
+		ID:             uuid.NewString(),
+		TargetType:     constants.REVISION_TARGET_TYPE_THREAD,
+		TargetID:       thread.ID,
+		ThreadID:       thread.ID,
+		Title:          pkg.ToPointer(thread.Title),
+		Content:        thread.Content,
+		ContentSummary: pkg.ToPointer(thread.ContentSummary),
+		CreatedBy:      req.UserEmail,
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThread] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.revisionRepo.SaveTx(revision, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThread] Failed to save thread revision", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread revision")
+	}
 
+	err = s.threadRepo.UpdateByIDTx(req.ThreadID, updateValues, tx)
 	if err != nil {
+		tx.Rollback()
 		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThread] Failed to update thread in database", zap.Error(err))
 
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread")
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThread] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
 	return nil
 }
 
@@ -109,7 +428,7 @@ func (s *threadService) DeleteThread(ctx context.Context, req request.DeleteThre
 	//ctx, endFunc := trace.Start(ctx, "ThreadService.DeleteThread", "service")
 	//defer endFunc()
 
-	_, err := s.threadRepo.GetByID(req.ThreadID)
+	thread, err := s.threadRepo.GetByID(req.ThreadID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThread] Thread not found", zap.Error(err))
@@ -120,6 +439,14 @@ func (s *threadService) DeleteThread(ctx context.Context, req request.DeleteThre
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread by id")
 	}
 
+	// Moderators of the subthread can remove the threads of other users
+	if thread.UserID != req.UserID {
+		err = s.checkSubThreadPermission(ctx, "DeleteThread", req.UserID, constants.PERMISSION_MODERATE_CONTENT, thread.SubThreadID)
+		if err != nil {
+			return err
+		}
+	}
+
 	updateValues := map[string]interface{}{
 		"deleted_by": req.UserEmail,
 		"deleted_at": time.Now(),
@@ -133,1057 +460,2269 @@ func (s *threadService) DeleteThread(ctx context.Context, req request.DeleteThre
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread")
 	}
 
+	s.realtimeHub.Publish(ctx, realtime.Event{
+		Type:     constants.THREAD_EVENT_THREAD_DELETED,
+		ThreadID: req.ThreadID,
+	})
+
 	return nil
 }
 
-func (s *threadService) GetThreadList(ctx context.Context, req request.GetThreadListReq) (response.GetThreadListResponse, error) {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadList", "service")
+func (s *threadService) PinThread(ctx context.Context, req request.PinThreadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.PinThread", "service")
 	//defer endFunc()
 
-	var resp response.GetThreadListResponse
-
-	threads, pagination, err := s.threadRepo.GetList(req)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadList] Failed to get thread list", zap.Error(err))
-
-		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread list")
-	}
-
-	resp.Meta = response.PaginationMeta{
-		CurrentCursor: pagination.CurrentCursor,
-		NextCursor:    pagination.NextCursor,
-	}
+	return s.setThreadPinned(ctx, "PinThread", req, true)
+}
 
-	resp.Data = s.mapThreadListData(threads)
+func (s *threadService) UnpinThread(ctx context.Context, req request.PinThreadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.UnpinThread", "service")
+	//defer endFunc()
 
-	return resp, nil
+	return s.setThreadPinned(ctx, "UnpinThread", req, false)
 }
 
-func (s *threadService) mapThreadListData(threads []model.Thread) []response.ThreadListData {
+func (s *threadService) setThreadPinned(ctx context.Context, funcName string, req request.PinThreadReq, isPinned bool) error {
 
-	threadData := []response.ThreadListData{}
+	thread, err := s.getModeratedThread(ctx, funcName, req.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
 
-	for _, t := range threads {
+	if thread.IsPinned == isPinned {
+		return nil
+	}
 
-		tld := response.ThreadListData{
-			ID:             t.ID,
-			UserID:         t.UserID,
-			UserName:       t.User.Username,
-			SubThreadID:    t.SubThreadID,
-			SubThreadName:  t.SubThread.Name,
-			SubThreadColor: t.SubThread.LabelColor,
-			Title:          t.Title,
-			Content:        t.Content,
-			ContentSummary: t.ContentSummary,
-			IsActive:       t.IsActive,
-			LikeCount:      t.LikeCount,
-			DislikeCount:   t.DislikeCount,
-			CommentCount:   t.CommentCount,
-			CreatedBy:      t.CreatedBy,
-			CreatedAt:      t.CreatedAt,
-			UpdatedBy:      t.UpdatedBy,
-			UpdatedAt:      t.UpdatedAt,
+	if isPinned {
+		pinnedCount, err := s.threadRepo.CountPinnedBySubThreadID(thread.SubThreadID)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to count pinned threads", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
-		if t.User.University != nil {
-			tld.UniversityAbbreviatedName = pkg.ToPointer(t.User.University.AbbreviatedName)
-			tld.UniversityImageURL = pkg.ToPointer(t.User.University.ImageURL)
+		if pinnedCount >= constants.SUBTHREAD_MAX_PINNED_THREADS {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Pinned thread limit reached", zap.String("subthread_id", thread.SubThreadID))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("A subthread can have at most %d pinned threads", constants.SUBTHREAD_MAX_PINNED_THREADS)
 		}
+	}
 
-		if t.ThreadAction != "" {
-			if t.ThreadAction == constants.LIKE_ACTION {
-				tld.IsLiked = true
-			}
+	updateValues := map[string]interface{}{
+		"is_pinned": isPinned,
+		"pinned_at": nil,
+		"pinned_by": nil,
+	}
 
-			if t.ThreadAction == constants.DISLIKE_ACTION {
-				tld.IsDisliked = true
-			}
-		}
+	if isPinned {
+		updateValues["pinned_at"] = time.Now()
+		updateValues["pinned_by"] = req.UserEmail
+	}
 
-		threadData = append(threadData, tld)
+	err = s.threadRepo.UpdateByID(req.ThreadID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to update thread in database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread")
 	}
 
-	return threadData
+	return nil
 }
 
-func (s *threadService) GetThreadDetail(ctx context.Context, req request.GetThreadDetailReq) (response.GetThreadDetailResponse, error) {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadDetail", "service")
+func (s *threadService) LockThread(ctx context.Context, req request.LockThreadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.LockThread", "service")
 	//defer endFunc()
 
-	var resp response.GetThreadDetailResponse
-
-	thread, err := s.threadRepo.GetByID(req.ThreadID)
+	return s.setThreadLocked(ctx, "LockThread", req, true)
+}
 
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Thread does not exist", zap.Error(err))
+func (s *threadService) UnlockThread(ctx context.Context, req request.LockThreadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.UnlockThread", "service")
+	//defer endFunc()
 
-			return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread does not exist")
-		}
+	return s.setThreadLocked(ctx, "UnlockThread", req, false)
+}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get thread detail", zap.Error(err))
+func (s *threadService) setThreadLocked(ctx context.Context, funcName string, req request.LockThreadReq, isLocked bool) error {
 
-		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread detail")
+	thread, err := s.getModeratedThread(ctx, funcName, req.ThreadID, req.UserID)
+	if err != nil {
+		return err
 	}
 
-	ta, err := s.threadRepo.GetLastThreadActivityByUserID(req.ThreadID, req.UserID)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get user last thread activity", zap.Error(err))
-		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread activity")
+	if thread.IsLocked == isLocked {
+		return nil
 	}
 
-	ts, err := s.threadRepo.GetThreadSubscriptionByUserAndThreadID(req.UserID, req.ThreadID)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get user thread subscription", zap.Error(err))
-		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user thread subscription")
+	updateValues := map[string]interface{}{
+		"is_locked": isLocked,
+		"locked_at": nil,
+		"locked_by": nil,
 	}
 
-	resp.Data = s.mapThreadDetailData(thread, ta, ts)
+	if isLocked {
+		updateValues["locked_at"] = time.Now()
+		updateValues["locked_by"] = req.UserEmail
+	}
 
-	return resp, nil
+	err = s.threadRepo.UpdateByID(req.ThreadID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to update thread in database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread")
+	}
+
+	return nil
 }
 
-func (s *threadService) mapThreadDetailData(thread model.Thread, threadActivity *model.ThreadActivity, threadSubscription model.ThreadSubscription) response.ThreadDetailData {
+func (s *threadService) ArchiveThread(ctx context.Context, req request.ArchiveThreadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.ArchiveThread", "service")
+	//defer endFunc()
 
-	td := response.ThreadDetailData{
-		ID:             thread.ID,
-		UserID:         thread.UserID,
-		UserName:       thread.User.Username,
-		SubThreadID:    thread.SubThreadID,
-		SubThreadName:  thread.SubThread.Name,
-		SubThreadColor: thread.SubThread.LabelColor,
-		Title:          thread.Title,
-		Content:        thread.Content,
-		ContentSummary: thread.ContentSummary,
-		IsActive:       thread.IsActive,
-		LikeCount:      thread.LikeCount,
-		DislikeCount:   thread.DislikeCount,
-		CommentCount:   thread.CommentCount,
-		CreatedBy:      thread.CreatedBy,
-		CreatedAt:      thread.CreatedAt,
-		UpdatedBy:      thread.UpdatedBy,
-		UpdatedAt:      thread.UpdatedAt,
+	return s.setThreadArchived(ctx, "ArchiveThread", req, true)
+}
+
+func (s *threadService) UnarchiveThread(ctx context.Context, req request.ArchiveThreadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.UnarchiveThread", "service")
+	//defer endFunc()
+
+	return s.setThreadArchived(ctx, "UnarchiveThread", req, false)
+}
+
+func (s *threadService) setThreadArchived(ctx context.Context, funcName string, req request.ArchiveThreadReq, isArchived bool) error {
+
+	thread, err := s.getModeratedThread(ctx, funcName, req.ThreadID, req.UserID)
+	if err != nil {
+		return err
 	}
 
-	if thread.User.University != nil {
-		td.UniversityAbbreviatedName = pkg.ToPointer(thread.User.University.AbbreviatedName)
-		td.UniversityImageURL = pkg.ToPointer(thread.User.University.ImageURL)
+	if thread.IsArchived == isArchived {
+		return nil
 	}
 
-	if threadActivity != nil {
-		if threadActivity.Action == constants.LIKE_ACTION {
-			td.IsLiked = true
-		}
+	updateValues := map[string]interface{}{
+		"is_archived": isArchived,
+		"archived_at": nil,
+		"archived_by": nil,
+	}
 
-		if threadActivity.Action == constants.DISLIKE_ACTION {
-			td.IsDisliked = true
-		}
+	if isArchived {
+		updateValues["archived_at"] = time.Now()
+		updateValues["archived_by"] = req.UserEmail
+	} else {
+		// Otherwise the thread archiver archives the thread again on its next run
+		updateValues["last_activity_at"] = time.Now()
 	}
 
-	if threadSubscription.ID != "" && threadSubscription.IsSubscribed {
-		td.IsSubscribed = true
+	err = s.threadRepo.UpdateByID(req.ThreadID, updateValues)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to update thread in database", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread")
 	}
 
-	return td
+	return nil
 }
 
-func (s *threadService) LikeThread(ctx context.Context, req request.LikeThreadReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.LikeThread", "service")
-	//defer endFunc()
-
-	var (
-		shouldDoubleIncrementUserReputationPoints bool
-	)
+// getModeratedThread gets the thread after checking the user can moderate the content of its subthread
+func (s *threadService) getModeratedThread(ctx context.Context, funcName string, threadID string, userID string) (model.Thread, error) {
 
-	existingThread, err := s.threadRepo.GetByIDSimple(req.ThreadID)
+	thread, err := s.threadRepo.GetByIDSimple(threadID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Thread not found", zap.Error(err))
-			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread not found")
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Thread not found", zap.Error(err))
+			return thread, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread not found")
 		}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to get thread by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get thread by id", zap.Error(err))
+		return thread, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	lastThreadActivity, err := s.getUserLastThreadAction(ctx, req.ThreadID, req.UserID)
+	err = s.checkSubThreadPermission(ctx, funcName, userID, constants.PERMISSION_MODERATE_CONTENT, thread.SubThreadID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to get user last thread action", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		return thread, err
 	}
 
-	tx, err := s.db.Begin()
+	return thread, nil
+}
+
+// checkSubThreadBan stops the users banned from a subthread from posting in it
+func (s *threadService) checkSubThreadBan(ctx context.Context, funcName string, subThreadID string, userID string) error {
+
+	isBanned, err := s.subThreadRepo.IsUserBanned(subThreadID, userID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to begin transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to check subthread ban", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	// Unlike if previously liked
-	if lastThreadActivity == constants.LIKE_ACTION {
-		err = s.unlikeThread(ctx, req, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to unlike thread", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unlike thread")
-		}
-
-		updateValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
-		}
+	if isBanned {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] User is banned from the subthread", zap.String("user_id", userID), zap.String("subthread_id", subThreadID))
+		return oops.Code(response.SubThreadBanned.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("You are banned from posting in this subthread")
+	}
 
-		err = s.userRepo.DecrementUserReputationPointsTx(existingThread.UserID, updateValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to decrement thread op reputation points", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread op reputation points")
-		}
+	return nil
+}
 
-		err = tx.Commit()
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to commit transaction", zap.Error(err))
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-		}
+// checkNotBlockedBy stops the actor from commenting on or replying to a user who blocked them
+func (s *threadService) checkNotBlockedBy(ctx context.Context, funcName string, userID string, actorID string) error {
 
+	if userID == actorID {
 		return nil
 	}
 
-	// Decrement dislike count if previously disliked
-	if lastThreadActivity == constants.DISLIKE_ACTION {
-		err = s.threadRepo.DecrementDislikesCountTx(req.ThreadID, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to decrement thread dislikes count", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread dislikes count")
+	userBlock, err := s.userBlockRepo.GetByBlockedUserID(userID, actorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
 		}
 
-		shouldDoubleIncrementUserReputationPoints = true
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get user block", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	err = s.threadRepo.IncrementLikesCountTx(req.ThreadID, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to increment thread likes count", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread likes count")
+	if userBlock.Type == constants.USER_BLOCK_TYPE_BLOCK {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] User is blocked", zap.String("user_id", userID), zap.String("actor_id", actorID))
+		return oops.Code(response.UserBlocked.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("You cannot reply to this user")
 	}
 
-	if lastThreadActivity != "" {
-
-		updateValues := map[string]interface{}{
-			"action":     constants.LIKE_ACTION,
-			"updated_by": req.UserEmail,
-			"updated_at": time.Now(),
-		}
-
-		err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to update thread activity", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
-		}
-
-		updateUserValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
-		}
+	return nil
+}
 
-		if shouldDoubleIncrementUserReputationPoints {
-			updateUserValues["reputation_points"] = constants.DEFAULT_INCREMENT_REPUTATION * 2
-		}
+// removeBlockingRecipients leaves out the subscribers who blocked or muted the actor and reports whether
+// recipientID, the user notified directly of the actor's comment or reply, did too
+func (s *threadService) removeBlockingRecipients(ctx context.Context, funcName string, actorID string, recipientID string, threadSubscribers []model.ThreadSubscription) ([]model.ThreadSubscription, bool, error) {
 
-		err = s.userRepo.IncrementUserReputationPointsTx(existingThread.UserID, updateUserValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to increment thread op reputation points", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread op reputation points")
-		}
+	recipientIDs := []string{recipientID}
+	for _, ts := range threadSubscribers {
+		recipientIDs = append(recipientIDs, ts.UserID)
+	}
 
-		// TODO: Save to thread activity history
+	blockingUserIDs, err := s.userBlockRepo.GetUserIDsBlocking(actorID, recipientIDs)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get users blocking the actor", zap.Error(err))
+		return threadSubscribers, false, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
 
-		err = tx.Commit()
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to commit transaction", zap.Error(err))
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-		}
+	threadSubscribers = slices.DeleteFunc(threadSubscribers, func(ts model.ThreadSubscription) bool {
+		return slices.Contains(blockingUserIDs, ts.UserID)
+	})
 
-		return nil
-	}
+	return threadSubscribers, slices.Contains(blockingUserIDs, recipientID), nil
+}
 
-	threadActivity := &model.ThreadActivity{
-		ID:            uuid.NewString(),
-		ThreadID:      req.ThreadID,
-		ActorID:       req.UserID,
-		ActorEmail:    req.UserEmail,
-		ActorUsername: req.Username,
-		Action:        constants.LIKE_ACTION,
-		CreatedBy:     req.UserEmail,
-	}
+// checkSubThreadPermission allows the user when they have the permission globally or for the subthread
+func (s *threadService) checkSubThreadPermission(ctx context.Context, funcName string, userID string, permission string, subThreadID string) error {
 
-	err = s.threadRepo.SaveThreadActivityTx(threadActivity, tx)
+	hasPermission, err := s.roleRepo.HasPermission(userID, permission, constants.SCOPE_TYPE_SUBTHREAD, subThreadID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to save thread activity", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread activity")
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to check user permission", zap.String("permission", permission), zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	updateValues := map[string]interface{}{
-		"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-		"updated_by":        req.UserEmail,
+	if !hasPermission {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] User does not have permission", zap.String("user_id", userID), zap.String("permission", permission))
+		return oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("User does not have permission")
 	}
 
-	if shouldDoubleIncrementUserReputationPoints {
-		updateValues["reputation_points"] = constants.DEFAULT_INCREMENT_REPUTATION * 2
-	}
+	return nil
+}
 
-	err = s.userRepo.IncrementUserReputationPointsTx(existingThread.UserID, updateValues, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to increment thread op reputation points", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread op reputation points")
+// checkAuthorOrModerator lets the author of the content through, anyone else has to moderate the subthread of its thread
+func (s *threadService) checkAuthorOrModerator(ctx context.Context, funcName string, authorID string, threadID string, userID string) error {
+
+	if authorID == userID {
+		return nil
 	}
 
-	err = tx.Commit()
+	thread, err := s.threadRepo.GetByIDSimple(threadID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get thread by id", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	return nil
+	return s.checkSubThreadPermission(ctx, funcName, userID, constants.PERMISSION_MODERATE_CONTENT, thread.SubThreadID)
 }
 
-func (s *threadService) getUserLastThreadAction(ctx context.Context, threadId string, userId string) (string, error) {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.getUserLastThreadAction", "service")
-	//defer endFunc()
-
-	ta, err := s.threadRepo.GetLastThreadActivityByUserID(threadId, userId)
-
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		s.cfg.Logger().ErrorWithContext(ctx, "[getUserLastThreadAction] Failed to get user last thread activity", zap.Error(err))
-		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread activity")
-	}
+// checkSubThreadAccess stops the users outside of its university from reading or posting in a university subthread
+func (s *threadService) checkSubThreadAccess(ctx context.Context, funcName string, subThreadID string, userID string) error {
 
-	if ta != nil {
+	subThread, err := s.subThreadRepo.GetByID(subThreadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] SubThread not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("SubThread not found")
+		}
 
-		return ta.Action, nil
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get subthread by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	return "", nil
+	return checkUniversitySubThreadAccess(ctx, s.cfg, s.userRepo, s.roleRepo, funcName, subThread, userID)
 }
 
-func (s *threadService) unlikeThread(ctx context.Context, req request.LikeThreadReq, tx bun.Tx) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.unlikeThread", "service")
-	//defer endFunc()
+// getAccessibleThread gets the thread after checking the user can access its subthread
+func (s *threadService) getAccessibleThread(ctx context.Context, funcName string, threadID string, userID string) (model.Thread, error) {
 
-	err := s.threadRepo.DecrementLikesCountTx(req.ThreadID, tx)
+	thread, err := s.threadRepo.GetByIDSimple(threadID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeThread] Failed to decrement thread likes count", zap.Error(err))
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Thread not found", zap.Error(err))
+			return thread, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread not found")
+		}
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread likes count")
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get thread by id", zap.Error(err))
+		return thread, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	updateValues := map[string]interface{}{
-		"action":     constants.UNLIKE_ACTION,
-		"updated_by": req.UserEmail,
-		"updated_at": time.Now(),
+	err = s.checkSubThreadAccess(ctx, funcName, thread.SubThreadID, userID)
+	if err != nil {
+		return thread, err
 	}
 
-	err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeThread] Failed to update thread activity", zap.Error(err))
+	return thread, nil
+}
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
+// checkThreadOpen stops new comments and votes on a locked or archived thread
+func (s *threadService) checkThreadOpen(ctx context.Context, funcName string, thread model.Thread) error {
+
+	if thread.IsLocked {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Thread is locked", zap.String("thread_id", thread.ID))
+		return oops.Code(response.ThreadLocked.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("This thread is locked")
 	}
 
-	// TODO: Save to thread activity history
+	if thread.IsArchived {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Thread is archived", zap.String("thread_id", thread.ID))
+		return oops.Code(response.ThreadArchived.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("This thread is archived")
+	}
 
 	return nil
 }
 
-func (s *threadService) DislikeThread(ctx context.Context, req request.DislikeThreadReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.DislikeThread", "service")
+func (s *threadService) GetThreadList(ctx context.Context, req request.GetThreadListReq) (response.GetThreadListResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadList", "service")
 	//defer endFunc()
 
-	var (
-		shouldDoubleDecrementUserReputationPoints bool
-	)
-
-	existingThread, err := s.threadRepo.GetByIDSimple(req.ThreadID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Thread not found", zap.Error(err))
-			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread not found")
-		}
-
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to get thread by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-	}
+	var resp response.GetThreadListResponse
 
-	lastThreadActivity, err := s.getUserLastThreadAction(ctx, req.ThreadID, req.UserID)
+	universityID, canAccessAll, err := getUserUniversityAccess(s.userRepo, s.roleRepo, req.UserID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to get user last thread action", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadList] Failed to get user university access", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	tx, err := s.db.Begin()
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to begin transaction", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-	}
+	req.UserUniversityID = universityID
+	req.CanAccessAllUniversities = canAccessAll
 
-	// Unlike if previously disliked
-	if lastThreadActivity == constants.DISLIKE_ACTION {
-		err = s.unDislikeThread(ctx, req, tx)
+	// The threads of a user are part of their profile, they follow the same visibility settings as the profile page
+	if req.UserIDParam != "" && req.UserIDParam != req.UserID {
+		user, err := s.userRepo.GetByID(req.UserIDParam)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to undislike thread", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to undislike thread")
-		}
+			if errors.Is(err, sql.ErrNoRows) {
+				s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadList] User not found", zap.Error(err))
+				return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+			}
 
-		updateValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadList] Failed to get user", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread list")
 		}
 
-		err = s.userRepo.IncrementUserReputationPointsTx(existingThread.UserID, updateValues, tx)
+		err = checkProfileVisibility(ctx, s.cfg, s.userRepo, user, req.UserID)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to increment thread op reputation points", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread op reputation points")
+			return resp, err
 		}
 
-		err = tx.Commit()
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to commit transaction", zap.Error(err))
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-		}
+		if user.HideThreads {
+			resp.Data = []response.ThreadListData{}
+
+			return resp, nil
+		}
+	}
+
+	// Pinned threads lead the subthread and home lists, they are left out of every page and prepended to the first one
+	isPinnedFirst := req.Search == "" && !req.IsTrending && !req.IsFeed && req.UserIDParam == ""
+
+	var pinnedThreads []model.Thread
+
+	if isPinnedFirst {
+		pinnedThreads, err = s.threadRepo.GetPinnedList(req, constants.THREAD_LIST_MAX_PINNED_THREADS)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadList] Failed to get pinned thread list", zap.Error(err))
+
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread list")
+		}
+
+		for _, t := range pinnedThreads {
+			req.ExcludeThreadIDs = append(req.ExcludeThreadIDs, t.ID)
+		}
+	}
+
+	threads, pagination, err := s.threadRepo.GetList(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadList] Failed to get thread list", zap.Error(err))
+
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread list")
+	}
+
+	if isPinnedFirst && req.Cursor == "" {
+		threads = append(pinnedThreads, threads...)
+	}
+
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	resp.Data = mapThreadListData(threads)
+
+	userIDs := []string{}
+	for _, t := range threads {
+		if !slices.Contains(userIDs, t.UserID) {
+			userIDs = append(userIDs, t.UserID)
+		}
+	}
+
+	badgesByUser, err := s.getUserBadgesByUserIDs(ctx, userIDs)
+	if err != nil {
+		return resp, err
+	}
+
+	for i := range resp.Data {
+		resp.Data[i].UserBadges = badgesByUser[resp.Data[i].UserID]
+	}
+
+	return resp, nil
+}
+
+func mapThreadListData(threads []model.Thread) []response.ThreadListData {
+
+	threadData := []response.ThreadListData{}
+
+	for _, t := range threads {
+
+		tld := response.ThreadListData{
+			ID:             t.ID,
+			UserID:         t.UserID,
+			UserName:       t.User.Username,
+			SubThreadID:    t.SubThreadID,
+			SubThreadName:  t.SubThread.Name,
+			SubThreadColor: t.SubThread.LabelColor,
+			Title:          t.Title,
+			Content:        t.Content,
+			ContentSummary: t.ContentSummary,
+			IsActive:       t.IsActive,
+			IsPinned:       t.IsPinned,
+			IsLocked:       t.IsLocked,
+			IsArchived:     t.IsArchived,
+			IsBookmarked:   t.IsBookmarked,
+			LikeCount:      t.LikeCount,
+			DislikeCount:   t.DislikeCount,
+			CommentCount:   t.CommentCount,
+			CreatedBy:      t.CreatedBy,
+			CreatedAt:      t.CreatedAt,
+			UpdatedBy:      t.UpdatedBy,
+			UpdatedAt:      t.UpdatedAt,
+			EditedAt:       t.EditedAt,
+		}
+
+		if t.User.University != nil {
+			tld.UniversityAbbreviatedName = pkg.ToPointer(t.User.University.AbbreviatedName)
+			tld.UniversityImageURL = pkg.ToPointer(t.User.University.ImageURL)
+		}
+
+		if t.SearchSnippet != "" {
+			tld.SearchSnippet = pkg.ToPointer(t.SearchSnippet)
+		}
+
+		if t.ThreadAction != "" {
+			if t.ThreadAction == constants.LIKE_ACTION {
+				tld.IsLiked = true
+			}
+
+			if t.ThreadAction == constants.DISLIKE_ACTION {
+				tld.IsDisliked = true
+			}
+		}
+
+		threadData = append(threadData, tld)
+	}
+
+	return threadData
+}
+
+func (s *threadService) GetThreadDetail(ctx context.Context, req request.GetThreadDetailReq) (response.GetThreadDetailResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadDetail", "service")
+	//defer endFunc()
+
+	var resp response.GetThreadDetailResponse
+
+	thread, err := s.threadRepo.GetByID(req.ThreadID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Thread does not exist", zap.Error(err))
+
+			return resp, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread does not exist")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get thread detail", zap.Error(err))
+
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread detail")
+	}
+
+	err = s.checkSubThreadAccess(ctx, "GetThreadDetail", thread.SubThreadID, req.UserID)
+	if err != nil {
+		return resp, err
+	}
+
+	if thread.UserID != req.UserID {
+		_, err = s.userBlockRepo.GetByBlockedUserID(req.UserID, thread.UserID)
+		if err == nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Thread author is blocked", zap.String("thread_id", thread.ID))
+			return resp, oops.Code(response.UserBlocked.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("You have blocked or muted the author of this thread")
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get user block", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+	}
+
+	ta, err := s.threadRepo.GetLastThreadActivityByUserID(req.ThreadID, req.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get user last thread activity", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread activity")
+	}
+
+	ts, err := s.threadRepo.GetThreadSubscriptionByUserAndThreadID(req.UserID, req.ThreadID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get user thread subscription", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user thread subscription")
+	}
+
+	resp.Data = s.mapThreadDetailData(thread, ta, ts)
+
+	attachmentsByThread, err := s.getAttachmentsByTargets(ctx, constants.ATTACHMENT_TARGET_TYPE_THREAD, []string{thread.ID})
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Data.Attachments = attachmentsByThread[thread.ID]
+
+	badgesByUser, err := s.getUserBadgesByUserIDs(ctx, []string{thread.UserID})
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Data.UserBadges = badgesByUser[thread.UserID]
+
+	threadPoll, err := s.threadPollRepo.GetByThreadID(req.ThreadID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get thread poll", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread poll")
+	}
+
+	if err == nil {
+		threadPollVotes, err := s.threadPollRepo.GetVotesByPollIDAndUserID(threadPoll.ID, req.UserID)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadDetail] Failed to get user thread poll votes", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user thread poll votes")
+		}
+
+		resp.Data.Poll = s.mapThreadPollData(threadPoll, threadPollVotes)
+	}
+
+	return resp, nil
+}
+
+func (s *threadService) mapThreadPollData(threadPoll model.ThreadPoll, threadPollVotes []model.ThreadPollVote) *response.ThreadPoll {
+
+	votedOptionIDs := map[string]bool{}
+	for _, v := range threadPollVotes {
+		votedOptionIDs[v.OptionID] = true
+	}
+
+	tp := &response.ThreadPoll{
+		ID:               threadPoll.ID,
+		Question:         threadPoll.Question,
+		IsMultipleChoice: threadPoll.IsMultipleChoice,
+		ClosesAt:         threadPoll.ClosesAt,
+		IsClosed:         threadPoll.IsClosed(),
+		VoterCount:       threadPoll.VoterCount,
+		HasVoted:         len(threadPollVotes) > 0,
+		Options:          []response.ThreadPollOption{},
+	}
+
+	for _, o := range threadPoll.Options {
+		tp.Options = append(tp.Options, response.ThreadPollOption{
+			ID:        o.ID,
+			Content:   o.Content,
+			VoteCount: o.VoteCount,
+			IsVoted:   votedOptionIDs[o.ID],
+		})
+	}
+
+	return tp
+}
+
+func (s *threadService) mapThreadDetailData(thread model.Thread, threadActivity *model.ThreadActivity, threadSubscription model.ThreadSubscription) response.ThreadDetailData {
+
+	td := response.ThreadDetailData{
+		ID:             thread.ID,
+		UserID:         thread.UserID,
+		UserName:       thread.User.Username,
+		SubThreadID:    thread.SubThreadID,
+		SubThreadName:  thread.SubThread.Name,
+		SubThreadColor: thread.SubThread.LabelColor,
+		Title:          thread.Title,
+		Content:        thread.Content,
+		ContentSummary: thread.ContentSummary,
+		IsActive:       thread.IsActive,
+		IsPinned:       thread.IsPinned,
+		IsLocked:       thread.IsLocked,
+		IsArchived:     thread.IsArchived,
+		LikeCount:      thread.LikeCount,
+		DislikeCount:   thread.DislikeCount,
+		CommentCount:   thread.CommentCount,
+		CreatedBy:      thread.CreatedBy,
+		CreatedAt:      thread.CreatedAt,
+		UpdatedBy:      thread.UpdatedBy,
+		UpdatedAt:      thread.UpdatedAt,
+		EditedAt:       thread.EditedAt,
+	}
+
+	if thread.User.University != nil {
+		td.UniversityAbbreviatedName = pkg.ToPointer(thread.User.University.AbbreviatedName)
+		td.UniversityImageURL = pkg.ToPointer(thread.User.University.ImageURL)
+	}
+
+	if threadActivity != nil {
+		if threadActivity.Action == constants.LIKE_ACTION {
+			td.IsLiked = true
+		}
+
+		if threadActivity.Action == constants.DISLIKE_ACTION {
+			td.IsDisliked = true
+		}
+	}
+
+	if threadSubscription.ID != "" && threadSubscription.IsSubscribed {
+		td.IsSubscribed = true
+	}
+
+	return td
+}
+
+func (s *threadService) LikeThread(ctx context.Context, req request.LikeThreadReq) (err error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.LikeThread", "service")
+	//defer endFunc()
+
+	defer func() {
+		if err == nil {
+			s.publishThreadVoteCount(ctx, req.ThreadID)
+		}
+	}()
+
+	existingThread, err := s.threadRepo.GetByIDSimple(req.ThreadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Thread not found", zap.Error(err))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to get thread by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.checkSubThreadAccess(ctx, "LikeThread", existingThread.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkThreadOpen(ctx, "LikeThread", existingThread)
+	if err != nil {
+		return err
+	}
+
+	lastThreadActivity, err := s.getUserLastThreadAction(ctx, req.ThreadID, req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to get user last thread action", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// Unlike if previously liked
+	if lastThreadActivity == constants.LIKE_ACTION {
+		err = s.unlikeThread(ctx, req, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to unlike thread", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unlike thread")
+		}
+
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingThread.UserID, constants.REPUTATION_SOURCE_THREAD_VOTE, existingThread.ID, req.UserID, 0, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to update thread op reputation points", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread op reputation points")
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to commit transaction", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		return nil
+	}
+
+	// Decrement dislike count if previously disliked
+	if lastThreadActivity == constants.DISLIKE_ACTION {
+		err = s.threadRepo.DecrementDislikesCountTx(req.ThreadID, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to decrement thread dislikes count", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread dislikes count")
+		}
+	}
+
+	err = s.threadRepo.IncrementLikesCountTx(req.ThreadID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to increment thread likes count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread likes count")
+	}
+
+	if isLikeMilestone(existingThread.LikeCount+1) && existingThread.UserID != req.UserID {
+		notification := newNotification(
+			existingThread.UserID,
+			"",
+			constants.THREAD_LIKE_MILESTONE_EVENT,
+			fmt.Sprintf("Your thread reached %d likes!", existingThread.LikeCount+1),
+			pkg.TruncateWithEllipsis(existingThread.Title, 50),
+			"/thread/"+existingThread.ID,
+			fmt.Sprintf("%s_%s_%d", constants.THREAD_LIKE_MILESTONE_EVENT, existingThread.ID, existingThread.LikeCount+1),
+			req.UserEmail,
+		)
+
+		err = s.notificationRepo.BulkSaveTx([]model.Notification{notification}, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to save like milestone notification", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save like milestone notification")
+		}
+	}
+
+	if existingThread.UserID != req.UserID {
+		err = evaluateLikesReceivedBadgeTx(s.badgeRepo, s.notificationRepo, existingThread.UserID, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to evaluate likes received badge", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to evaluate likes received badge")
+		}
+	}
+
+	if lastThreadActivity != "" {
+
+		updateValues := map[string]interface{}{
+			"action":     constants.LIKE_ACTION,
+			"updated_by": req.UserEmail,
+			"updated_at": time.Now(),
+		}
+
+		err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to update thread activity", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
+		}
+
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingThread.UserID, constants.REPUTATION_SOURCE_THREAD_VOTE, existingThread.ID, req.UserID, constants.DEFAULT_INCREMENT_REPUTATION, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to update thread op reputation points", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread op reputation points")
+		}
+
+		// TODO: Save to thread activity history
+
+		err = tx.Commit()
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to commit transaction", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		return nil
+	}
+
+	threadActivity := &model.ThreadActivity{
+		ID:            uuid.NewString(),
+		ThreadID:      req.ThreadID,
+		ActorID:       req.UserID,
+		ActorEmail:    req.UserEmail,
+		ActorUsername: req.Username,
+		Action:        constants.LIKE_ACTION,
+		CreatedBy:     req.UserEmail,
+	}
+
+	err = s.threadRepo.SaveThreadActivityTx(threadActivity, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to save thread activity", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread activity")
+	}
+
+	err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingThread.UserID, constants.REPUTATION_SOURCE_THREAD_VOTE, existingThread.ID, req.UserID, constants.DEFAULT_INCREMENT_REPUTATION, req.UserEmail, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to update thread op reputation points", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread op reputation points")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeThread] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+func (s *threadService) getUserLastThreadAction(ctx context.Context, threadId string, userId string) (string, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.getUserLastThreadAction", "service")
+	//defer endFunc()
+
+	ta, err := s.threadRepo.GetLastThreadActivityByUserID(threadId, userId)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getUserLastThreadAction] Failed to get user last thread activity", zap.Error(err))
+		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread activity")
+	}
+
+	if ta != nil {
+
+		return ta.Action, nil
+	}
+
+	return "", nil
+}
+
+func (s *threadService) unlikeThread(ctx context.Context, req request.LikeThreadReq, tx bun.Tx) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.unlikeThread", "service")
+	//defer endFunc()
+
+	err := s.threadRepo.DecrementLikesCountTx(req.ThreadID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeThread] Failed to decrement thread likes count", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread likes count")
+	}
+
+	updateValues := map[string]interface{}{
+		"action":     constants.UNLIKE_ACTION,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeThread] Failed to update thread activity", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
+	}
+
+	// TODO: Save to thread activity history
+
+	return nil
+}
+
+func (s *threadService) DislikeThread(ctx context.Context, req request.DislikeThreadReq) (err error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.DislikeThread", "service")
+	//defer endFunc()
+
+	defer func() {
+		if err == nil {
+			s.publishThreadVoteCount(ctx, req.ThreadID)
+		}
+	}()
+
+	existingThread, err := s.threadRepo.GetByIDSimple(req.ThreadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Thread not found", zap.Error(err))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to get thread by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.checkSubThreadAccess(ctx, "DislikeThread", existingThread.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkThreadOpen(ctx, "DislikeThread", existingThread)
+	if err != nil {
+		return err
+	}
+
+	lastThreadActivity, err := s.getUserLastThreadAction(ctx, req.ThreadID, req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to get user last thread action", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// Unlike if previously disliked
+	if lastThreadActivity == constants.DISLIKE_ACTION {
+		err = s.unDislikeThread(ctx, req, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to undislike thread", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to undislike thread")
+		}
+
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingThread.UserID, constants.REPUTATION_SOURCE_THREAD_VOTE, existingThread.ID, req.UserID, 0, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to update thread op reputation points", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread op reputation points")
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to commit transaction", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		return nil
+	}
+
+	// Decrement like count if previously liked
+	if lastThreadActivity == constants.LIKE_ACTION {
+		err = s.threadRepo.DecrementLikesCountTx(req.ThreadID, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to decrement thread likes count", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread likes count")
+		}
+	}
+
+	err = s.threadRepo.IncrementDislikesCountTx(req.ThreadID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to increment thread dislikes count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread dislikes count")
+	}
+
+	if lastThreadActivity != "" {
+
+		updateValues := map[string]interface{}{
+			"action":     constants.DISLIKE_ACTION,
+			"updated_by": req.UserEmail,
+			"updated_at": time.Now(),
+		}
+
+		err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to update thread activity", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
+		}
+
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingThread.UserID, constants.REPUTATION_SOURCE_THREAD_VOTE, existingThread.ID, req.UserID, -constants.DEFAULT_DECREMENT_REPUTATION, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to update thread op reputation points", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread op reputation points")
+		}
+
+		// TODO: Save to thread activity history
+
+		err = tx.Commit()
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to commit transaction", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		return nil
+	}
+
+	threadActivity := &model.ThreadActivity{
+		ID:            uuid.NewString(),
+		ThreadID:      req.ThreadID,
+		ActorID:       req.UserID,
+		ActorEmail:    req.UserEmail,
+		ActorUsername: req.Username,
+		Action:        constants.DISLIKE_ACTION,
+		CreatedBy:     req.UserEmail,
+	}
+
+	err = s.threadRepo.SaveThreadActivityTx(threadActivity, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to save thread activity", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread activity")
+	}
+
+	err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingThread.UserID, constants.REPUTATION_SOURCE_THREAD_VOTE, existingThread.ID, req.UserID, -constants.DEFAULT_DECREMENT_REPUTATION, req.UserEmail, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to update thread op reputation points", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread op reputation points")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+func (s *threadService) unDislikeThread(ctx context.Context, req request.DislikeThreadReq, tx bun.Tx) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.unDislikeThread", "service")
+	//defer endFunc()
+
+	err := s.threadRepo.DecrementDislikesCountTx(req.ThreadID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeThread] Failed to decrement thread dislikes count", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread dislikes count")
+	}
+
+	updateValues := map[string]interface{}{
+		"action":     constants.UNDISLIKE_ACTION,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeThread] Failed to update thread activity", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
+	}
+
+	// TODO: Save to thread activity history
+
+	return nil
+}
+
+func (s *threadService) CommentThread(ctx context.Context, req request.CommentThreadReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.CommentThread", "service")
+	//defer endFunc()
+
+	thread, err := s.threadRepo.GetByID(req.ThreadID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Thread does not exist", zap.Error(err))
+
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread does not exist")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to get thread detail", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread detail")
+	}
+
+	err = s.checkSubThreadAccess(ctx, "CommentThread", thread.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkThreadOpen(ctx, "CommentThread", thread)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkSubThreadBan(ctx, "CommentThread", thread.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkNotBlockedBy(ctx, "CommentThread", thread.UserID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	threadSubscribers, err := s.threadRepo.GetThreadSubscribers(req.ThreadID)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to get thread subscribers", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread subscribers")
+	}
+
+	threadSubscribers, isOwnerBlocking, err := s.removeBlockingRecipients(ctx, "CommentThread", req.UserID, thread.UserID, threadSubscribers)
+	if err != nil {
+		return err
+	}
+
+	isOwnerNotified := thread.UserID != req.UserID && !isOwnerBlocking
+
+	// Owners without a registered device still get the comment in their notification inbox
+	userDevices, err := s.userRepo.GetUserDevices(request.GetUserDevicesReq{
+		UserID: thread.UserID,
+	})
+
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to get user devices", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user devices")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.threadRepo.IncrementCommentsCountTx(req.ThreadID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to increment thread comments count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comments count")
+	}
+
+	threadComment := &model.ThreadComment{
+		ID:        uuid.NewString(),
+		ThreadID:  req.ThreadID,
+		UserID:    req.UserID,
+		Content:   req.Content,
+		CreatedBy: req.UserEmail,
+	}
+
+	err = s.threadRepo.SaveThreadCommentTx(threadComment, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to save thread comment", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment")
+	}
+
+	err = s.attachTx(ctx, req.AttachmentIDs, req.UserID, constants.ATTACHMENT_TARGET_TYPE_THREAD_COMMENT, threadComment.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	notifications := []model.Notification{}
+	notificationContent := fmt.Sprintf("%s: %s", req.Username, pkg.TruncateWithEllipsis(req.Content, 50))
+
+	if isOwnerNotified {
+		notifications = append(notifications, newNotification(
+			thread.UserID,
+			req.UserID,
+			constants.COMMENT_ON_THREAD_EVENT,
+			"Someone commented on your thread!",
+			notificationContent,
+			"/thread/"+thread.ID,
+			"",
+			req.UserEmail,
+		))
+	}
+
+	notifications = append(notifications, s.buildSubscribedThreadNotifications(threadSubscribers, thread.ID, notificationContent, req.UserEmail, req.UserID, thread.UserID)...)
+
+	if len(notifications) > 0 {
+		err = s.notificationRepo.BulkSaveTx(notifications, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to save notifications", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save notifications")
+		}
+	}
+
+	pushNotifications := []notifsvc.SendPushNotificationReq{}
+
+	if isOwnerNotified {
+		var notificationTokens = []string{}
+
+		for _, ud := range userDevices {
+			if ud.IsNotificationActive {
+				notificationTokens = append(notificationTokens, ud.NotificationToken)
+			}
+		}
+
+		if len(notificationTokens) > 0 {
+			pushNotifications = append(pushNotifications, notifsvc.SendPushNotificationReq{
+				NotificationTokens: notificationTokens,
+				Title:              "Someone commented on your thread!",
+				Content:            notificationContent,
+				Data: map[string]string{
+					constants.APP_ROUTE_KEY:  "/thread/" + thread.ID,
+					constants.EVENT_TYPE_KEY: constants.COMMENT_ON_THREAD_EVENT,
+				},
+			})
+		}
+	}
+
+	if len(threadSubscribers) > 0 {
+		var subscriberNotificationTokens = []string{}
+
+		for _, ts := range threadSubscribers {
+			if ts.UserID == req.UserID {
+				continue
+			}
+
+			if ts.User.Devices != nil && len(ts.User.Devices) > 0 {
+				for _, tsud := range ts.User.Devices {
+					if tsud.IsNotificationActive {
+						subscriberNotificationTokens = append(subscriberNotificationTokens, tsud.NotificationToken)
+					}
+				}
+			}
+		}
+
+		if len(subscriberNotificationTokens) > 0 {
+			pushNotifications = append(pushNotifications, notifsvc.SendPushNotificationReq{
+				NotificationTokens: subscriberNotificationTokens,
+				Title:              "A New Comment on Your Subscribed Thread!",
+				Content:            notificationContent,
+				Data: map[string]string{
+					constants.APP_ROUTE_KEY:  "/thread/" + thread.ID,
+					constants.EVENT_TYPE_KEY: constants.COMMENT_ON_SUBSCRIBED_THREAD_EVENT,
+				},
+			})
+		}
+	}
+
+	// Push notifications are delivered by the outbox dispatcher once the comment is committed
+	for _, pn := range pushNotifications {
+		outboxMessage, err := newOutboxMessage(constants.OUTBOX_TYPE_PUSH_NOTIFICATION, pn, req.UserEmail)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to build push notification outbox message", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		err = s.outboxRepo.SaveTx(outboxMessage, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to save push notification outbox message", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save push notification outbox message")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	threadComment.User = model.User{Username: req.Username}
+	threadCommentData := s.mapThreadCommentsData([]model.ThreadComment{*threadComment})[0]
+
+	attachmentsByComment, err := s.getAttachmentsByTargets(ctx, constants.ATTACHMENT_TARGET_TYPE_THREAD_COMMENT, []string{threadComment.ID})
+	if err == nil {
+		threadCommentData.Attachments = attachmentsByComment[threadComment.ID]
+	}
+
+	s.realtimeHub.Publish(ctx, realtime.Event{
+		Type:     constants.THREAD_EVENT_COMMENT_CREATED,
+		ThreadID: req.ThreadID,
+		Data: realtime.CommentCreatedData{
+			TargetType: constants.THREAD_EVENT_TARGET_THREAD_COMMENT,
+			Comment:    threadCommentData,
+		},
+	})
+
+	return nil
+}
+
+func (s *threadService) ReplyComment(ctx context.Context, req request.ReplyCommentReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.ReplyComment", "service")
+	//defer endFunc()
+
+	threadComment, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Thread comment does not exist", zap.Error(err))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread comment does not exist")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to get thread comment by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// Every later step works on req.ThreadID, it must be the thread the access, lock and ban checks run against
+	if threadComment.ThreadID != req.ThreadID {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Thread comment does not belong to the thread", zap.String("comment_id", req.CommentID), zap.String("thread_id", req.ThreadID))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread comment does not belong to the thread")
+	}
+
+	thread, err := s.threadRepo.GetByIDSimple(threadComment.ThreadID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to get thread by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.checkSubThreadAccess(ctx, "ReplyComment", thread.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkThreadOpen(ctx, "ReplyComment", thread)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkSubThreadBan(ctx, "ReplyComment", thread.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	// The author being replied to, the comment author unless this is a reply to a reply
+	repliedToUserID := threadComment.UserID
+
+	if req.ParentReplyID != nil {
+		parentReply, err := s.threadRepo.GetThreadCommentReplyByID(*req.ParentReplyID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Parent reply does not exist", zap.Error(err))
+				return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Parent reply does not exist")
+			}
+
+			s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to get parent reply by id", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		if parentReply.ThreadCommentID != req.CommentID {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Parent reply does not belong to the comment", zap.String("parent_reply_id", parentReply.ID))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Parent reply does not belong to the comment")
+		}
+
+		repliedToUserID = parentReply.UserID
+	}
+
+	err = s.checkNotBlockedBy(ctx, "ReplyComment", repliedToUserID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	threadSubscribers, err := s.threadRepo.GetThreadSubscribers(req.ThreadID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to get thread subscribers", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread subscribers")
+	}
+
+	threadSubscribers, isRepliedToBlocking, err := s.removeBlockingRecipients(ctx, "ReplyComment", req.UserID, repliedToUserID, threadSubscribers)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	err = s.threadRepo.IncrementCommentReplyCountTx(req.CommentID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to increment comment reply count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment reply count")
+	}
+
+	err = s.threadRepo.IncrementCommentsCountTx(req.ThreadID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to increment thread comments count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comments count")
+	}
+
+	if req.ParentReplyID != nil {
+		err = s.threadRepo.IncrementCommentReplyReplyCountTx(*req.ParentReplyID, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to increment parent reply reply count", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment parent reply reply count")
+		}
+	}
+
+	threadCommentReply := &model.ThreadCommentReply{
+		ID:              uuid.NewString(),
+		ThreadID:        req.ThreadID,
+		UserID:          req.UserID,
+		ThreadCommentID: req.CommentID,
+		ParentReplyID:   req.ParentReplyID,
+		Content:         req.Content,
+		CreatedBy:       req.UserEmail,
+	}
+
+	err = s.threadRepo.SaveCommentReplyTx(threadCommentReply, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to save thread comment reply", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment reply")
+	}
+
+	err = s.attachTx(ctx, req.AttachmentIDs, req.UserID, constants.ATTACHMENT_TARGET_TYPE_THREAD_COMMENT_REPLY, threadCommentReply.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	notifications := []model.Notification{}
+	notificationContent := fmt.Sprintf("%s: %s", req.Username, pkg.TruncateWithEllipsis(req.Content, 50))
+
+	if repliedToUserID != req.UserID && !isRepliedToBlocking {
+		notifications = append(notifications, newNotification(
+			repliedToUserID,
+			req.UserID,
+			constants.REPLY_ON_COMMENT_EVENT,
+			"Someone replied to your comment!",
+			notificationContent,
+			"/thread/"+req.ThreadID,
+			"",
+			req.UserEmail,
+		))
+	}
+
+	notifications = append(notifications, s.buildSubscribedThreadNotifications(threadSubscribers, req.ThreadID, notificationContent, req.UserEmail, req.UserID, repliedToUserID)...)
+
+	if len(notifications) > 0 {
+		err = s.notificationRepo.BulkSaveTx(notifications, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to save notifications", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save notifications")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	threadCommentReply.User = model.User{Username: req.Username}
+	threadCommentReplyData := s.mapThreadCommentRepliesData([]model.ThreadCommentReply{*threadCommentReply})[0]
+
+	attachmentsByReply, err := s.getAttachmentsByTargets(ctx, constants.ATTACHMENT_TARGET_TYPE_THREAD_COMMENT_REPLY, []string{threadCommentReply.ID})
+	if err == nil {
+		threadCommentReplyData.Attachments = attachmentsByReply[threadCommentReply.ID]
+	}
+
+	s.realtimeHub.Publish(ctx, realtime.Event{
+		Type:     constants.THREAD_EVENT_COMMENT_CREATED,
+		ThreadID: req.ThreadID,
+		Data: realtime.CommentCreatedData{
+			TargetType: constants.THREAD_EVENT_TARGET_THREAD_COMMENT_REPLY,
+			Comment:    threadCommentReplyData,
+		},
+	})
+
+	return nil
+}
+
+// buildSubscribedThreadNotifications notifies every subscriber of the thread except the actor and
+// the users in excludeUserIDs, who already receive a more specific notification for the same event
+func (s *threadService) buildSubscribedThreadNotifications(threadSubscribers []model.ThreadSubscription, threadID string, content string, actorEmail string, actorID string, excludeUserIDs ...string) []model.Notification {
+
+	notifications := []model.Notification{}
+
+	for _, ts := range threadSubscribers {
+		if ts.UserID == actorID || slices.Contains(excludeUserIDs, ts.UserID) {
+			continue
+		}
+
+		notifications = append(notifications, newNotification(
+			ts.UserID,
+			actorID,
+			constants.COMMENT_ON_SUBSCRIBED_THREAD_EVENT,
+			"A New Comment on Your Subscribed Thread!",
+			content,
+			"/thread/"+threadID,
+			"",
+			actorEmail,
+		))
+	}
+
+	return notifications
+}
+
+func isLikeMilestone(likeCount int64) bool {
+	return slices.Contains(likeMilestones, likeCount)
+}
+
+func (s *threadService) LikeComment(ctx context.Context, req request.LikeCommentReq) (err error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.LikeComment", "service")
+	//defer endFunc()
+
+	defer func() {
+		if err == nil {
+			s.publishCommentVoteCount(ctx, req.CommentID, req.IsReply)
+		}
+	}()
+
+	// Handle is comment reply
+	if req.IsReply {
+		err := s.likeCommentReply(ctx, req)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to like comment reply", zap.Error(err))
+			return err
+		}
 
 		return nil
 	}
 
-	// Decrement like count if previously liked
-	if lastThreadActivity == constants.LIKE_ACTION {
-		err = s.threadRepo.DecrementLikesCountTx(req.ThreadID, tx)
+	existingComment, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Thread comment not found", zap.Error(err))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread comment not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to get thread comment by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	parentThread, err := s.getAccessibleThread(ctx, "LikeComment", existingComment.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkThreadOpen(ctx, "LikeComment", parentThread)
+	if err != nil {
+		return err
+	}
+
+	lastThreadCommentActivity, err := s.getUserLastThreadCommentAction(ctx, req.ThreadID, req.CommentID, req.UserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to get user last thread comment action", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	// Unlike if previously liked
+	if lastThreadCommentActivity == constants.LIKE_ACTION {
+		err = s.unlikeComment(ctx, req, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to decrement thread likes count", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to unlike comment", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread likes count")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unlike comment")
+		}
+
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingComment.UserID, constants.REPUTATION_SOURCE_COMMENT_VOTE, existingComment.ID, req.UserID, 0, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to update comment op reputation points", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment op reputation points")
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to commit transaction", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
-		shouldDoubleDecrementUserReputationPoints = true
+		return nil
 	}
 
-	err = s.threadRepo.IncrementDislikesCountTx(req.ThreadID, tx)
+	// Decrement dislike count if previously disliked
+	if lastThreadCommentActivity == constants.DISLIKE_ACTION {
+		err = s.threadRepo.DecrementCommentDislikesCountTx(req.CommentID, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to decrement comment dislikes count", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment dislikes count")
+		}
+	}
+
+	err = s.threadRepo.IncrementCommentLikesCountTx(req.CommentID, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to increment thread dislikes count", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to increment comment likes count", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread dislikes count")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment likes count")
 	}
 
-	if lastThreadActivity != "" {
+	if isLikeMilestone(existingComment.LikeCount+1) && existingComment.UserID != req.UserID {
+		notification := newNotification(
+			existingComment.UserID,
+			"",
+			constants.COMMENT_LIKE_MILESTONE_EVENT,
+			fmt.Sprintf("Your comment reached %d likes!", existingComment.LikeCount+1),
+			pkg.TruncateWithEllipsis(existingComment.Content, 50),
+			"/thread/"+existingComment.ThreadID,
+			fmt.Sprintf("%s_%s_%d", constants.COMMENT_LIKE_MILESTONE_EVENT, existingComment.ID, existingComment.LikeCount+1),
+			req.UserEmail,
+		)
 
-		updateValues := map[string]interface{}{
-			"action":     constants.DISLIKE_ACTION,
-			"updated_by": req.UserEmail,
-			"updated_at": time.Now(),
+		err = s.notificationRepo.BulkSaveTx([]model.Notification{notification}, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to save like milestone notification", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save like milestone notification")
 		}
+	}
 
-		err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
+	if existingComment.UserID != req.UserID {
+		err = evaluateLikesReceivedBadgeTx(s.badgeRepo, s.notificationRepo, existingComment.UserID, req.UserEmail, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to update thread activity", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to evaluate likes received badge", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to evaluate likes received badge")
 		}
+	}
+
+	if lastThreadCommentActivity != "" {
 
-		updateUserValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
+		updateValues := map[string]interface{}{
+			"action":     constants.LIKE_ACTION,
+			"updated_by": req.UserEmail,
+			"updated_at": time.Now(),
 		}
 
-		if shouldDoubleDecrementUserReputationPoints {
-			updateUserValues["reputation_points"] = constants.DEFAULT_DECREMENT_REPUTATION * 2
+		err = s.threadRepo.UpdateThreadCommentActivityTx(req.CommentID, req.UserID, updateValues, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to update thread comment activity", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment activity")
 		}
 
-		err = s.userRepo.DecrementUserReputationPointsTx(existingThread.UserID, updateUserValues, tx)
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingComment.UserID, constants.REPUTATION_SOURCE_COMMENT_VOTE, existingComment.ID, req.UserID, constants.DEFAULT_INCREMENT_REPUTATION, req.UserEmail, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to decrement thread op reputation points", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to update comment op reputation points", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread op reputation points")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment op reputation points")
 		}
 
 		// TODO: Save to thread activity history
 
 		err = tx.Commit()
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to commit transaction", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to commit transaction", zap.Error(err))
 			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
 		return nil
 	}
 
-	threadActivity := &model.ThreadActivity{
-		ID:            uuid.NewString(),
-		ThreadID:      req.ThreadID,
-		ActorID:       req.UserID,
-		ActorEmail:    req.UserEmail,
-		ActorUsername: req.Username,
-		Action:        constants.DISLIKE_ACTION,
-		CreatedBy:     req.UserEmail,
+	threadActivity := &model.ThreadCommentActivity{
+		ID:              uuid.NewString(),
+		ThreadID:        req.ThreadID,
+		ThreadCommentID: req.CommentID,
+		ActorID:         req.UserID,
+		ActorEmail:      req.UserEmail,
+		ActorUsername:   req.Username,
+		Action:          constants.LIKE_ACTION,
+		CreatedBy:       req.UserEmail,
 	}
 
-	err = s.threadRepo.SaveThreadActivityTx(threadActivity, tx)
+	err = s.threadRepo.SaveThreadCommentActivityTx(threadActivity, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to save thread activity", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread activity")
-	}
-
-	updateValues := map[string]interface{}{
-		"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-		"updated_by":        req.UserEmail,
-	}
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to save thread comment activity", zap.Error(err))
 
-	if shouldDoubleDecrementUserReputationPoints {
-		updateValues["reputation_points"] = constants.DEFAULT_DECREMENT_REPUTATION * 2
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment activity")
 	}
 
-	err = s.userRepo.DecrementUserReputationPointsTx(existingThread.UserID, updateValues, tx)
+	err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingComment.UserID, constants.REPUTATION_SOURCE_COMMENT_VOTE, existingComment.ID, req.UserID, constants.DEFAULT_INCREMENT_REPUTATION, req.UserEmail, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to decrement thread op reputation points", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to update comment op reputation points", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread op reputation points")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment op reputation points")
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeThread] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
 	return nil
 }
 
-func (s *threadService) unDislikeThread(ctx context.Context, req request.DislikeThreadReq, tx bun.Tx) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.unDislikeThread", "service")
+func (s *threadService) likeCommentReply(ctx context.Context, req request.LikeCommentReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.likeCommentReply", "service")
 	//defer endFunc()
 
-	err := s.threadRepo.DecrementDislikesCountTx(req.ThreadID, tx)
+	lastThreadCommentReplyActivity, err := s.getUserLastThreadCommentReplyAction(ctx, req.ThreadID, req.CommentID, req.UserID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeThread] Failed to decrement thread dislikes count", zap.Error(err))
-
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread dislikes count")
+		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to get user last thread comment reply action", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	updateValues := map[string]interface{}{
-		"action":     constants.UNDISLIKE_ACTION,
-		"updated_by": req.UserEmail,
-		"updated_at": time.Now(),
+	threadCommentReply, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Thread comment reply does not exist", zap.Error(err))
+
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply does not exist")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to get thread comment reply by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread comment reply by id")
 	}
 
-	err = s.threadRepo.UpdateThreadActivityTx(req.ThreadID, req.UserID, updateValues, tx)
+	parentThread, err := s.getAccessibleThread(ctx, "likeCommentReply", threadCommentReply.ThreadID, req.UserID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeThread] Failed to update thread activity", zap.Error(err))
+		return err
+	}
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread activity")
+	err = s.checkThreadOpen(ctx, "likeCommentReply", parentThread)
+	if err != nil {
+		return err
 	}
 
-	// TODO: Save to thread activity history
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
 
-	return nil
-}
+	// Unlike if previously liked
+	if lastThreadCommentReplyActivity == constants.LIKE_ACTION {
+		err = s.unlikeCommentReply(ctx, req, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to unlike comment reply", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unlike comment reply")
+		}
 
-func (s *threadService) CommentThread(ctx context.Context, req request.CommentThreadReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.CommentThread", "service")
-	//defer endFunc()
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, threadCommentReply.UserID, constants.REPUTATION_SOURCE_COMMENT_REPLY_VOTE, threadCommentReply.ID, req.UserID, 0, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to update comment reply op reputation points", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment reply op reputation points")
+		}
 
-	thread, err := s.threadRepo.GetByID(req.ThreadID)
+		err = tx.Commit()
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to commit transaction", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
 
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Thread does not exist", zap.Error(err))
+		return nil
+	}
 
-			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread does not exist")
+	// Decrement dislike count if previously disliked
+	if lastThreadCommentReplyActivity == constants.DISLIKE_ACTION {
+		err = s.threadRepo.DecrementCommentReplyDislikesCountTx(req.CommentID, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to decrement comment reply dislikes count", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment reply dislikes count")
 		}
+	}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to get thread detail", zap.Error(err))
-
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread detail")
+	err = s.threadRepo.IncrementCommentReplyLikesCountTx(req.CommentID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to increment comment reply likes count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment reply likes count")
 	}
 
-	threadSubscribers, err := s.threadRepo.GetThreadSubscribers(req.ThreadID)
+	if isLikeMilestone(threadCommentReply.LikeCount+1) && threadCommentReply.UserID != req.UserID {
+		notification := newNotification(
+			threadCommentReply.UserID,
+			"",
+			constants.COMMENT_LIKE_MILESTONE_EVENT,
+			fmt.Sprintf("Your comment reached %d likes!", threadCommentReply.LikeCount+1),
+			pkg.TruncateWithEllipsis(threadCommentReply.Content, 50),
+			"/thread/"+threadCommentReply.ThreadID,
+			fmt.Sprintf("%s_%s_%d", constants.COMMENT_LIKE_MILESTONE_EVENT, threadCommentReply.ID, threadCommentReply.LikeCount+1),
+			req.UserEmail,
+		)
 
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to get thread subscribers", zap.Error(err))
+		err = s.notificationRepo.BulkSaveTx([]model.Notification{notification}, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to save like milestone notification", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save like milestone notification")
+		}
+	}
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread subscribers")
+	if threadCommentReply.UserID != req.UserID {
+		err = evaluateLikesReceivedBadgeTx(s.badgeRepo, s.notificationRepo, threadCommentReply.UserID, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to evaluate likes received badge", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to evaluate likes received badge")
+		}
 	}
 
-	userDevices, err := s.userRepo.GetUserDevices(request.GetUserDevicesReq{
-		UserID: thread.UserID,
-	})
+	if lastThreadCommentReplyActivity != "" {
 
-	if len(userDevices) == 0 {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] User devices not found", zap.Error(err))
-		return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User devices not found")
-	}
+		updateValues := map[string]interface{}{
+			"action":     constants.LIKE_ACTION,
+			"updated_by": req.UserEmail,
+			"updated_at": time.Now(),
+		}
 
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to get user devices", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user devices")
-	}
+		err = s.threadRepo.UpdateThreadCommentActivityReplyTx(req.CommentID, req.UserID, updateValues, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to update thread comment reply activity", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply activity")
+		}
 
-	tx, err := s.db.Begin()
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to begin transaction", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-	}
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, threadCommentReply.UserID, constants.REPUTATION_SOURCE_COMMENT_REPLY_VOTE, threadCommentReply.ID, req.UserID, constants.DEFAULT_INCREMENT_REPUTATION, req.UserEmail, tx)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to update comment reply op reputation points", zap.Error(err))
+			tx.Rollback()
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment reply op reputation points")
+		}
 
-	err = s.threadRepo.IncrementCommentsCountTx(req.ThreadID, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to increment thread comments count", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comments count")
+		// TODO: Save to thread activity history
+
+		err = tx.Commit()
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to commit transaction", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		}
+
+		return nil
 	}
 
-	threadComment := &model.ThreadComment{
-		ID:        uuid.NewString(),
-		ThreadID:  req.ThreadID,
-		UserID:    req.UserID,
-		Content:   req.Content,
-		CreatedBy: req.UserEmail,
+	threadActivity := &model.ThreadCommentActivity{
+		ID:                   uuid.NewString(),
+		ThreadID:             req.ThreadID,
+		ThreadCommentID:      threadCommentReply.ThreadCommentID,
+		ThreadCommentReplyID: pkg.ToPointer(req.CommentID),
+		ActorID:              req.UserID,
+		ActorEmail:           req.UserEmail,
+		ActorUsername:        req.Username,
+		Action:               constants.LIKE_ACTION,
+		CreatedBy:            req.UserEmail,
 	}
 
-	err = s.threadRepo.SaveThreadCommentTx(threadComment, tx)
+	err = s.threadRepo.SaveThreadCommentActivityTx(threadActivity, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to save thread comment", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to save thread comment reply activity", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment reply activity")
+	}
+
+	err = applyVoteReputationTx(s.reputationRepo, s.userRepo, threadCommentReply.UserID, constants.REPUTATION_SOURCE_COMMENT_REPLY_VOTE, threadCommentReply.ID, req.UserID, constants.DEFAULT_INCREMENT_REPUTATION, req.UserEmail, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to update comment reply op reputation points", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment reply op reputation points")
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	if thread.UserID != req.UserID {
-		var notificationTokens = []string{}
+	// TODO: Save to thread comment activity history
 
-		for _, ud := range userDevices {
-			if ud.IsNotificationActive {
-				notificationTokens = append(notificationTokens, ud.NotificationToken)
-			}
-		}
+	return nil
+}
 
-		if len(notificationTokens) > 0 {
+func (s *threadService) unlikeComment(ctx context.Context, req request.LikeCommentReq, tx bun.Tx) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.unlikeComment", "service")
+	//defer endFunc()
 
-			notifData := map[string]string{
-				constants.APP_ROUTE_KEY:  "/thread/" + thread.ID,
-				constants.EVENT_TYPE_KEY: constants.COMMENT_ON_THREAD_EVENT,
-			}
+	err := s.threadRepo.DecrementCommentLikesCountTx(req.CommentID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeComment] Failed to decrement thread comment likes count", zap.Error(err))
 
-			_, err = s.notifCl.SendPushNotification(ctx, notifsvc.SendPushNotificationReq{
-				NotificationTokens: notificationTokens,
-				Title:              "Someone commented on your thread!",
-				Content:            fmt.Sprintf("%s: %s", req.Username, pkg.TruncateWithEllipsis(req.Content, 50)),
-				Data:               notifData,
-			})
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment likes count")
+	}
 
-			if err != nil {
-				s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to send push notification", zap.Error(err))
-			}
-		}
+	updateValues := map[string]interface{}{
+		"action":     constants.UNLIKE_ACTION,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
 	}
 
-	if len(threadSubscribers) > 0 {
-		var subscriberNotificationTokens = []string{}
+	err = s.threadRepo.UpdateThreadCommentActivityTx(req.CommentID, req.UserID, updateValues, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeComment] Failed to update thread comment activity", zap.Error(err))
 
-		for _, ts := range threadSubscribers {
-			if ts.UserID == req.UserID {
-				continue
-			}
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment activity")
+	}
 
-			if ts.User.Devices != nil && len(ts.User.Devices) > 0 {
-				for _, tsud := range ts.User.Devices {
-					if tsud.IsNotificationActive {
-						subscriberNotificationTokens = append(subscriberNotificationTokens, tsud.NotificationToken)
-					}
-				}
-			}
-		}
+	// TODO: Save to thread comment activity history
 
-		if len(subscriberNotificationTokens) > 0 {
+	return nil
+}
 
-			notifData := map[string]string{
-				constants.APP_ROUTE_KEY:  "/thread/" + thread.ID,
-				constants.EVENT_TYPE_KEY: constants.COMMENT_ON_SUBSCRIBED_THREAD_EVENT,
-			}
+func (s *threadService) unlikeCommentReply(ctx context.Context, req request.LikeCommentReq, tx bun.Tx) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.unlikeCommentReply", "service")
+	//defer endFunc()
 
-			_, err = s.notifCl.SendPushNotification(ctx, notifsvc.SendPushNotificationReq{
-				NotificationTokens: subscriberNotificationTokens,
-				Title:              "A New Comment on Your Subscribed Thread!",
-				Content:            fmt.Sprintf("%s: %s", req.Username, pkg.TruncateWithEllipsis(req.Content, 50)),
-				Data:               notifData,
-			})
+	err := s.threadRepo.DecrementCommentReplyLikesCountTx(req.CommentID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeCommentReply] Failed to decrement thread comment reply likes count", zap.Error(err))
 
-			if err != nil {
-				s.cfg.Logger().ErrorWithContext(ctx, "[CommentThread] Failed to send push notification to thread subscribers", zap.Error(err))
-			}
-		}
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment reply likes count")
+	}
+
+	updateValues := map[string]interface{}{
+		"action":     constants.UNLIKE_ACTION,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	err = s.threadRepo.UpdateThreadCommentActivityReplyTx(req.CommentID, req.UserID, updateValues, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeCommentReply] Failed to update thread comment reply activity", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply activity")
 	}
 
+	// TODO: Save to thread comment activity history
+
 	return nil
 }
 
-func (s *threadService) ReplyComment(ctx context.Context, req request.ReplyCommentReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.ReplyComment", "service")
+func (s *threadService) getUserLastThreadCommentAction(ctx context.Context, threadId string, threadCommentId string, userId string) (string, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.getUserLastThreadCommentAction", "service")
 	//defer endFunc()
 
-	tx, err := s.db.Begin()
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to begin transaction", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-	}
+	tca, err := s.threadRepo.GetLastThreadCommentActivityByUserID(threadId, threadCommentId, userId)
 
-	err = s.threadRepo.IncrementCommentReplyCountTx(req.CommentID, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to increment comment reply count", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment reply count")
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getUserLastThreadCommentAction] Failed to get user last thread comment activity", zap.Error(err))
+		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread comment activity")
 	}
 
-	err = s.threadRepo.IncrementCommentsCountTx(req.ThreadID, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to increment thread comments count", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comments count")
+	if tca != nil {
+		return tca.Action, nil
 	}
 
-	threadCommentReply := &model.ThreadCommentReply{
-		ID:              uuid.NewString(),
-		ThreadID:        req.ThreadID,
-		UserID:          req.UserID,
-		ThreadCommentID: req.CommentID,
-		Content:         req.Content,
-		CreatedBy:       req.UserEmail,
-	}
+	return "", nil
+}
 
-	err = s.threadRepo.SaveCommentReplyTx(threadCommentReply, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to save thread comment reply", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment reply")
+func (s *threadService) getUserLastThreadCommentReplyAction(ctx context.Context, threadId string, threadCommentReplyId string, userId string) (string, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.getUserLastThreadCommentReplyAction", "service")
+	//defer endFunc()
+
+	tca, err := s.threadRepo.GetLastThreadCommentActivityReplyByUserID(threadId, threadCommentReplyId, userId)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getUserLastThreadCommentAction] Failed to get user last thread comment reply activity", zap.Error(err))
+		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread comment reply activity")
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[ReplyComment] Failed to commit transaction", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	if tca != nil {
+		return tca.Action, nil
 	}
 
-	return nil
+	return "", nil
 }
 
-func (s *threadService) LikeComment(ctx context.Context, req request.LikeCommentReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.LikeComment", "service")
+func (s *threadService) DislikeComment(ctx context.Context, req request.DislikeCommentReq) (err error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.DislikeComment", "service")
 	//defer endFunc()
 
+	defer func() {
+		if err == nil {
+			s.publishCommentVoteCount(ctx, req.CommentID, req.IsReply)
+		}
+	}()
+
 	// Handle is comment reply
 	if req.IsReply {
-		err := s.likeCommentReply(ctx, req)
+		err := s.dislikeCommentReply(ctx, req)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to like comment reply", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to dislike comment reply", zap.Error(err))
 			return err
 		}
 
 		return nil
 	}
 
-	var (
-		shouldDoubleIncrementUserReputationPoints bool
-	)
-
 	existingComment, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Thread comment not found", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Thread comment not found", zap.Error(err))
 			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread comment not found")
 		}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to get thread comment by id", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to get thread comment by id", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
+	parentThread, err := s.getAccessibleThread(ctx, "DislikeComment", existingComment.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkThreadOpen(ctx, "DislikeComment", parentThread)
+	if err != nil {
+		return err
+	}
+
 	lastThreadCommentActivity, err := s.getUserLastThreadCommentAction(ctx, req.ThreadID, req.CommentID, req.UserID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to get user last thread comment action", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to get user last thread comment action", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
 	tx, err := s.db.Begin()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to begin transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to begin transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	// Unlike if previously liked
-	if lastThreadCommentActivity == constants.LIKE_ACTION {
-		err = s.unlikeComment(ctx, req, tx)
+	// Unlike if previously disliked
+	if lastThreadCommentActivity == constants.DISLIKE_ACTION {
+		err = s.unDislikeComment(ctx, req, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to unlike comment", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to undislike comment", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unlike comment")
-		}
-
-		updateValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to undislike comment")
 		}
 
-		err = s.userRepo.DecrementUserReputationPointsTx(existingComment.UserID, updateValues, tx)
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingComment.UserID, constants.REPUTATION_SOURCE_COMMENT_VOTE, existingComment.ID, req.UserID, 0, req.UserEmail, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to decrement comment op reputation points", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to update comment op reputation points", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment op reputation points")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment op reputation points")
 		}
 
 		err = tx.Commit()
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to commit transaction", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to commit transaction", zap.Error(err))
 			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
 		return nil
 	}
 
-	// Decrement dislike count if previously disliked
-	if lastThreadCommentActivity == constants.DISLIKE_ACTION {
-		err = s.threadRepo.DecrementCommentDislikesCountTx(req.CommentID, tx)
+	// Decrement like count if previously liked
+	if lastThreadCommentActivity == constants.LIKE_ACTION {
+		err = s.threadRepo.DecrementCommentLikesCountTx(req.CommentID, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to decrement comment dislikes count", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to decrement thread comment likes count", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment dislikes count")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment likes count")
 		}
-
-		shouldDoubleIncrementUserReputationPoints = true
 	}
 
-	err = s.threadRepo.IncrementCommentLikesCountTx(req.CommentID, tx)
+	err = s.threadRepo.IncrementCommentDislikesCountTx(req.CommentID, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to increment comment likes count", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to increment thread comment dislikes count", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment likes count")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comment dislikes count")
 	}
 
 	if lastThreadCommentActivity != "" {
 
 		updateValues := map[string]interface{}{
-			"action":     constants.LIKE_ACTION,
+			"action":     constants.DISLIKE_ACTION,
 			"updated_by": req.UserEmail,
 			"updated_at": time.Now(),
 		}
 
 		err = s.threadRepo.UpdateThreadCommentActivityTx(req.CommentID, req.UserID, updateValues, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to update thread comment activity", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to update thread comment activity", zap.Error(err))
 			tx.Rollback()
 			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment activity")
 		}
 
-		updateUserValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
-		}
-
-		if shouldDoubleIncrementUserReputationPoints {
-			updateUserValues["reputation_points"] = constants.DEFAULT_INCREMENT_REPUTATION * 2
-		}
-
-		err = s.userRepo.IncrementUserReputationPointsTx(existingComment.UserID, updateUserValues, tx)
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingComment.UserID, constants.REPUTATION_SOURCE_COMMENT_VOTE, existingComment.ID, req.UserID, -constants.DEFAULT_DECREMENT_REPUTATION, req.UserEmail, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to increment comment op reputation points", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to update comment op reputation points", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment op reputation points")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment op reputation points")
 		}
 
 		// TODO: Save to thread activity history
 
 		err = tx.Commit()
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to commit transaction", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to commit transaction", zap.Error(err))
 			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
 		return nil
 	}
 
-	threadActivity := &model.ThreadCommentActivity{
+	threadCommentActivity := &model.ThreadCommentActivity{
 		ID:              uuid.NewString(),
 		ThreadID:        req.ThreadID,
 		ThreadCommentID: req.CommentID,
 		ActorID:         req.UserID,
 		ActorEmail:      req.UserEmail,
 		ActorUsername:   req.Username,
-		Action:          constants.LIKE_ACTION,
+		Action:          constants.DISLIKE_ACTION,
 		CreatedBy:       req.UserEmail,
 	}
 
-	err = s.threadRepo.SaveThreadCommentActivityTx(threadActivity, tx)
+	err = s.threadRepo.SaveThreadCommentActivityTx(threadCommentActivity, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to save thread comment activity", zap.Error(err))
-
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to save thread comment activity", zap.Error(err))
+		tx.Rollback()
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment activity")
 	}
 
-	updateValues := map[string]interface{}{
-		"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-		"updated_by":        req.UserEmail,
+	err = applyVoteReputationTx(s.reputationRepo, s.userRepo, existingComment.UserID, constants.REPUTATION_SOURCE_COMMENT_VOTE, existingComment.ID, req.UserID, -constants.DEFAULT_DECREMENT_REPUTATION, req.UserEmail, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to update comment op reputation points", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment op reputation points")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+func (s *threadService) unDislikeComment(ctx context.Context, req request.DislikeCommentReq, tx bun.Tx) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.unDislikeComment", "service")
+	//defer endFunc()
+
+	err := s.threadRepo.DecrementCommentDislikesCountTx(req.CommentID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeComment] Failed to decrement thread comment dislikes count", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment dislikes count")
 	}
 
-	if shouldDoubleIncrementUserReputationPoints {
-		updateValues["reputation_points"] = constants.DEFAULT_INCREMENT_REPUTATION * 2
+	updateValues := map[string]interface{}{
+		"action":     constants.UNDISLIKE_ACTION,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
 	}
 
-	err = s.userRepo.IncrementUserReputationPointsTx(existingComment.UserID, updateValues, tx)
+	err = s.threadRepo.UpdateThreadCommentActivityTx(req.CommentID, req.UserID, updateValues, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to increment comment op reputation points", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment op reputation points")
-	}
+		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeComment] Failed to update thread comment activity", zap.Error(err))
 
-	err = tx.Commit()
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[LikeComment] Failed to commit transaction", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment activity")
 	}
 
+	// TODO: Save to thread activity history
+
 	return nil
 }
 
-func (s *threadService) likeCommentReply(ctx context.Context, req request.LikeCommentReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.likeCommentReply", "service")
+func (s *threadService) dislikeCommentReply(ctx context.Context, req request.DislikeCommentReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.dislikeCommentReply", "service")
 	//defer endFunc()
 
-	var (
-		shouldDoubleIncrementUserReputationPoints bool
-	)
-
 	lastThreadCommentReplyActivity, err := s.getUserLastThreadCommentReplyAction(ctx, req.ThreadID, req.CommentID, req.UserID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to get user last thread comment reply action", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to get user last thread comment reply action", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
 	threadCommentReply, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Thread comment reply does not exist", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Thread comment reply does not exist", zap.Error(err))
 
 			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply does not exist")
 		}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to get thread comment reply by id", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to get thread comment reply by id", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread comment reply by id")
 	}
 
+	parentThread, err := s.getAccessibleThread(ctx, "dislikeCommentReply", threadCommentReply.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkThreadOpen(ctx, "dislikeCommentReply", parentThread)
+	if err != nil {
+		return err
+	}
+
 	tx, err := s.db.Begin()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to begin transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to begin transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	// Unlike if previously liked
-	if lastThreadCommentReplyActivity == constants.LIKE_ACTION {
-		err = s.unlikeCommentReply(ctx, req, tx)
+	// Unlike if previously disliked
+	if lastThreadCommentReplyActivity == constants.DISLIKE_ACTION {
+		err = s.unDislikeCommentReply(ctx, req, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to unlike comment reply", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to undislike comment reply", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unlike comment reply")
-		}
-
-		updateValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to undislike comment reply")
 		}
 
-		err = s.userRepo.DecrementUserReputationPointsTx(threadCommentReply.UserID, updateValues, tx)
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, threadCommentReply.UserID, constants.REPUTATION_SOURCE_COMMENT_REPLY_VOTE, threadCommentReply.ID, req.UserID, 0, req.UserEmail, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to decrement comment reply op reputation points", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to update comment reply op reputation points", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment reply op reputation points")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment reply op reputation points")
 		}
 
 		err = tx.Commit()
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to commit transaction", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to commit transaction", zap.Error(err))
 			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
 		return nil
 	}
 
-	// Decrement dislike count if previously disliked
-	if lastThreadCommentReplyActivity == constants.DISLIKE_ACTION {
-		err = s.threadRepo.DecrementCommentReplyDislikesCountTx(req.CommentID, tx)
+	// Decrement like count if previously liked
+	if lastThreadCommentReplyActivity == constants.LIKE_ACTION {
+		err = s.threadRepo.DecrementCommentReplyLikesCountTx(req.CommentID, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to decrement comment reply dislikes count", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to decrement thread comment reply likes count", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment reply dislikes count")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment reply likes count")
 		}
-
-		shouldDoubleIncrementUserReputationPoints = true
 	}
 
-	err = s.threadRepo.IncrementCommentReplyLikesCountTx(req.CommentID, tx)
+	err = s.threadRepo.IncrementCommentReplyDislikesCountTx(req.CommentID, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to increment comment reply likes count", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to increment thread comment reply dislikes count", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment reply likes count")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comment reply dislikes count")
 	}
 
 	if lastThreadCommentReplyActivity != "" {
 
 		updateValues := map[string]interface{}{
-			"action":     constants.LIKE_ACTION,
+			"action":     constants.DISLIKE_ACTION,
 			"updated_by": req.UserEmail,
 			"updated_at": time.Now(),
 		}
 
 		err = s.threadRepo.UpdateThreadCommentActivityReplyTx(req.CommentID, req.UserID, updateValues, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to update thread comment reply activity", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to update thread comment reply activity", zap.Error(err))
 			tx.Rollback()
 			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply activity")
 		}
 
-		updateUserValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
-		}
-
-		if shouldDoubleIncrementUserReputationPoints {
-			updateUserValues["reputation_points"] = constants.DEFAULT_INCREMENT_REPUTATION * 2
-		}
-
-		err = s.userRepo.IncrementUserReputationPointsTx(threadCommentReply.UserID, updateUserValues, tx)
+		err = applyVoteReputationTx(s.reputationRepo, s.userRepo, threadCommentReply.UserID, constants.REPUTATION_SOURCE_COMMENT_REPLY_VOTE, threadCommentReply.ID, req.UserID, -constants.DEFAULT_DECREMENT_REPUTATION, req.UserEmail, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to increment comment reply op reputation points", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to update comment reply op reputation points", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment reply op reputation points")
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment reply op reputation points")
 		}
 
 		// TODO: Save to thread activity history
 
 		err = tx.Commit()
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to commit transaction", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to commit transaction", zap.Error(err))
 			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
 		return nil
 	}
 
-	threadActivity := &model.ThreadCommentActivity{
+	threadCommentActivity := &model.ThreadCommentActivity{
 		ID:                   uuid.NewString(),
 		ThreadID:             req.ThreadID,
 		ThreadCommentID:      threadCommentReply.ThreadCommentID,
@@ -1191,36 +2730,27 @@ func (s *threadService) likeCommentReply(ctx context.Context, req request.LikeCo
 		ActorID:              req.UserID,
 		ActorEmail:           req.UserEmail,
 		ActorUsername:        req.Username,
-		Action:               constants.LIKE_ACTION,
+		Action:               constants.DISLIKE_ACTION,
 		CreatedBy:            req.UserEmail,
 	}
 
-	err = s.threadRepo.SaveThreadCommentActivityTx(threadActivity, tx)
+	err = s.threadRepo.SaveThreadCommentActivityTx(threadCommentActivity, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to save thread comment reply activity", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to save thread comment reply activity", zap.Error(err))
 		tx.Rollback()
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment reply activity")
 	}
 
-	updateUserValues := map[string]interface{}{
-		"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-		"updated_by":        req.UserEmail,
-	}
-
-	if shouldDoubleIncrementUserReputationPoints {
-		updateUserValues["reputation_points"] = constants.DEFAULT_INCREMENT_REPUTATION * 2
-	}
-
-	err = s.userRepo.IncrementUserReputationPointsTx(threadCommentReply.UserID, updateUserValues, tx)
+	err = applyVoteReputationTx(s.reputationRepo, s.userRepo, threadCommentReply.UserID, constants.REPUTATION_SOURCE_COMMENT_REPLY_VOTE, threadCommentReply.ID, req.UserID, -constants.DEFAULT_DECREMENT_REPUTATION, req.UserEmail, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to increment comment reply op reputation points", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to update comment reply op reputation points", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment reply op reputation points")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update comment reply op reputation points")
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[likeCommentReply] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
@@ -1229,761 +2759,919 @@ func (s *threadService) likeCommentReply(ctx context.Context, req request.LikeCo
 	return nil
 }
 
-func (s *threadService) unlikeComment(ctx context.Context, req request.LikeCommentReq, tx bun.Tx) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.unlikeComment", "service")
+func (s *threadService) unDislikeCommentReply(ctx context.Context, req request.DislikeCommentReq, tx bun.Tx) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.unDislikeCommentReply", "service")
 	//defer endFunc()
 
-	err := s.threadRepo.DecrementCommentLikesCountTx(req.CommentID, tx)
+	err := s.threadRepo.DecrementCommentReplyDislikesCountTx(req.CommentID, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeComment] Failed to decrement thread comment likes count", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeCommentReply] Failed to decrement thread comment reply dislikes count", zap.Error(err))
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment likes count")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment reply dislikes count")
 	}
 
 	updateValues := map[string]interface{}{
-		"action":     constants.UNLIKE_ACTION,
+		"action":     constants.UNDISLIKE_ACTION,
 		"updated_by": req.UserEmail,
 		"updated_at": time.Now(),
 	}
 
-	err = s.threadRepo.UpdateThreadCommentActivityTx(req.CommentID, req.UserID, updateValues, tx)
+	err = s.threadRepo.UpdateThreadCommentActivityReplyTx(req.CommentID, req.UserID, updateValues, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeComment] Failed to update thread comment activity", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeComment] Failed to update thread comment reply activity", zap.Error(err))
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment activity")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply activity")
 	}
 
-	// TODO: Save to thread comment activity history
+	// TODO: Save to thread activity history
 
 	return nil
 }
 
-func (s *threadService) unlikeCommentReply(ctx context.Context, req request.LikeCommentReq, tx bun.Tx) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.unlikeCommentReply", "service")
+func (s *threadService) GetThreadComments(ctx context.Context, req request.GetThreadCommentsReq) (response.GetThreadCommentsResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadComments", "service")
 	//defer endFunc()
 
-	err := s.threadRepo.DecrementCommentReplyLikesCountTx(req.CommentID, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeCommentReply] Failed to decrement thread comment reply likes count", zap.Error(err))
+	var resp response.GetThreadCommentsResponse
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment reply likes count")
+	_, err := s.getAccessibleThread(ctx, "GetThreadComments", req.ThreadID, req.UserID)
+	if err != nil {
+		return resp, err
 	}
 
-	updateValues := map[string]interface{}{
-		"action":     constants.UNLIKE_ACTION,
-		"updated_by": req.UserEmail,
-		"updated_at": time.Now(),
+	sortBy, err := s.getCommentSortBy(ctx, req.SortBy)
+	if err != nil {
+		return resp, err
 	}
 
-	err = s.threadRepo.UpdateThreadCommentActivityReplyTx(req.CommentID, req.UserID, updateValues, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unlikeCommentReply] Failed to update thread comment reply activity", zap.Error(err))
+	req.SortBy = sortBy
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply activity")
+	threadComments, pagination, err := s.threadRepo.GetThreadComments(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadComments] Failed to get thread comments", zap.Error(err))
+		return resp, err
 	}
 
-	// TODO: Save to thread comment activity history
-
-	return nil
-}
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
 
-func (s *threadService) getUserLastThreadCommentAction(ctx context.Context, threadId string, threadCommentId string, userId string) (string, error) {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.getUserLastThreadCommentAction", "service")
-	//defer endFunc()
+	resp.Data = s.mapThreadCommentsData(threadComments)
 
-	tca, err := s.threadRepo.GetLastThreadCommentActivityByUserID(threadId, threadCommentId, userId)
+	threadCommentIDs := []string{}
+	for _, tc := range resp.Data {
+		threadCommentIDs = append(threadCommentIDs, tc.ID)
+	}
 
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		s.cfg.Logger().ErrorWithContext(ctx, "[getUserLastThreadCommentAction] Failed to get user last thread comment activity", zap.Error(err))
-		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread comment activity")
+	attachmentsByComment, err := s.getAttachmentsByTargets(ctx, constants.ATTACHMENT_TARGET_TYPE_THREAD_COMMENT, threadCommentIDs)
+	if err != nil {
+		return resp, err
 	}
 
-	if tca != nil {
-		return tca.Action, nil
+	for i := range resp.Data {
+		resp.Data[i].Attachments = attachmentsByComment[resp.Data[i].ID]
 	}
 
-	return "", nil
+	return resp, nil
 }
 
-func (s *threadService) getUserLastThreadCommentReplyAction(ctx context.Context, threadId string, threadCommentReplyId string, userId string) (string, error) {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.getUserLastThreadCommentReplyAction", "service")
+func (s *threadService) GetThreadCommentReplies(ctx context.Context, req request.GetThreadCommentRepliesReq) (response.GetThreadCommentRepliesResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadCommentReplies", "service")
 	//defer endFunc()
 
-	tca, err := s.threadRepo.GetLastThreadCommentActivityReplyByUserID(threadId, threadCommentReplyId, userId)
+	var resp response.GetThreadCommentRepliesResponse
 
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		s.cfg.Logger().ErrorWithContext(ctx, "[getUserLastThreadCommentAction] Failed to get user last thread comment reply activity", zap.Error(err))
-		return "", oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user last thread comment reply activity")
+	threadComment, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentReplies] Thread comment not found", zap.Error(err))
+			return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentReplies] Failed to get thread comment by id", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	if tca != nil {
-		return tca.Action, nil
+	_, err = s.getAccessibleThread(ctx, "GetThreadCommentReplies", threadComment.ThreadID, req.UserID)
+	if err != nil {
+		return resp, err
 	}
 
-	return "", nil
-}
+	sortBy, err := s.getCommentSortBy(ctx, req.SortBy)
+	if err != nil {
+		return resp, err
+	}
 
-func (s *threadService) DislikeComment(ctx context.Context, req request.DislikeCommentReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.DislikeComment", "service")
-	//defer endFunc()
+	req.SortBy = sortBy
 
-	// Handle is comment reply
-	if req.IsReply {
-		err := s.dislikeCommentReply(ctx, req)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to dislike comment reply", zap.Error(err))
-			return err
-		}
+	threadCommentReplies, pagination, err := s.threadRepo.GetThreadCommentReplies(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentReplies] Failed to get thread comment replies", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread comment replies")
+	}
 
-		return nil
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
 	}
 
-	var (
-		shouldDoubleDecrementUserReputationPoints bool
-	)
+	resp.Data = s.mapThreadCommentRepliesData(threadCommentReplies)
 
-	existingComment, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
+	threadCommentReplyIDs := []string{}
+	for _, tcr := range resp.Data {
+		threadCommentReplyIDs = append(threadCommentReplyIDs, tcr.ID)
+	}
+
+	attachmentsByReply, err := s.getAttachmentsByTargets(ctx, constants.ATTACHMENT_TARGET_TYPE_THREAD_COMMENT_REPLY, threadCommentReplyIDs)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Thread comment not found", zap.Error(err))
-			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread comment not found")
-		}
+		return resp, err
+	}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to get thread comment by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	for i := range resp.Data {
+		resp.Data[i].Attachments = attachmentsByReply[resp.Data[i].ID]
 	}
 
-	lastThreadCommentActivity, err := s.getUserLastThreadCommentAction(ctx, req.ThreadID, req.CommentID, req.UserID)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to get user last thread comment action", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	return resp, nil
+}
+
+func (s *threadService) getCommentSortBy(ctx context.Context, sortBy string) (string, error) {
+
+	if sortBy == "" {
+		return constants.COMMENT_SORT_NEWEST, nil
 	}
 
-	tx, err := s.db.Begin()
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to begin transaction", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	sortBy = strings.ToUpper(sortBy)
+
+	if sortBy != constants.COMMENT_SORT_NEWEST && sortBy != constants.COMMENT_SORT_TOP && sortBy != constants.COMMENT_SORT_CONTROVERSIAL {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getCommentSortBy] Invalid comment sort", zap.String("sort_by", sortBy))
+		return "", oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Invalid sort, must be one of NEWEST, TOP or CONTROVERSIAL")
 	}
 
-	// Unlike if previously disliked
-	if lastThreadCommentActivity == constants.DISLIKE_ACTION {
-		err = s.unDislikeComment(ctx, req, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to undislike comment", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to undislike comment")
-		}
+	return sortBy, nil
+}
 
-		updateValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
-		}
+func (s *threadService) mapThreadCommentsData(threadComments []model.ThreadComment) []response.GetThreadCommentsData {
 
-		err = s.userRepo.IncrementUserReputationPointsTx(existingComment.UserID, updateValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to increment comment op reputation points", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment op reputation points")
-		}
+	threadCommentsData := []response.GetThreadCommentsData{}
 
-		err = tx.Commit()
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to commit transaction", zap.Error(err))
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	for _, tc := range threadComments {
+
+		tcd := response.GetThreadCommentsData{
+			ID:           tc.ID,
+			ThreadID:     tc.ThreadID,
+			UserID:       tc.UserID,
+			UserName:     tc.User.Username,
+			Content:      tc.Content,
+			LikeCount:    tc.LikeCount,
+			DislikeCount: tc.DislikeCount,
+			ReplyCount:   tc.ReplyCount,
+			CreatedBy:    tc.CreatedBy,
+			CreatedAt:    tc.CreatedAt,
+			UpdatedBy:    tc.UpdatedBy,
+			UpdatedAt:    tc.UpdatedAt,
+			EditedAt:     tc.EditedAt,
 		}
 
-		return nil
-	}
+		if tc.User.University != nil {
+			tcd.UniversityAbbreviatedName = pkg.ToPointer(tc.User.University.AbbreviatedName)
+			tcd.UniversityImageURL = pkg.ToPointer(tc.User.University.ImageURL)
+		}
 
-	// Decrement like count if previously liked
-	if lastThreadCommentActivity == constants.LIKE_ACTION {
-		err = s.threadRepo.DecrementCommentLikesCountTx(req.CommentID, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to decrement thread comment likes count", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment likes count")
+		if tc.CommentAction != "" {
+			if tc.CommentAction == constants.LIKE_ACTION {
+				tcd.IsLiked = true
+			}
+
+			if tc.CommentAction == constants.DISLIKE_ACTION {
+				tcd.IsDisliked = true
+			}
 		}
 
-		shouldDoubleDecrementUserReputationPoints = true
+		threadCommentsData = append(threadCommentsData, tcd)
 	}
 
-	err = s.threadRepo.IncrementCommentDislikesCountTx(req.CommentID, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to increment thread comment dislikes count", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comment dislikes count")
-	}
+	return threadCommentsData
+}
 
-	if lastThreadCommentActivity != "" {
+func (s *threadService) mapThreadCommentRepliesData(threadCommentReplies []model.ThreadCommentReply) []response.ThreadCommentReply {
+
+	threadCommentRepliesData := []response.ThreadCommentReply{}
+
+	for _, tcr := range threadCommentReplies {
+		tcrd := response.ThreadCommentReply{
+			ID:              tcr.ID,
+			ThreadID:        tcr.ThreadID,
+			ThreadCommentID: tcr.ThreadCommentID,
+			ParentReplyID:   tcr.ParentReplyID,
+			UserID:          tcr.UserID,
+			UserName:        tcr.User.Username,
+			Content:         tcr.Content,
+			LikeCount:       tcr.LikeCount,
+			DislikeCount:    tcr.DislikeCount,
+			ReplyCount:      tcr.ReplyCount,
+			CreatedBy:       tcr.CreatedBy,
+			CreatedAt:       tcr.CreatedAt,
+			UpdatedBy:       tcr.UpdatedBy,
+			UpdatedAt:       tcr.UpdatedAt,
+			EditedAt:        tcr.EditedAt,
+		}
+
+		if tcr.User.University != nil {
+			tcrd.UniversityAbbreviatedName = pkg.ToPointer(tcr.User.University.AbbreviatedName)
+			tcrd.UniversityImageURL = pkg.ToPointer(tcr.User.University.ImageURL)
+		}
+
+		if tcr.CommentReplyAction != "" {
+			if tcr.CommentReplyAction == constants.LIKE_ACTION {
+				tcrd.IsLiked = true
+			}
 
-		updateValues := map[string]interface{}{
-			"action":     constants.DISLIKE_ACTION,
-			"updated_by": req.UserEmail,
-			"updated_at": time.Now(),
+			if tcr.CommentReplyAction == constants.DISLIKE_ACTION {
+				tcrd.IsDisliked = true
+			}
 		}
 
-		err = s.threadRepo.UpdateThreadCommentActivityTx(req.CommentID, req.UserID, updateValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to update thread comment activity", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment activity")
-		}
+		threadCommentRepliesData = append(threadCommentRepliesData, tcrd)
+	}
 
-		updateUserValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
-		}
+	return threadCommentRepliesData
+}
 
-		if shouldDoubleDecrementUserReputationPoints {
-			updateUserValues["reputation_points"] = constants.DEFAULT_DECREMENT_REPUTATION * 2
-		}
+func (s *threadService) DeleteThreadComment(ctx context.Context, req request.DeleteThreadCommentReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.DeleteThreadComment", "service")
+	//defer endFunc()
 
-		err = s.userRepo.DecrementUserReputationPointsTx(existingComment.UserID, updateUserValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to decrement comment op reputation points", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment op reputation points")
+	tc, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Thread comment not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment not found")
 		}
 
-		// TODO: Save to thread activity history
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to delete thread comment by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread comment by id")
+	}
 
-		err = tx.Commit()
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to commit transaction", zap.Error(err))
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-		}
+	err = s.checkAuthorOrModerator(ctx, "DeleteThreadComment", tc.UserID, tc.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
 
-		return nil
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	threadCommentActivity := &model.ThreadCommentActivity{
-		ID:              uuid.NewString(),
-		ThreadID:        req.ThreadID,
-		ThreadCommentID: req.CommentID,
-		ActorID:         req.UserID,
-		ActorEmail:      req.UserEmail,
-		ActorUsername:   req.Username,
-		Action:          constants.DISLIKE_ACTION,
-		CreatedBy:       req.UserEmail,
+	updateValues := map[string]interface{}{
+		"deleted_by": req.UserEmail,
+		"deleted_at": time.Now(),
 	}
 
-	err = s.threadRepo.SaveThreadCommentActivityTx(threadCommentActivity, tx)
+	isDeleted, err := s.threadRepo.DeleteThreadCommentByIDTx(req.CommentID, updateValues, tx)
+
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to save thread comment activity", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to delete thread comment in database", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment activity")
-	}
-
-	updateUserValues := map[string]interface{}{
-		"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-		"updated_by":        req.UserEmail,
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread comment")
 	}
 
-	if shouldDoubleDecrementUserReputationPoints {
-		updateUserValues["reputation_points"] = constants.DEFAULT_DECREMENT_REPUTATION * 2
+	// Nothing to decrement when it was already deleted by a concurrent request
+	if !isDeleted {
+		tx.Rollback()
+		return nil
 	}
 
-	err = s.userRepo.DecrementUserReputationPointsTx(existingComment.UserID, updateUserValues, tx)
+	err = s.threadRepo.DecrementCommentsCountTx(tc.ThreadID, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to decrement comment op reputation points", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to decrement thread comments count", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment op reputation points")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comments count")
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DislikeComment] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
 	return nil
 }
 
-func (s *threadService) unDislikeComment(ctx context.Context, req request.DislikeCommentReq, tx bun.Tx) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.unDislikeComment", "service")
+func (s *threadService) UpdateThreadComment(ctx context.Context, req request.UpdateThreadCommentReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.UpdateThreadComment", "service")
 	//defer endFunc()
 
-	err := s.threadRepo.DecrementCommentDislikesCountTx(req.CommentID, tx)
+	threadComment, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeComment] Failed to decrement thread comment dislikes count", zap.Error(err))
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Thread comment not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment not found")
+		}
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment dislikes count")
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Failed to get thread comment by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment by id")
+	}
+
+	err = s.checkAuthorOrModerator(ctx, "UpdateThreadComment", threadComment.UserID, threadComment.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if threadComment.Content == req.Content {
+		return nil
 	}
 
+	now := time.Now()
+
 	updateValues := map[string]interface{}{
-		"action":     constants.UNDISLIKE_ACTION,
+		"content":    req.Content,
+		"edited_at":  now,
 		"updated_by": req.UserEmail,
-		"updated_at": time.Now(),
+		"updated_at": now,
 	}
 
-	err = s.threadRepo.UpdateThreadCommentActivityTx(req.CommentID, req.UserID, updateValues, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeComment] Failed to update thread comment activity", zap.Error(err))
-
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment activity")
+	revision := &model.ContentRevision{
+		ID:         uuid.NewString(),
+		TargetType: constants.REVISION_TARGET_TYPE_THREAD_COMMENT,
+		TargetID:   threadComment.ID,
+		ThreadID:   threadComment.ThreadID,
+		Content:    threadComment.Content,
+		CreatedBy:  req.UserEmail,
 	}
 
-	// TODO: Save to thread activity history
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
 
-	return nil
-}
+	err = s.revisionRepo.SaveTx(revision, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Failed to save thread comment revision", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment revision")
+	}
 
-func (s *threadService) dislikeCommentReply(ctx context.Context, req request.DislikeCommentReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.dislikeCommentReply", "service")
-	//defer endFunc()
+	err = s.threadRepo.UpdateThreadCommentByIDTx(req.CommentID, updateValues, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Failed to update thread comment in database", zap.Error(err))
 
-	var (
-		shouldDoubleDecrementUserReputationPoints bool
-	)
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment")
+	}
 
-	lastThreadCommentReplyActivity, err := s.getUserLastThreadCommentReplyAction(ctx, req.ThreadID, req.CommentID, req.UserID)
+	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to get user last thread comment reply action", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	threadCommentReply, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
+	s.realtimeHub.Publish(ctx, realtime.Event{
+		Type:     constants.THREAD_EVENT_COMMENT_EDITED,
+		ThreadID: threadComment.ThreadID,
+		Data: realtime.CommentEditedData{
+			TargetType: constants.THREAD_EVENT_TARGET_THREAD_COMMENT,
+			TargetID:   req.CommentID,
+			Content:    req.Content,
+		},
+	})
+
+	return nil
+}
+
+func (s *threadService) DeleteThreadCommentReply(ctx context.Context, req request.DeleteThreadCommentReplyReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.DeleteThreadCommentReply", "service")
+	//defer endFunc()
+
+	tcr, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Thread comment reply does not exist", zap.Error(err))
-
-			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply does not exist")
+			s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Thread comment reply not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply not found")
 		}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to get thread comment reply by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread comment reply by id")
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to delete thread comment reply by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread comment reply by id")
+	}
+
+	err = s.checkAuthorOrModerator(ctx, "DeleteThreadCommentReply", tcr.UserID, tcr.ThreadID, req.UserID)
+	if err != nil {
+		return err
 	}
 
 	tx, err := s.db.Begin()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to begin transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to begin transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	// Unlike if previously disliked
-	if lastThreadCommentReplyActivity == constants.DISLIKE_ACTION {
-		err = s.unDislikeCommentReply(ctx, req, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to undislike comment reply", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to undislike comment reply")
-		}
-
-		updateValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_INCREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
-		}
+	updateValues := map[string]interface{}{
+		"deleted_by": req.UserEmail,
+		"deleted_at": time.Now(),
+	}
 
-		err = s.userRepo.IncrementUserReputationPointsTx(threadCommentReply.UserID, updateValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to increment comment reply op reputation points", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment comment reply op reputation points")
-		}
+	isDeleted, err := s.threadRepo.DeleteThreadCommentReplyByIDTx(req.CommentID, updateValues, tx)
 
-		err = tx.Commit()
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to commit transaction", zap.Error(err))
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
-		}
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to delete thread comment reply in database", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread reply comment")
+	}
 
+	// Nothing to decrement when it was already deleted by a concurrent request
+	if !isDeleted {
+		tx.Rollback()
 		return nil
 	}
 
-	// Decrement like count if previously liked
-	if lastThreadCommentReplyActivity == constants.LIKE_ACTION {
-		err = s.threadRepo.DecrementCommentReplyLikesCountTx(req.CommentID, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to decrement thread comment reply likes count", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment reply likes count")
-		}
-
-		shouldDoubleDecrementUserReputationPoints = true
+	err = s.threadRepo.DecrementCommentsCountTx(tcr.ThreadID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to decrement thread comments count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comments count")
 	}
 
-	err = s.threadRepo.IncrementCommentReplyDislikesCountTx(req.CommentID, tx)
+	err = s.threadRepo.DecrementCommentReplyCountTx(tcr.ThreadCommentID, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to increment thread comment reply dislikes count", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to decrement comment reply count", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment thread comment reply dislikes count")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment reply count")
 	}
 
-	if lastThreadCommentReplyActivity != "" {
-
-		updateValues := map[string]interface{}{
-			"action":     constants.DISLIKE_ACTION,
-			"updated_by": req.UserEmail,
-			"updated_at": time.Now(),
-		}
-
-		err = s.threadRepo.UpdateThreadCommentActivityReplyTx(req.CommentID, req.UserID, updateValues, tx)
+	if tcr.ParentReplyID != nil {
+		err = s.threadRepo.DecrementCommentReplyReplyCountTx(*tcr.ParentReplyID, tx)
 		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to update thread comment reply activity", zap.Error(err))
+			s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to decrement parent reply reply count", zap.Error(err))
 			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply activity")
-		}
-
-		updateUserValues := map[string]interface{}{
-			"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-			"updated_by":        req.UserEmail,
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement parent reply reply count")
 		}
+	}
 
-		if shouldDoubleDecrementUserReputationPoints {
-			updateUserValues["reputation_points"] = constants.DEFAULT_DECREMENT_REPUTATION * 2
-		}
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
 
-		err = s.userRepo.DecrementUserReputationPointsTx(threadCommentReply.UserID, updateUserValues, tx)
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to decrement comment reply op reputation points", zap.Error(err))
-			tx.Rollback()
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment reply op reputation points")
-		}
+	return nil
+}
 
-		// TODO: Save to thread activity history
+func (s *threadService) UpdateThreadCommentReply(ctx context.Context, req request.UpdateThreadCommentReplyReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.UpdateThreadCommentReply", "service")
+	//defer endFunc()
 
-		err = tx.Commit()
-		if err != nil {
-			s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to commit transaction", zap.Error(err))
-			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	threadCommentReply, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Thread comment reply not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply not found")
 		}
 
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Failed to get thread comment reply by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply by id")
+	}
+
+	err = s.checkAuthorOrModerator(ctx, "UpdateThreadCommentReply", threadCommentReply.UserID, threadCommentReply.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if threadCommentReply.Content == req.Content {
 		return nil
 	}
 
-	threadCommentActivity := &model.ThreadCommentActivity{
-		ID:                   uuid.NewString(),
-		ThreadID:             req.ThreadID,
-		ThreadCommentID:      threadCommentReply.ThreadCommentID,
-		ThreadCommentReplyID: pkg.ToPointer(req.CommentID),
-		ActorID:              req.UserID,
-		ActorEmail:           req.UserEmail,
-		ActorUsername:        req.Username,
-		Action:               constants.DISLIKE_ACTION,
-		CreatedBy:            req.UserEmail,
+	now := time.Now()
+
+	updateValues := map[string]interface{}{
+		"content":    req.Content,
+		"edited_at":  now,
+		"updated_by": req.UserEmail,
+		"updated_at": now,
 	}
 
-	err = s.threadRepo.SaveThreadCommentActivityTx(threadCommentActivity, tx)
-	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to save thread comment reply activity", zap.Error(err))
-		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment reply activity")
+	revision := &model.ContentRevision{
+		ID:         uuid.NewString(),
+		TargetType: constants.REVISION_TARGET_TYPE_THREAD_COMMENT_REPLY,
+		TargetID:   threadCommentReply.ID,
+		ThreadID:   threadCommentReply.ThreadID,
+		Content:    threadCommentReply.Content,
+		CreatedBy:  req.UserEmail,
 	}
 
-	updateUserValues := map[string]interface{}{
-		"reputation_points": constants.DEFAULT_DECREMENT_REPUTATION,
-		"updated_by":        req.UserEmail,
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	if shouldDoubleDecrementUserReputationPoints {
-		updateUserValues["reputation_points"] = constants.DEFAULT_DECREMENT_REPUTATION * 2
+	err = s.revisionRepo.SaveTx(revision, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Failed to save thread comment reply revision", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save thread comment reply revision")
 	}
 
-	err = s.userRepo.DecrementUserReputationPointsTx(threadCommentReply.UserID, updateUserValues, tx)
+	err = s.threadRepo.UpdateThreadCommentReplyByIDTx(req.CommentID, updateValues, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to decrement comment reply op reputation points", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement comment reply op reputation points")
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Failed to update thread comment reply in database", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply")
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[dislikeCommentReply] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	// TODO: Save to thread comment activity history
+	s.realtimeHub.Publish(ctx, realtime.Event{
+		Type:     constants.THREAD_EVENT_COMMENT_EDITED,
+		ThreadID: threadCommentReply.ThreadID,
+		Data: realtime.CommentEditedData{
+			TargetType: constants.THREAD_EVENT_TARGET_THREAD_COMMENT_REPLY,
+			TargetID:   req.CommentID,
+			Content:    req.Content,
+		},
+	})
 
 	return nil
 }
 
-func (s *threadService) unDislikeCommentReply(ctx context.Context, req request.DislikeCommentReq, tx bun.Tx) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.unDislikeCommentReply", "service")
+func (s *threadService) GetThreadRevisions(ctx context.Context, req request.GetThreadRevisionsReq) (response.GetContentRevisionsResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadRevisions", "service")
 	//defer endFunc()
 
-	err := s.threadRepo.DecrementCommentReplyDislikesCountTx(req.CommentID, tx)
+	thread, err := s.getAccessibleThread(ctx, "GetThreadRevisions", req.ThreadID, req.UserID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeCommentReply] Failed to decrement thread comment reply dislikes count", zap.Error(err))
-
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comment reply dislikes count")
+		return response.GetContentRevisionsResponse{}, err
 	}
 
-	updateValues := map[string]interface{}{
-		"action":     constants.UNDISLIKE_ACTION,
-		"updated_by": req.UserEmail,
-		"updated_at": time.Now(),
-	}
+	return s.getRevisions(ctx, "GetThreadRevisions", constants.REVISION_TARGET_TYPE_THREAD, thread.ID, thread, thread.UserID, req.UserID)
+}
 
-	err = s.threadRepo.UpdateThreadCommentActivityReplyTx(req.CommentID, req.UserID, updateValues, tx)
+func (s *threadService) GetThreadCommentRevisions(ctx context.Context, req request.GetThreadCommentRevisionsReq) (response.GetContentRevisionsResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadCommentRevisions", "service")
+	//defer endFunc()
+
+	threadComment, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[unDislikeComment] Failed to update thread comment reply activity", zap.Error(err))
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentRevisions] Thread comment not found", zap.Error(err))
+			return response.GetContentRevisionsResponse{}, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment not found")
+		}
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply activity")
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentRevisions] Failed to get thread comment by id", zap.Error(err))
+		return response.GetContentRevisionsResponse{}, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	// TODO: Save to thread activity history
+	thread, err := s.getAccessibleThread(ctx, "GetThreadCommentRevisions", threadComment.ThreadID, req.UserID)
+	if err != nil {
+		return response.GetContentRevisionsResponse{}, err
+	}
 
-	return nil
+	return s.getRevisions(ctx, "GetThreadCommentRevisions", constants.REVISION_TARGET_TYPE_THREAD_COMMENT, threadComment.ID, thread, threadComment.UserID, req.UserID)
 }
 
-func (s *threadService) GetThreadComments(ctx context.Context, req request.GetThreadCommentsReq) (response.GetThreadCommentsResponse, error) {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadComments", "service")
+func (s *threadService) GetThreadCommentReplyRevisions(ctx context.Context, req request.GetThreadCommentRevisionsReq) (response.GetContentRevisionsResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.GetThreadCommentReplyRevisions", "service")
 	//defer endFunc()
 
-	var resp response.GetThreadCommentsResponse
+	threadCommentReply, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentReplyRevisions] Thread comment reply not found", zap.Error(err))
+			return response.GetContentRevisionsResponse{}, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentReplyRevisions] Failed to get thread comment reply by id", zap.Error(err))
+		return response.GetContentRevisionsResponse{}, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
 
-	threadComments, err := s.threadRepo.GetThreadCommentsByThreadID(req.ThreadID, req.UserID)
+	// The thread of the parent comment is authoritative, replies saved before the thread check may carry another thread id
+	threadComment, err := s.threadRepo.GetThreadCommentByID(threadCommentReply.ThreadCommentID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadComments] Failed to get thread comments", zap.Error(err))
-		return resp, err
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetThreadCommentReplyRevisions] Failed to get thread comment by id", zap.Error(err))
+		return response.GetContentRevisionsResponse{}, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	resp.Data = s.mapThreadCommentsData(threadComments)
+	thread, err := s.getAccessibleThread(ctx, "GetThreadCommentReplyRevisions", threadComment.ThreadID, req.UserID)
+	if err != nil {
+		return response.GetContentRevisionsResponse{}, err
+	}
 
-	return resp, nil
+	return s.getRevisions(ctx, "GetThreadCommentReplyRevisions", constants.REVISION_TARGET_TYPE_THREAD_COMMENT_REPLY, threadCommentReply.ID, thread, threadCommentReply.UserID, req.UserID)
 }
 
-func (s *threadService) mapThreadCommentsData(threadComments []model.ThreadComment) []response.GetThreadCommentsData {
-
-	threadCommentsData := []response.GetThreadCommentsData{}
-
-	for _, tc := range threadComments {
+// getRevisions lists the revisions to the author and the moderators of the subthread, or to everyone when the
+// subthread made its revisions public
+func (s *threadService) getRevisions(ctx context.Context, funcName string, targetType string, targetID string, thread model.Thread, authorID string, userID string) (response.GetContentRevisionsResponse, error) {
 
-		threadCommentReplies := []response.ThreadCommentReply{}
+	resp := response.GetContentRevisionsResponse{
+		TargetType: targetType,
+		TargetID:   targetID,
+	}
 
-		tcd := response.GetThreadCommentsData{
-			ID:           tc.ID,
-			ThreadID:     tc.ThreadID,
-			UserID:       tc.UserID,
-			UserName:     tc.User.Username,
-			Content:      tc.Content,
-			LikeCount:    tc.LikeCount,
-			DislikeCount: tc.DislikeCount,
-			CreatedBy:    tc.CreatedBy,
-			CreatedAt:    tc.CreatedAt,
-			UpdatedBy:    tc.UpdatedBy,
-			UpdatedAt:    tc.UpdatedAt,
+	if authorID != userID {
+		subThread, err := s.subThreadRepo.GetByID(thread.SubThreadID)
+		if err != nil {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get subthread by id", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 		}
 
-		if tc.User.University != nil {
-			tcd.UniversityAbbreviatedName = pkg.ToPointer(tc.User.University.AbbreviatedName)
-			tcd.UniversityImageURL = pkg.ToPointer(tc.User.University.ImageURL)
+		if !subThread.IsRevisionPublic {
+			err = s.checkSubThreadPermission(ctx, funcName, userID, constants.PERMISSION_MODERATE_CONTENT, thread.SubThreadID)
+			if err != nil {
+				return resp, err
+			}
 		}
+	}
 
-		if tc.CommentAction != "" {
-			if tc.CommentAction == constants.LIKE_ACTION {
-				tcd.IsLiked = true
-			}
+	revisions, err := s.revisionRepo.GetListByTarget(targetType, targetID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get revisions", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get revisions")
+	}
 
-			if tc.CommentAction == constants.DISLIKE_ACTION {
-				tcd.IsDisliked = true
-			}
-		}
+	resp.Data = revisions
 
-		for _, tcr := range tc.Replies {
-			tcrd := response.ThreadCommentReply{
-				ID:              tcr.ID,
-				ThreadID:        tcr.ThreadID,
-				ThreadCommentID: tcr.ThreadCommentID,
-				UserID:          tcr.UserID,
-				UserName:        tcr.User.Username,
-				Content:         tcr.Content,
-				LikeCount:       tcr.LikeCount,
-				DislikeCount:    tcr.DislikeCount,
-				CreatedBy:       tcr.CreatedBy,
-				CreatedAt:       tcr.CreatedAt,
-				UpdatedBy:       tcr.UpdatedBy,
-				UpdatedAt:       tcr.UpdatedAt,
-			}
+	return resp, nil
+}
 
-			if tcr.User.University != nil {
-				tcrd.UniversityAbbreviatedName = pkg.ToPointer(tcr.User.University.AbbreviatedName)
-				tcrd.UniversityImageURL = pkg.ToPointer(tcr.User.University.ImageURL)
-			}
+func (s *threadService) VoteThreadPoll(ctx context.Context, req request.VoteThreadPollReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.VoteThreadPoll", "service")
+	//defer endFunc()
 
-			if tcr.CommentReplyAction != "" {
-				if tcr.CommentReplyAction == constants.LIKE_ACTION {
-					tcrd.IsLiked = true
-				}
+	thread, err := s.getAccessibleThread(ctx, "VoteThreadPoll", req.ThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
 
-				if tcr.CommentReplyAction == constants.DISLIKE_ACTION {
-					tcrd.IsDisliked = true
-				}
-			}
+	err = s.checkThreadOpen(ctx, "VoteThreadPoll", thread)
+	if err != nil {
+		return err
+	}
 
-			threadCommentReplies = append(threadCommentReplies, tcrd)
-		}
+	threadPoll, err := s.getOpenThreadPoll(ctx, req.ThreadID)
+	if err != nil {
+		return err
+	}
 
-		tcd.Replies = threadCommentReplies
+	if !threadPoll.IsMultipleChoice && len(req.OptionIDs) > 1 {
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Multiple options voted on a single choice poll", zap.String("poll_id", threadPoll.ID))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Only one option can be voted on this poll")
+	}
 
-		threadCommentsData = append(threadCommentsData, tcd)
+	pollOptionIDs := map[string]bool{}
+	for _, o := range threadPoll.Options {
+		pollOptionIDs[o.ID] = true
 	}
 
-	return threadCommentsData
-}
+	threadPollVotes := []model.ThreadPollVote{}
+	votedOptionIDs := []string{}
 
-func (s *threadService) DeleteThreadComment(ctx context.Context, req request.DeleteThreadCommentReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.DeleteThreadComment", "service")
-	//defer endFunc()
+	for _, optionID := range req.OptionIDs {
+		if !pollOptionIDs[optionID] {
+			s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Option does not belong to the poll", zap.String("option_id", optionID))
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Poll option does not exist")
+		}
 
-	tc, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Thread comment not found", zap.Error(err))
-			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment not found")
+		if slices.Contains(votedOptionIDs, optionID) {
+			continue
 		}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to delete thread comment by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread comment by id")
+		votedOptionIDs = append(votedOptionIDs, optionID)
+		threadPollVotes = append(threadPollVotes, model.ThreadPollVote{
+			ID:               uuid.NewString(),
+			PollID:           threadPoll.ID,
+			OptionID:         optionID,
+			UserID:           req.UserID,
+			IsMultipleChoice: threadPoll.IsMultipleChoice,
+			CreatedBy:        req.UserEmail,
+		})
 	}
 
 	tx, err := s.db.Begin()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to begin transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Failed to begin transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	updateValues := map[string]interface{}{
-		"deleted_by": req.UserEmail,
-		"deleted_at": time.Now(),
+	// Incrementing the voter count first locks the poll row, so concurrent votes of the same user
+	// are serialized and the check below always sees the votes committed before it
+	err = s.threadPollRepo.IncrementVoterCountTx(threadPoll.ID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Failed to increment poll voter count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment poll voter count")
 	}
 
-	err = s.threadRepo.DeleteThreadCommentByIDTx(req.CommentID, updateValues, tx)
+	existingVoteCount, err := s.threadPollRepo.CountVotesByPollIDAndUserIDTx(threadPoll.ID, req.UserID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Failed to count user poll votes", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
 
+	if existingVoteCount > 0 {
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] User already voted on the poll", zap.String("poll_id", threadPoll.ID))
+		tx.Rollback()
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("User already voted on this poll")
+	}
+
+	err = s.threadPollRepo.BulkSaveVotesTx(threadPollVotes, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to delete thread comment in database", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Failed to save poll votes", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread comment")
+
+		if db.IsUniqueViolation(err) {
+			return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("User already voted on this poll")
+		}
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to save poll votes")
 	}
 
-	err = s.threadRepo.DecrementCommentsCountTx(tc.ThreadID, tx)
+	err = s.threadPollRepo.IncrementOptionsVoteCountTx(votedOptionIDs, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to decrement thread comments count", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Failed to increment poll options vote count", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comments count")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment poll options vote count")
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadComment] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[VoteThreadPoll] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
 	return nil
 }
 
-func (s *threadService) UpdateThreadComment(ctx context.Context, req request.UpdateThreadCommentReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.UpdateThreadComment", "service")
+func (s *threadService) UnVoteThreadPoll(ctx context.Context, req request.UnVoteThreadPollReq) error {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.UnVoteThreadPoll", "service")
 	//defer endFunc()
 
-	_, err := s.threadRepo.GetThreadCommentByID(req.CommentID)
+	thread, err := s.getAccessibleThread(ctx, "UnVoteThreadPoll", req.ThreadID, req.UserID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Thread comment not found", zap.Error(err))
-			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment not found")
-		}
-
-		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Failed to get thread comment by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment by id")
-	}
-
-	updateValues := map[string]interface{}{
-		"content":    req.Content,
-		"updated_by": req.UserEmail,
-		"updated_at": time.Now(),
+		return err
 	}
 
-	err = s.threadRepo.UpdateThreadCommentByID(req.CommentID, updateValues)
-
+	err = s.checkThreadOpen(ctx, "UnVoteThreadPoll", thread)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadComment] Failed to update thread comment in database", zap.Error(err))
-
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment")
+		return err
 	}
 
-	return nil
-}
-
-func (s *threadService) DeleteThreadCommentReply(ctx context.Context, req request.DeleteThreadCommentReplyReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.DeleteThreadCommentReply", "service")
-	//defer endFunc()
-
-	tcr, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
+	threadPoll, err := s.getOpenThreadPoll(ctx, req.ThreadID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Thread comment reply not found", zap.Error(err))
-			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply not found")
-		}
-
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to delete thread comment reply by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread comment reply by id")
+		return err
 	}
 
 	tx, err := s.db.Begin()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to begin transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnVoteThreadPoll] Failed to begin transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
-	updateValues := map[string]interface{}{
-		"deleted_by": req.UserEmail,
-		"deleted_at": time.Now(),
+	err = s.threadPollRepo.DecrementVoterCountTx(threadPoll.ID, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnVoteThreadPoll] Failed to decrement poll voter count", zap.Error(err))
+		tx.Rollback()
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement poll voter count")
 	}
 
-	err = s.threadRepo.DeleteThreadCommentReplyByIDTx(req.CommentID, updateValues, tx)
-
+	votedOptionIDs, err := s.threadPollRepo.DeleteVotesByPollIDAndUserIDTx(threadPoll.ID, req.UserID, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to delete thread comment reply in database", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnVoteThreadPoll] Failed to delete poll votes", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete thread reply comment")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to delete poll votes")
 	}
 
-	err = s.threadRepo.DecrementCommentsCountTx(tcr.ThreadID, tx)
+	if len(votedOptionIDs) == 0 {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnVoteThreadPoll] User has not voted on the poll", zap.String("poll_id", threadPoll.ID))
+		tx.Rollback()
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("User has not voted on this poll")
+	}
+
+	err = s.threadPollRepo.DecrementOptionsVoteCountTx(votedOptionIDs, tx)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to decrement thread comments count", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnVoteThreadPoll] Failed to decrement poll options vote count", zap.Error(err))
 		tx.Rollback()
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement thread comments count")
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement poll options vote count")
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[DeleteThreadCommentReply] Failed to commit transaction", zap.Error(err))
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnVoteThreadPoll] Failed to commit transaction", zap.Error(err))
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
 	}
 
 	return nil
 }
 
-func (s *threadService) UpdateThreadCommentReply(ctx context.Context, req request.UpdateThreadCommentReplyReq) error {
-	//ctx, endFunc := trace.Start(ctx, "ThreadService.UpdateThreadCommentReply", "service")
-	//defer endFunc()
+func (s *threadService) getOpenThreadPoll(ctx context.Context, threadID string) (model.ThreadPoll, error) {
 
-	_, err := s.threadRepo.GetThreadCommentReplyByID(req.CommentID)
+	threadPoll, err := s.threadPollRepo.GetByThreadID(threadID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Thread comment reply not found", zap.Error(err))
-			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread comment reply not found")
+			s.cfg.Logger().ErrorWithContext(ctx, "[getOpenThreadPoll] Thread poll not found", zap.Error(err))
+			return threadPoll, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread poll not found")
 		}
 
-		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Failed to get thread comment reply by id", zap.Error(err))
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply by id")
+		s.cfg.Logger().ErrorWithContext(ctx, "[getOpenThreadPoll] Failed to get thread poll", zap.Error(err))
+		return threadPoll, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread poll")
 	}
 
-	updateValues := map[string]interface{}{
-		"content":    req.Content,
-		"updated_by": req.UserEmail,
-		"updated_at": time.Now(),
+	if threadPoll.IsClosed() {
+		s.cfg.Logger().ErrorWithContext(ctx, "[getOpenThreadPoll] Thread poll is closed", zap.String("poll_id", threadPoll.ID))
+		return threadPoll, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Thread poll is closed")
 	}
 
-	err = s.threadRepo.UpdateThreadCommentReplyByID(req.CommentID, updateValues)
+	return threadPoll, nil
+}
 
+// publishThreadVoteCount re-reads the thread so streaming clients always receive the committed counts
+func (s *threadService) publishThreadVoteCount(ctx context.Context, threadID string) {
+	thread, err := s.threadRepo.GetByIDSimple(threadID)
 	if err != nil {
-		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateThreadCommentReply] Failed to update thread comment reply in database", zap.Error(err))
+		s.cfg.Logger().WarnWithContext(ctx, "[publishThreadVoteCount] Failed to get thread by id", zap.Error(err))
+		return
+	}
 
-		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update thread comment reply")
+	s.realtimeHub.Publish(ctx, realtime.Event{
+		Type:     constants.THREAD_EVENT_VOTE_COUNT_CHANGED,
+		ThreadID: thread.ID,
+		Data: realtime.VoteCountChangedData{
+			TargetType:   constants.THREAD_EVENT_TARGET_THREAD,
+			TargetID:     thread.ID,
+			LikeCount:    thread.LikeCount,
+			DislikeCount: thread.DislikeCount,
+		},
+	})
+}
+
+func (s *threadService) publishCommentVoteCount(ctx context.Context, commentID string, isReply bool) {
+	data := realtime.VoteCountChangedData{
+		TargetID: commentID,
 	}
 
-	return nil
+	var threadID string
+
+	if isReply {
+		threadCommentReply, err := s.threadRepo.GetThreadCommentReplyByID(commentID)
+		if err != nil {
+			s.cfg.Logger().WarnWithContext(ctx, "[publishCommentVoteCount] Failed to get thread comment reply by id", zap.Error(err))
+			return
+		}
+
+		threadID = threadCommentReply.ThreadID
+		data.TargetType = constants.THREAD_EVENT_TARGET_THREAD_COMMENT_REPLY
+		data.LikeCount = threadCommentReply.LikeCount
+		data.DislikeCount = threadCommentReply.DislikeCount
+	} else {
+		threadComment, err := s.threadRepo.GetThreadCommentByID(commentID)
+		if err != nil {
+			s.cfg.Logger().WarnWithContext(ctx, "[publishCommentVoteCount] Failed to get thread comment by id", zap.Error(err))
+			return
+		}
+
+		threadID = threadComment.ThreadID
+		data.TargetType = constants.THREAD_EVENT_TARGET_THREAD_COMMENT
+		data.LikeCount = threadComment.LikeCount
+		data.DislikeCount = threadComment.DislikeCount
+	}
+
+	s.realtimeHub.Publish(ctx, realtime.Event{
+		Type:     constants.THREAD_EVENT_VOTE_COUNT_CHANGED,
+		ThreadID: threadID,
+		Data:     data,
+	})
+}
+
+func (s *threadService) SubscribeThreadEvents(ctx context.Context, req request.SubscribeThreadEventsReq) (<-chan []byte, func(), error) {
+	//ctx, endFunc := trace.Start(ctx, "ThreadService.SubscribeThreadEvents", "service")
+	//defer endFunc()
+
+	thread, err := s.threadRepo.GetByIDSimple(req.ThreadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[SubscribeThreadEvents] Thread not found", zap.Error(err))
+			return nil, nil, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("Thread not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[SubscribeThreadEvents] Failed to get thread by id", zap.Error(err))
+		return nil, nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread by id")
+	}
+
+	err = s.checkSubThreadAccess(ctx, "SubscribeThreadEvents", thread.SubThreadID, req.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events, unsubscribe := s.realtimeHub.Subscribe(req.ThreadID)
+
+	return events, unsubscribe, nil
 }
 
 func (s *threadService) SubscribeThread(ctx context.Context, req request.SubscribeThreadReq) error {
@@ -2001,6 +3689,11 @@ func (s *threadService) SubscribeThread(ctx context.Context, req request.Subscri
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get thread by id")
 	}
 
+	err = s.checkSubThreadAccess(ctx, "SubscribeThread", t.SubThreadID, req.UserID)
+	if err != nil {
+		return err
+	}
+
 	if t.UserID == req.UserID {
 		s.cfg.Logger().WarnWithContext(ctx, "[SubscribeThread] Cannot subscribe to the user's own thread")
 		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Cannot subscribe to the user's own thread")
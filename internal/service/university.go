@@ -21,22 +21,44 @@ import (
 )
 
 type universityService struct {
-	cfg            config.Config
-	universityRepo repository.UniversityRepository
-	userRepo       repository.UserRepository
-	db             *bun.DB
+	cfg              config.Config
+	universityRepo   repository.UniversityRepository
+	userRepo         repository.UserRepository
+	badgeRepo        repository.BadgeRepository
+	notificationRepo repository.NotificationRepository
+	db               *bun.DB
 }
 
-func NewUniversityService(cfg config.Config, universityRepo repository.UniversityRepository, userRepo repository.UserRepository, db *bun.DB) UniversityService {
+func NewUniversityService(cfg config.Config, universityRepo repository.UniversityRepository, userRepo repository.UserRepository, badgeRepo repository.BadgeRepository, notificationRepo repository.NotificationRepository, db *bun.DB) UniversityService {
 
 	return &universityService{
-		cfg:            cfg,
-		universityRepo: universityRepo,
-		userRepo:       userRepo,
-		db:             db,
+		cfg:              cfg,
+		universityRepo:   universityRepo,
+		userRepo:         userRepo,
+		badgeRepo:        badgeRepo,
+		notificationRepo: notificationRepo,
+		db:               db,
 	}
 }
 
+// evaluateUniversityBadgesTx runs the rules of the badges earned by rating a university
+func (s *universityService) evaluateUniversityBadgesTx(ctx context.Context, user *model.User, pros []string, cons []string, createdBy string, tx bun.Tx) error {
+
+	err := evaluateVerifiedStudentBadgeTx(s.badgeRepo, s.notificationRepo, user, createdBy, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[evaluateUniversityBadgesTx] Failed to evaluate verified student badge", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to evaluate verified student badge")
+	}
+
+	err = evaluateUniversityReviewBadgeTx(s.badgeRepo, s.notificationRepo, user.ID, pros, cons, createdBy, tx)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[evaluateUniversityBadgesTx] Failed to evaluate university reviewer badge", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to evaluate university reviewer badge")
+	}
+
+	return nil
+}
+
 func (s *universityService) CreateUniversityRating(ctx context.Context, req request.RateUniversityReq) error {
 	//ctx, endFunc := trace.Start(ctx, "UniversityService.CreateUniversityRating", "service")
 	//defer endFunc()
@@ -154,6 +176,12 @@ func (s *universityService) CreateUniversityRating(ctx context.Context, req requ
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update user data")
 	}
 
+	err = s.evaluateUniversityBadgesTx(ctx, user, req.Pros, req.Cons, req.UserEmail, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		s.cfg.Logger().ErrorWithContext(ctx, "[CreateUniversityRating] Failed to commit transaction", zap.Error(err))
@@ -278,6 +306,12 @@ func (s *universityService) UpdateUniversityRating(ctx context.Context, req requ
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to create university rating pros and cons")
 	}
 
+	err = s.evaluateUniversityBadgesTx(ctx, user, req.Pros, req.Cons, req.UserEmail, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateUniversityRating] Failed to commit transaction", zap.Error(err))
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
 	"github.com/andibalo/meowhasiswa-be/internal/repository"
 	"github.com/andibalo/meowhasiswa-be/internal/request"
@@ -14,23 +15,34 @@ import (
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
 	"github.com/google/uuid"
 	"github.com/samber/oops"
+	"github.com/uptrace/bun"
 	"go.uber.org/zap"
 	"net/http"
 	"time"
 )
 
 type userService struct {
-	cfg      config.Config
-	userRepo repository.UserRepository
-	uniRepo  repository.UniversityRepository
+	cfg            config.Config
+	userRepo       repository.UserRepository
+	uniRepo        repository.UniversityRepository
+	attachmentRepo repository.AttachmentRepository
+	userBlockRepo  repository.UserBlockRepository
+	followerRepo   repository.UserFollowerRepository
+	threadSvc      ThreadService
+	db             *bun.DB
 }
 
-func NewUserService(cfg config.Config, userRepo repository.UserRepository, uniRepo repository.UniversityRepository) UserService {
+func NewUserService(cfg config.Config, userRepo repository.UserRepository, uniRepo repository.UniversityRepository, attachmentRepo repository.AttachmentRepository, userBlockRepo repository.UserBlockRepository, followerRepo repository.UserFollowerRepository, threadSvc ThreadService, db *bun.DB) UserService {
 
 	return &userService{
-		cfg:      cfg,
-		userRepo: userRepo,
-		uniRepo:  uniRepo,
+		cfg:            cfg,
+		userRepo:       userRepo,
+		uniRepo:        uniRepo,
+		attachmentRepo: attachmentRepo,
+		userBlockRepo:  userBlockRepo,
+		followerRepo:   followerRepo,
+		threadSvc:      threadSvc,
+		db:             db,
 	}
 }
 
@@ -64,6 +76,181 @@ func (s *userService) GetUserProfile(ctx context.Context, req request.GetUserPro
 	return user, nil
 }
 
+func (s *userService) UpdateUserProfile(ctx context.Context, req request.UpdateUserProfileReq) (*model.User, error) {
+	//ctx, endFunc := trace.Start(ctx, "UserService.UpdateUserProfile", "service")
+	//defer endFunc()
+
+	updateValues := map[string]interface{}{
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	// Empty strings clear the field
+	if req.Bio != nil {
+		updateValues["bio"] = pkg.EmptyStrToNil(*req.Bio)
+	}
+
+	if req.Major != nil {
+		updateValues["major"] = pkg.EmptyStrToNil(*req.Major)
+	}
+
+	if req.GraduationYear != nil {
+		updateValues["graduation_year"] = *req.GraduationYear
+	}
+
+	if req.ProfileVisibility != nil {
+		updateValues["profile_visibility"] = *req.ProfileVisibility
+	}
+
+	if req.HideThreads != nil {
+		updateValues["hide_threads"] = *req.HideThreads
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateUserProfile] Failed to begin transaction", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if req.AvatarAttachmentID != nil {
+		avatar, err := attachImageTx(ctx, s.cfg, s.attachmentRepo, *req.AvatarAttachmentID, req.UserID, constants.ATTACHMENT_TARGET_TYPE_USER_AVATAR, req.UserID, tx)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		updateValues["avatar_url"] = avatar.URL
+	} else if req.RemoveAvatar {
+		err = s.attachmentRepo.DetachByTargetTx(constants.ATTACHMENT_TARGET_TYPE_USER_AVATAR, req.UserID, tx)
+		if err != nil {
+			tx.Rollback()
+			s.cfg.Logger().ErrorWithContext(ctx, "[UpdateUserProfile] Failed to detach avatar", zap.Error(err))
+			return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to remove avatar")
+		}
+
+		updateValues["avatar_url"] = nil
+	}
+
+	err = s.userRepo.UpdateUserTx(req.UserID, updateValues, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateUserProfile] Failed to update user profile", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to update user profile")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UpdateUserProfile] Failed to commit transaction", zap.Error(err))
+		return nil, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return s.GetUserProfile(ctx, request.GetUserProfileReq{
+		UserID:    req.UserID,
+		UserEmail: req.UserEmail,
+	})
+}
+
+func (s *userService) GetPublicUserProfile(ctx context.Context, req request.GetPublicUserProfileReq) (response.PublicUserProfile, error) {
+	//ctx, endFunc := trace.Start(ctx, "UserService.GetPublicUserProfile", "service")
+	//defer endFunc()
+
+	var resp response.PublicUserProfile
+
+	user, err := s.userRepo.GetPublicProfileByUsername(req.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetPublicUserProfile] User not found", zap.Error(err))
+			return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetPublicUserProfile] Failed to get user profile", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user profile")
+	}
+
+	err = checkProfileVisibility(ctx, s.cfg, s.userRepo, user, req.UserID)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = response.PublicUserProfile{
+		ID:               user.ID,
+		Username:         user.Username,
+		AvatarURL:        user.AvatarURL,
+		Bio:              user.Bio,
+		Major:            user.Major,
+		GraduationYear:   user.GraduationYear,
+		ReputationPoints: user.ReputationPoints,
+		FollowersCount:   user.FollowersCount,
+		FollowingCount:   user.FollowingCount,
+		Badges:           mapUserBadges(user.Badges),
+		CreatedAt:        user.CreatedAt,
+	}
+
+	if req.UserID != "" && req.UserID != user.ID {
+		_, err = s.followerRepo.GetByUserIDAndFollowerID(user.ID, req.UserID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[GetPublicUserProfile] Failed to get user follower", zap.Error(err))
+			return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user profile")
+		}
+
+		resp.IsFollowing = err == nil
+	}
+
+	if user.University != nil {
+		resp.University = &response.ProfileUniversity{
+			ID:              user.University.ID,
+			Name:            user.University.Name,
+			AbbreviatedName: user.University.AbbreviatedName,
+			ImageURL:        user.University.ImageURL,
+		}
+	}
+
+	// The owner always sees their own threads, the next pages are fetched from the thread list with the user id
+	if user.HideThreads && user.ID != req.UserID {
+		return resp, nil
+	}
+
+	threads, err := s.threadSvc.GetThreadList(ctx, request.GetThreadListReq{
+		UserIDParam:         user.ID,
+		Limit:               10,
+		IncludeUserActivity: req.UserID != "",
+		UserID:              req.UserID,
+		UserEmail:           req.UserEmail,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Threads = threads.Data
+	resp.ThreadsMeta = &threads.Meta
+
+	return resp, nil
+}
+
+// checkProfileVisibility allows the owner to see their profile regardless of the visibility setting
+func checkProfileVisibility(ctx context.Context, cfg config.Config, userRepo repository.UserRepository, user *model.User, viewerID string) error {
+
+	if user.ID == viewerID || user.ProfileVisibility == constants.PROFILE_VISIBILITY_PUBLIC {
+		return nil
+	}
+
+	if user.ProfileVisibility == constants.PROFILE_VISIBILITY_UNIVERSITY && viewerID != "" && user.UniversityID != nil {
+		viewer, err := userRepo.GetByID(viewerID)
+		if err != nil {
+			cfg.Logger().ErrorWithContext(ctx, "[checkProfileVisibility] Failed to get viewer", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user profile")
+		}
+
+		if viewer.UniversityID != nil && *viewer.UniversityID == *user.UniversityID {
+			return nil
+		}
+
+		return oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("Profile is only visible to students of the same university")
+	}
+
+	return oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("Profile is private")
+}
+
 func (s *userService) GetUserDevices(ctx context.Context, req request.GetUserDevicesReq) ([]model.UserDevice, error) {
 	//ctx, endFunc := trace.Start(ctx, "UserService.GetUserDevices", "service")
 	//defer endFunc()
@@ -155,14 +342,42 @@ func (s *userService) BanUser(ctx context.Context, req request.BanUserReq) error
 		"updated_at": time.Now(),
 	}
 
-	err = s.userRepo.UpdateUser(req.BanUserID, updateValues)
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanUser] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
 
+	err = s.userRepo.UpdateUserTx(req.BanUserID, updateValues, tx)
 	if err != nil {
+		tx.Rollback()
 		s.cfg.Logger().ErrorWithContext(ctx, "[BanUser] Failed to ban user", zap.Error(err))
 
 		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to ban user")
 	}
 
+	// Revoke every active session so the ban takes effect immediately
+	revokeValues := map[string]interface{}{
+		"revoked_at": time.Now(),
+		"revoked_by": req.UserEmail,
+		"updated_by": req.UserEmail,
+		"updated_at": time.Now(),
+	}
+
+	err = s.userRepo.RevokeUserSessionsByUserIDTx(req.BanUserID, revokeValues, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanUser] Failed to revoke user sessions", zap.Error(err))
+
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to ban user")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BanUser] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
 	return nil
 }
 
@@ -203,3 +418,355 @@ func (s *userService) UnBanUser(ctx context.Context, req request.UnBanUserReq) e
 
 	return nil
 }
+
+// BlockUser blocks or mutes a user, blocking or muting an already blocked or muted user changes the type
+func (s *userService) BlockUser(ctx context.Context, req request.BlockUserReq) error {
+	//ctx, endFunc := trace.Start(ctx, "UserService.BlockUser", "service")
+	//defer endFunc()
+
+	if req.BlockedUserID == req.UserID {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] User cannot block themselves", zap.String("user_id", req.UserID))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("You cannot block yourself")
+	}
+
+	_, err := s.userRepo.GetByID(req.BlockedUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] User not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to get user by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	existingUserBlock, err := s.userBlockRepo.GetByBlockedUserID(req.UserID, req.BlockedUserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to get user block", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingUserBlock.ID != "" && existingUserBlock.Type == req.Type {
+		s.cfg.Logger().WarnWithContext(ctx, "[BlockUser] User is already blocked")
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingUserBlock.ID != "" {
+		updateValues := map[string]interface{}{
+			"type":       req.Type,
+			"updated_by": req.UserEmail,
+			"updated_at": time.Now(),
+		}
+
+		err = s.userBlockRepo.UpdateByIDTx(existingUserBlock.ID, updateValues, tx)
+		if err != nil {
+			tx.Rollback()
+			s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to update user block", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to block user")
+		}
+	} else {
+		userBlock := &model.UserBlock{
+			ID:            uuid.NewString(),
+			UserID:        req.UserID,
+			BlockedUserID: req.BlockedUserID,
+			Type:          req.Type,
+			CreatedBy:     req.UserEmail,
+		}
+
+		err = s.userBlockRepo.SaveTx(userBlock, tx)
+		if err != nil {
+			tx.Rollback()
+			s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to save user block", zap.Error(err))
+			return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to block user")
+		}
+	}
+
+	// A block ends the follows between both users, a mute stays silent so the muted user keeps following
+	if req.Type == constants.USER_BLOCK_TYPE_BLOCK {
+		follows := []struct {
+			userID     string
+			followerID string
+		}{
+			{userID: req.UserID, followerID: req.BlockedUserID},
+			{userID: req.BlockedUserID, followerID: req.UserID},
+		}
+
+		for _, f := range follows {
+			isDeleted, err := s.followerRepo.DeleteTx(f.userID, f.followerID, tx)
+			if err != nil {
+				tx.Rollback()
+				s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to delete user follower", zap.Error(err))
+				return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to block user")
+			}
+
+			if !isDeleted {
+				continue
+			}
+
+			err = s.followerRepo.DecrementFollowCountsTx(f.userID, f.followerID, tx)
+			if err != nil {
+				tx.Rollback()
+				s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to decrement follow counts", zap.Error(err))
+				return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to block user")
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[BlockUser] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+func (s *userService) UnblockUser(ctx context.Context, req request.UnblockUserReq) error {
+	//ctx, endFunc := trace.Start(ctx, "UserService.UnblockUser", "service")
+	//defer endFunc()
+
+	isDeleted, err := s.userBlockRepo.DeleteByBlockedUserID(req.UserID, req.BlockedUserID)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnblockUser] Failed to delete user block", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unblock user")
+	}
+
+	if !isDeleted {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnblockUser] User block not found", zap.String("blocked_user_id", req.BlockedUserID))
+		return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User is not blocked")
+	}
+
+	return nil
+}
+
+func (s *userService) GetUserBlockList(ctx context.Context, req request.GetUserBlockListReq) (response.GetUserBlockListResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "UserService.GetUserBlockList", "service")
+	//defer endFunc()
+
+	var resp response.GetUserBlockListResponse
+
+	userBlocks, pagination, err := s.userBlockRepo.GetList(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[GetUserBlockList] Failed to get user block list", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user block list")
+	}
+
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	resp.Data = []response.UserBlockData{}
+
+	for _, ub := range userBlocks {
+		ubd := response.UserBlockData{
+			UserID:    ub.BlockedUserID,
+			Type:      ub.Type,
+			CreatedAt: ub.CreatedAt,
+		}
+
+		if ub.BlockedUser != nil {
+			ubd.Username = ub.BlockedUser.Username
+			ubd.AvatarURL = ub.BlockedUser.AvatarURL
+		}
+
+		resp.Data = append(resp.Data, ubd)
+	}
+
+	return resp, nil
+}
+
+func (s *userService) FollowUser(ctx context.Context, req request.FollowUserReq) error {
+	//ctx, endFunc := trace.Start(ctx, "UserService.FollowUser", "service")
+	//defer endFunc()
+
+	if req.FollowedUserID == req.UserID {
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] User cannot follow themselves", zap.String("user_id", req.UserID))
+		return oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("You cannot follow yourself")
+	}
+
+	_, err := s.userRepo.GetByID(req.FollowedUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] User not found", zap.Error(err))
+			return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] Failed to get user by id", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	userBlock, err := s.userBlockRepo.GetByBlockedUserID(req.FollowedUserID, req.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] Failed to get user block", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if userBlock.Type == constants.USER_BLOCK_TYPE_BLOCK {
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] User is blocked", zap.String("followed_user_id", req.FollowedUserID))
+		return oops.Code(response.UserBlocked.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf("You cannot follow this user")
+	}
+
+	existingFollower, err := s.followerRepo.GetByUserIDAndFollowerID(req.FollowedUserID, req.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] Failed to get user follower", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	if existingFollower.ID != "" {
+		s.cfg.Logger().WarnWithContext(ctx, "[FollowUser] User already followed")
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	userFollower := &model.UserFollower{
+		ID:         uuid.NewString(),
+		UserID:     req.FollowedUserID,
+		FollowerID: req.UserID,
+		CreatedBy:  req.UserEmail,
+	}
+
+	err = s.followerRepo.SaveTx(userFollower, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] Failed to save user follower", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to follow user")
+	}
+
+	err = s.followerRepo.IncrementFollowCountsTx(req.FollowedUserID, req.UserID, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] Failed to increment follow counts", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to increment follow counts")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[FollowUser] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+func (s *userService) UnFollowUser(ctx context.Context, req request.UnFollowUserReq) error {
+	//ctx, endFunc := trace.Start(ctx, "UserService.UnFollowUser", "service")
+	//defer endFunc()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnFollowUser] Failed to begin transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	isDeleted, err := s.followerRepo.DeleteTx(req.FollowedUserID, req.UserID, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnFollowUser] Failed to delete user follower", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to unfollow user")
+	}
+
+	if !isDeleted {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnFollowUser] User follower not found", zap.String("followed_user_id", req.FollowedUserID))
+		return oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("You are not following this user")
+	}
+
+	err = s.followerRepo.DecrementFollowCountsTx(req.FollowedUserID, req.UserID, tx)
+	if err != nil {
+		tx.Rollback()
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnFollowUser] Failed to decrement follow counts", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to decrement follow counts")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "[UnFollowUser] Failed to commit transaction", zap.Error(err))
+		return oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return nil
+}
+
+func (s *userService) GetUserFollowers(ctx context.Context, req request.GetUserFollowListReq) (response.GetUserFollowListResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "UserService.GetUserFollowers", "service")
+	//defer endFunc()
+
+	return s.getUserFollowList(ctx, "GetUserFollowers", req, s.followerRepo.GetFollowers)
+}
+
+func (s *userService) GetUserFollowing(ctx context.Context, req request.GetUserFollowListReq) (response.GetUserFollowListResponse, error) {
+	//ctx, endFunc := trace.Start(ctx, "UserService.GetUserFollowing", "service")
+	//defer endFunc()
+
+	return s.getUserFollowList(ctx, "GetUserFollowing", req, s.followerRepo.GetFollowing)
+}
+
+// getUserFollowList lists the followers or the followings of a profile, under the same visibility as the profile
+func (s *userService) getUserFollowList(ctx context.Context, funcName string, req request.GetUserFollowListReq, getList func(req request.GetUserFollowListReq) ([]model.UserFollower, pkg.Pagination, error)) (response.GetUserFollowListResponse, error) {
+
+	var resp response.GetUserFollowListResponse
+
+	user, err := s.userRepo.GetPublicProfileByUsername(req.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] User not found", zap.Error(err))
+			return resp, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf("User not found")
+		}
+
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get user profile", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user profile")
+	}
+
+	err = checkProfileVisibility(ctx, s.cfg, s.userRepo, user, req.UserID)
+	if err != nil {
+		return resp, err
+	}
+
+	req.ProfileUserID = user.ID
+
+	userFollowers, pagination, err := getList(req)
+	if err != nil {
+		s.cfg.Logger().ErrorWithContext(ctx, "["+funcName+"] Failed to get user follow list", zap.Error(err))
+		return resp, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf("Failed to get user follow list")
+	}
+
+	resp.Meta = response.PaginationMeta{
+		CurrentCursor: pagination.CurrentCursor,
+		NextCursor:    pagination.NextCursor,
+	}
+
+	resp.Data = []response.UserFollowData{}
+
+	for _, uf := range userFollowers {
+		// The listed user is the follower in the followers list and the followed user in the following list
+		listedUser := uf.Follower
+		if listedUser == nil {
+			listedUser = uf.User
+		}
+
+		if listedUser == nil {
+			continue
+		}
+
+		resp.Data = append(resp.Data, response.UserFollowData{
+			UserID:     listedUser.ID,
+			Username:   listedUser.Username,
+			AvatarURL:  listedUser.AvatarURL,
+			FollowedAt: uf.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,43 @@
+package thread
+
+import (
+	"context"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/repository"
+	"github.com/andibalo/meowhasiswa-be/internal/worker"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Archiver periodically archives the threads without a new comment or reply for the configured number of days.
+// Archived threads stay readable but take no new comments or votes
+type Archiver struct {
+	cfg        config.Config
+	threadRepo repository.ThreadRepository
+}
+
+// NewArchiver returns the worker running the archiver. When it is shut down the in-flight archive is cancelled
+// and rolled back, the threads are archived on the next start
+func NewArchiver(cfg config.Config, threadRepo repository.ThreadRepository) *worker.Worker {
+
+	a := &Archiver{
+		cfg:        cfg,
+		threadRepo: threadRepo,
+	}
+
+	return worker.New("thread archiver", time.Duration(cfg.GetThreadArchiveCfg().IntervalMins)*time.Minute, a.archive)
+}
+
+func (a *Archiver) archive(ctx context.Context, stopping <-chan struct{}) {
+	inactiveBefore := time.Now().AddDate(0, 0, -a.cfg.GetThreadArchiveCfg().InactiveDays)
+
+	count, err := a.threadRepo.ArchiveInactive(ctx, inactiveBefore)
+	if err != nil {
+		a.cfg.Logger().ErrorWithContext(ctx, "[Archiver.archive] Failed to archive inactive threads", zap.Error(err))
+		return
+	}
+
+	if count > 0 {
+		a.cfg.Logger().Info("[Archiver.archive] Archived inactive threads", zap.Int64("count", count))
+	}
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// Task is run on every tick of a Worker. ctx is cancelled when the shutdown deadline expires, stopping is closed
+// as soon as the shutdown starts so a task working through a batch can return early
+type Task func(ctx context.Context, stopping <-chan struct{})
+
+// Worker runs a task right away and then on every interval in a background goroutine
+type Worker struct {
+	name     string
+	interval time.Duration
+	task     Task
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func New(name string, interval time.Duration, task Task) *Worker {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		name:     name,
+		interval: interval,
+		task:     task,
+		ctx:      ctx,
+		cancel:   cancel,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (w *Worker) Name() string {
+	return w.name
+}
+
+// Start runs the worker loop in a background goroutine
+func (w *Worker) Start() {
+	go w.run()
+}
+
+// Shutdown stops the ticks and waits for the in-flight task to finish. When ctx expires first the context of
+// the task is cancelled and Shutdown returns once the task gave up
+func (w *Worker) Shutdown(ctx context.Context) error {
+	close(w.stop)
+
+	select {
+	case <-w.done:
+		w.cancel()
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		<-w.done
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.task(w.ctx, w.stop)
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsStopping reports whether the shutdown of the worker running the task has started
+func IsStopping(stopping <-chan struct{}) bool {
+	select {
+	case <-stopping:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,18 @@
+CREATE TABLE user_session (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    refresh_token VARCHAR(255) NOT NULL,
+    user_agent VARCHAR(255),
+    ip_address VARCHAR(100),
+    expired_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    revoked_by VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_session_refresh_token_index ON user_session(refresh_token);
+CREATE INDEX IF NOT EXISTS user_session_user_id_index ON user_session(user_id);
@@ -0,0 +1,88 @@
+CREATE TABLE role (
+    id UUID PRIMARY KEY NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    scope_type VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS role_name_index ON role(name);
+
+CREATE TABLE permission (
+    id UUID PRIMARY KEY NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS permission_name_index ON permission(name);
+
+CREATE TABLE role_permission (
+    id UUID PRIMARY KEY NOT NULL,
+    role_id UUID NOT NULL REFERENCES role(id),
+    permission_id UUID NOT NULL REFERENCES permission(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS role_permission_role_id_permission_id_index ON role_permission(role_id, permission_id);
+
+-- scope_type and scope_id are NULL for global roles, otherwise they point to the
+-- subthread/university the role is granted for
+CREATE TABLE user_role (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    role_id UUID NOT NULL REFERENCES role(id),
+    scope_type VARCHAR(100),
+    scope_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100),
+    deleted_at TIMESTAMPTZ,
+    deleted_by VARCHAR(100)
+);
+
+CREATE INDEX IF NOT EXISTS user_role_user_id_index ON user_role(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS user_role_active_index ON user_role(user_id, role_id, COALESCE(scope_id, '00000000-0000-0000-0000-000000000000')) WHERE deleted_at IS NULL;
+
+INSERT INTO role (id, name, description, scope_type, created_by) VALUES
+('8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', 'ADMIN', 'Full access to every resource', NULL, 'SYSTEM'),
+('8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f02', 'MODERATOR', 'Moderate content and users across the platform', NULL, 'SYSTEM'),
+('8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f03', 'SUBTHREAD_MODERATOR', 'Moderate content of a single subthread', 'SUBTHREAD', 'SYSTEM'),
+('8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f04', 'UNIVERSITY_REPRESENTATIVE', 'Represent a single university', 'UNIVERSITY', 'SYSTEM');
+
+INSERT INTO permission (id, name, description, created_by) VALUES
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e01', 'MANAGE_ROLE', 'Grant and revoke user roles', 'SYSTEM'),
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e02', 'BAN_USER', 'Ban and unban users', 'SYSTEM'),
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e03', 'MODERATE_CONTENT', 'Remove threads and comments of other users', 'SYSTEM'),
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e04', 'MANAGE_SUBTHREAD', 'Update and delete subthreads', 'SYSTEM'),
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e05', 'MANAGE_UNIVERSITY', 'Manage university information', 'SYSTEM');
+
+INSERT INTO role_permission (id, role_id, permission_id, created_by) VALUES
+-- ADMIN
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e01', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e01', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e02', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e02', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e03', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e03', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e04', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e04', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e05', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e05', 'SYSTEM'),
+-- MODERATOR
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e06', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f02', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e02', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e07', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f02', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e03', 'SYSTEM'),
+-- SUBTHREAD_MODERATOR
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e08', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f03', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e03', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e09', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f03', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e04', 'SYSTEM'),
+-- UNIVERSITY_REPRESENTATIVE
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e10', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f04', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e05', 'SYSTEM');
+
+-- migrate existing admins, previously identified by user.role or the superadmin email
+INSERT INTO user_role (id, user_id, role_id, created_by)
+SELECT gen_random_uuid(), u.id, '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', 'SYSTEM'
+FROM "user" u
+WHERE u.role = 'ADMIN' OR u.email = 'meowhasiswa.admin@meowhasiswa.com';
@@ -0,0 +1,34 @@
+CREATE TABLE report (
+    id UUID PRIMARY KEY NOT NULL,
+    reporter_id UUID NOT NULL REFERENCES "user"(id),
+    content_type VARCHAR(100) NOT NULL,
+    content_id UUID NOT NULL,
+    content_author_id UUID NOT NULL REFERENCES "user"(id),
+    reason VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    status VARCHAR(100) NOT NULL DEFAULT 'PENDING',
+    resolution_action VARCHAR(100),
+    resolution_note VARCHAR(255),
+    resolved_at TIMESTAMPTZ,
+    resolved_by VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100)
+);
+
+CREATE INDEX IF NOT EXISTS report_content_index ON report(content_type, content_id);
+CREATE INDEX IF NOT EXISTS report_status_created_at_index ON report(status, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS report_pending_reporter_content_index ON report(reporter_id, content_type, content_id) WHERE status = 'PENDING';
+
+CREATE TABLE user_warning (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    content_type VARCHAR(100) NOT NULL,
+    content_id UUID NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_warning_user_id_index ON user_warning(user_id);
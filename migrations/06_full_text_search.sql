@@ -0,0 +1,30 @@
+-- 'simple' is used instead of a language config since content is a mix of indonesian and english
+ALTER TABLE thread ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(content_summary, '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE(content, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS thread_search_vector_index ON thread USING GIN(search_vector);
+
+ALTER TABLE subthread ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS subthread_search_vector_index ON subthread USING GIN(search_vector);
+
+ALTER TABLE university ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(abbreviated_name, '')), 'A')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS university_search_vector_index ON university USING GIN(search_vector);
+
+ALTER TABLE university_rating ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(university_major, '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE(content, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS university_rating_search_vector_index ON university_rating USING GIN(search_vector);
@@ -0,0 +1,18 @@
+CREATE TABLE notification (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    actor_id UUID REFERENCES "user"(id),
+    type VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content VARCHAR(255) NOT NULL,
+    data JSONB,
+    dedupe_key VARCHAR(255),
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS notification_user_id_created_at_index ON notification(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS notification_user_id_unread_index ON notification(user_id) WHERE is_read = FALSE;
+CREATE UNIQUE INDEX IF NOT EXISTS notification_user_id_dedupe_key_index ON notification(user_id, dedupe_key) WHERE dedupe_key IS NOT NULL;
@@ -0,0 +1,14 @@
+ALTER TABLE thread_comment_reply
+    ADD COLUMN parent_reply_id UUID REFERENCES thread_comment_reply(id),
+    ADD COLUMN reply_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS thread_comment_cursor_index ON thread_comment(thread_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS thread_comment_reply_cursor_index ON thread_comment_reply(thread_comment_id, created_at, id) WHERE deleted_at IS NULL AND parent_reply_id IS NULL;
+CREATE INDEX IF NOT EXISTS thread_comment_reply_parent_reply_cursor_index ON thread_comment_reply(parent_reply_id, created_at, id) WHERE deleted_at IS NULL AND parent_reply_id IS NOT NULL;
+
+-- Keep the comment reply counter consistent with replies that were soft deleted before it was maintained on delete
+UPDATE thread_comment AS thc
+SET reply_count = (
+    SELECT COUNT(*) FROM thread_comment_reply AS thcr
+    WHERE thcr.thread_comment_id = thc.id AND thcr.deleted_at IS NULL
+);
@@ -0,0 +1,13 @@
+ALTER TABLE "user"
+    ADD COLUMN failed_login_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until TIMESTAMPTZ;
+
+-- Token buckets of the postgres rate limit store, shared by every instance
+CREATE UNLOGGED TABLE rate_limit_bucket (
+    key VARCHAR(255) PRIMARY KEY NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    rate_per_sec DOUBLE PRECISION NOT NULL,
+    burst DOUBLE PRECISION NOT NULL,
+    is_allowed BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,38 @@
+CREATE TABLE thread_poll (
+    id UUID PRIMARY KEY NOT NULL,
+    thread_id UUID NOT NULL UNIQUE REFERENCES thread(id),
+    question VARCHAR(255) NOT NULL,
+    is_multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMPTZ,
+    voter_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100)
+);
+
+CREATE TABLE thread_poll_option (
+    id UUID PRIMARY KEY NOT NULL,
+    poll_id UUID NOT NULL REFERENCES thread_poll(id),
+    content VARCHAR(255) NOT NULL,
+    position INT NOT NULL,
+    vote_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    UNIQUE (poll_id, position)
+);
+
+-- is_multiple_choice is copied from the poll so a single choice poll can be limited to one vote per user by an index
+CREATE TABLE thread_poll_vote (
+    id UUID PRIMARY KEY NOT NULL,
+    poll_id UUID NOT NULL REFERENCES thread_poll(id),
+    option_id UUID NOT NULL REFERENCES thread_poll_option(id),
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    is_multiple_choice BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    UNIQUE (option_id, user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS thread_poll_vote_single_choice_unique_index ON thread_poll_vote(poll_id, user_id) WHERE is_multiple_choice = FALSE;
+CREATE INDEX IF NOT EXISTS thread_poll_vote_poll_id_user_id_index ON thread_poll_vote(poll_id, user_id);
@@ -0,0 +1,22 @@
+-- Uploaded files, target_id stays NULL until the upload is attached to a thread or comment
+CREATE TABLE attachment (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    bucket VARCHAR(255) NOT NULL,
+    file_key VARCHAR(500) NOT NULL,
+    url TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INT,
+    height INT,
+    alt_text VARCHAR(500),
+    target_type VARCHAR(100),
+    target_id UUID,
+    position INT NOT NULL DEFAULT 0,
+    attached_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS attachment_target_index ON attachment(target_type, target_id, position) WHERE target_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS attachment_unattached_created_at_index ON attachment(created_at) WHERE target_id IS NULL;
@@ -0,0 +1,6 @@
+-- Uploads are re-encoded and stored under content addressed keys, identical images share the same storage objects
+ALTER TABLE attachment
+    ADD COLUMN content_hash VARCHAR(64),
+    ADD COLUMN variants JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS attachment_content_hash_index ON attachment(content_hash) WHERE content_hash IS NOT NULL;
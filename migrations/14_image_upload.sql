@@ -0,0 +1,19 @@
+-- Direct uploads to the storage, the raw object is verified and processed into an attachment once the client confirms it
+CREATE TABLE image_upload (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    bucket VARCHAR(255) NOT NULL,
+    file_key VARCHAR(500) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    checksum_sha256 VARCHAR(64) NOT NULL,
+    alt_text VARCHAR(500),
+    status VARCHAR(50) NOT NULL,
+    attachment_id UUID,
+    expires_at TIMESTAMPTZ NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS image_upload_pending_expires_at_index ON image_upload(expires_at) WHERE status = 'PENDING';
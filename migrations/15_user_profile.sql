@@ -0,0 +1,8 @@
+ALTER TABLE "user"
+    ADD COLUMN avatar_url TEXT,
+    ADD COLUMN bio VARCHAR(500),
+    ADD COLUMN major VARCHAR(255),
+    ADD COLUMN graduation_year INT,
+    ADD COLUMN profile_visibility VARCHAR(50) NOT NULL DEFAULT 'PUBLIC',
+    ADD COLUMN hide_threads BOOLEAN NOT NULL DEFAULT FALSE;
+
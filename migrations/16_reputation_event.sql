@@ -0,0 +1,72 @@
+-- Append-only ledger of every reputation change, user.reputation_points is the cached sum of its deltas
+CREATE TABLE reputation_event (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    source_type VARCHAR(100) NOT NULL,
+    source_id UUID,
+    actor_id UUID,
+    delta INT NOT NULL,
+    reason VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS reputation_event_user_id_created_at_index ON reputation_event(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS reputation_event_source_index ON reputation_event(source_type, source_id, actor_id);
+
+-- backfill the current votes so they can still be undone, the latest activity of an actor is their current vote
+INSERT INTO reputation_event (id, user_id, source_type, source_id, actor_id, delta, reason, created_at, created_by)
+SELECT gen_random_uuid(), t.user_id, 'THREAD_VOTE', t.id, ta.actor_id,
+       CASE ta.action WHEN 'LIKE' THEN 5 ELSE -5 END,
+       CASE ta.action WHEN 'LIKE' THEN 'LIKE_RECEIVED' ELSE 'DISLIKE_RECEIVED' END,
+       ta.created_at, 'SYSTEM'
+FROM (
+    SELECT DISTINCT ON (thread_id, actor_id) thread_id, actor_id, action, COALESCE(updated_at, created_at) AS created_at
+    FROM thread_activity
+    ORDER BY thread_id, actor_id, created_at DESC
+) ta
+JOIN thread t ON t.id = ta.thread_id
+WHERE ta.action IN ('LIKE', 'DISLIKE');
+
+INSERT INTO reputation_event (id, user_id, source_type, source_id, actor_id, delta, reason, created_at, created_by)
+SELECT gen_random_uuid(), tc.user_id, 'COMMENT_VOTE', tc.id, tca.actor_id,
+       CASE tca.action WHEN 'LIKE' THEN 5 ELSE -5 END,
+       CASE tca.action WHEN 'LIKE' THEN 'LIKE_RECEIVED' ELSE 'DISLIKE_RECEIVED' END,
+       tca.created_at, 'SYSTEM'
+FROM (
+    SELECT DISTINCT ON (thread_comment_id, actor_id) thread_comment_id, actor_id, action, COALESCE(updated_at, created_at) AS created_at
+    FROM thread_comment_activity
+    WHERE thread_comment_reply_id IS NULL
+    ORDER BY thread_comment_id, actor_id, created_at DESC
+) tca
+JOIN thread_comment tc ON tc.id = tca.thread_comment_id
+WHERE tca.action IN ('LIKE', 'DISLIKE');
+
+INSERT INTO reputation_event (id, user_id, source_type, source_id, actor_id, delta, reason, created_at, created_by)
+SELECT gen_random_uuid(), tcr.user_id, 'COMMENT_REPLY_VOTE', tcr.id, tca.actor_id,
+       CASE tca.action WHEN 'LIKE' THEN 5 ELSE -5 END,
+       CASE tca.action WHEN 'LIKE' THEN 'LIKE_RECEIVED' ELSE 'DISLIKE_RECEIVED' END,
+       tca.created_at, 'SYSTEM'
+FROM (
+    SELECT DISTINCT ON (thread_comment_reply_id, actor_id) thread_comment_reply_id, actor_id, action, COALESCE(updated_at, created_at) AS created_at
+    FROM thread_comment_activity
+    WHERE thread_comment_reply_id IS NOT NULL
+    ORDER BY thread_comment_reply_id, actor_id, created_at DESC
+) tca
+JOIN thread_comment_reply tcr ON tcr.id = tca.thread_comment_reply_id
+WHERE tca.action IN ('LIKE', 'DISLIKE');
+
+-- whatever the votes don't explain (signup bonus, drift) is kept as an opening balance so the totals don't change
+INSERT INTO reputation_event (id, user_id, source_type, delta, reason, created_by)
+SELECT gen_random_uuid(), u.id, 'MIGRATION', u.reputation_points - COALESCE(SUM(re.delta), 0), 'OPENING_BALANCE', 'SYSTEM'
+FROM "user" u
+LEFT JOIN reputation_event re ON re.user_id = u.id
+GROUP BY u.id, u.reputation_points
+HAVING u.reputation_points - COALESCE(SUM(re.delta), 0) <> 0;
+
+INSERT INTO permission (id, name, description, created_by) VALUES
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e06', 'MANAGE_REPUTATION', 'Recompute user reputation from the ledger', 'SYSTEM');
+
+INSERT INTO role_permission (id, role_id, permission_id, created_by) VALUES
+-- ADMIN
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e11', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e06', 'SYSTEM');
@@ -0,0 +1,76 @@
+CREATE TABLE badge (
+    id UUID PRIMARY KEY NOT NULL,
+    code VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    icon_url TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS badge_code_index ON badge(code);
+
+-- scope_type and scope_id are NULL for badges earned globally, otherwise they point to the
+-- subthread the badge was earned in
+CREATE TABLE user_badge (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    badge_id UUID NOT NULL REFERENCES badge(id),
+    scope_type VARCHAR(100),
+    scope_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_badge_user_id_index ON user_badge(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS user_badge_earned_index ON user_badge(user_id, badge_id, COALESCE(scope_id, '00000000-0000-0000-0000-000000000000'));
+
+INSERT INTO badge (id, code, name, description, created_by) VALUES
+('3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a01', 'FIRST_THREAD', 'First Thread', 'Posted your first thread', 'SYSTEM'),
+('3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a02', 'LIKES_RECEIVED_100', 'Crowd Favorite', 'Received 100 likes on your threads and comments', 'SYSTEM'),
+('3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a03', 'VERIFIED_STUDENT', 'Verified Student', 'Verified a university email', 'SYSTEM'),
+('3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a04', 'TOP_CONTRIBUTOR', 'Top Contributor', 'Posted the most threads in a subthread', 'SYSTEM'),
+('3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a05', 'HELPFUL_UNIVERSITY_REVIEWER', 'Helpful Reviewer', 'Reviewed your university with its pros and cons', 'SYSTEM');
+
+-- award the badges already earned before the rules existed, without notifying
+INSERT INTO user_badge (id, user_id, badge_id, created_by)
+SELECT gen_random_uuid(), t.user_id, '3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a01', 'SYSTEM'
+FROM thread t
+WHERE t.deleted_at IS NULL
+GROUP BY t.user_id;
+
+INSERT INTO user_badge (id, user_id, badge_id, created_by)
+SELECT gen_random_uuid(), l.user_id, '3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a02', 'SYSTEM'
+FROM (
+    SELECT user_id, like_count FROM thread WHERE deleted_at IS NULL
+    UNION ALL
+    SELECT user_id, like_count FROM thread_comment WHERE deleted_at IS NULL
+    UNION ALL
+    SELECT user_id, like_count FROM thread_comment_reply WHERE deleted_at IS NULL
+) l
+GROUP BY l.user_id
+HAVING SUM(l.like_count) >= 100;
+
+INSERT INTO user_badge (id, user_id, badge_id, created_by)
+SELECT gen_random_uuid(), u.id, '3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a03', 'SYSTEM'
+FROM "user" u
+WHERE u.university_id IS NOT NULL AND u.is_email_verified = TRUE AND u.deleted_at IS NULL;
+
+INSERT INTO user_badge (id, user_id, badge_id, scope_type, scope_id, created_by)
+SELECT gen_random_uuid(), c.user_id, '3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a04', 'SUBTHREAD', c.subthread_id, 'SYSTEM'
+FROM (
+    SELECT user_id, subthread_id, COUNT(*) AS thread_count, MAX(COUNT(*)) OVER (PARTITION BY subthread_id) AS top_count
+    FROM thread
+    WHERE deleted_at IS NULL
+    GROUP BY user_id, subthread_id
+) c
+WHERE c.thread_count >= 10 AND c.thread_count = c.top_count;
+
+INSERT INTO user_badge (id, user_id, badge_id, created_by)
+SELECT gen_random_uuid(), ur.user_id, '3f6a9c2e-1b4d-4e8f-a7c5-2d9e0b1f3a05', 'SYSTEM'
+FROM university_rating ur
+JOIN university_rating_point urp ON urp.university_rating_id = ur.id
+WHERE ur.deleted_at IS NULL
+GROUP BY ur.id, ur.user_id
+HAVING COUNT(*) FILTER (WHERE urp.type = 'PRO') >= 2 AND COUNT(*) FILTER (WHERE urp.type = 'CON') >= 2
+ON CONFLICT DO NOTHING;
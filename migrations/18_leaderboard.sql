@@ -0,0 +1,51 @@
+-- Reputation gained per author, subthread and day from the current votes. The latest activity of an actor is their
+-- current vote and its timestamp is when the points were gained. Votes on deleted content are not counted.
+-- A vote weighs 5 points like constants.DEFAULT_INCREMENT_REPUTATION and DEFAULT_DECREMENT_REPUTATION, the view has to
+-- be recreated when they change. Refreshed periodically by the leaderboard refresher
+CREATE MATERIALIZED VIEW leaderboard_score AS
+SELECT v.user_id,
+       v.subthread_id,
+       v.voted_at::date AS day,
+       SUM(CASE v.action WHEN 'LIKE' THEN 5 ELSE -5 END) AS points,
+       COUNT(*) FILTER (WHERE v.action = 'LIKE') AS like_count,
+       COUNT(*) FILTER (WHERE v.action = 'DISLIKE') AS dislike_count
+FROM (
+    SELECT t.user_id, t.subthread_id, ta.action, ta.voted_at
+    FROM (
+        SELECT DISTINCT ON (thread_id, actor_id) thread_id, action, COALESCE(updated_at, created_at) AS voted_at
+        FROM thread_activity
+        ORDER BY thread_id, actor_id, COALESCE(updated_at, created_at) DESC
+    ) ta
+    JOIN thread t ON t.id = ta.thread_id AND t.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT tc.user_id, t.subthread_id, tca.action, tca.voted_at
+    FROM (
+        SELECT DISTINCT ON (thread_comment_id, actor_id) thread_comment_id, action, COALESCE(updated_at, created_at) AS voted_at
+        FROM thread_comment_activity
+        WHERE thread_comment_reply_id IS NULL
+        ORDER BY thread_comment_id, actor_id, COALESCE(updated_at, created_at) DESC
+    ) tca
+    JOIN thread_comment tc ON tc.id = tca.thread_comment_id AND tc.deleted_at IS NULL
+    JOIN thread t ON t.id = tc.thread_id AND t.deleted_at IS NULL
+
+    UNION ALL
+
+    SELECT tcr.user_id, t.subthread_id, tca.action, tca.voted_at
+    FROM (
+        SELECT DISTINCT ON (thread_comment_reply_id, actor_id) thread_comment_reply_id, thread_id, action, COALESCE(updated_at, created_at) AS voted_at
+        FROM thread_comment_activity
+        WHERE thread_comment_reply_id IS NOT NULL
+        ORDER BY thread_comment_reply_id, actor_id, COALESCE(updated_at, created_at) DESC
+    ) tca
+    JOIN thread_comment_reply tcr ON tcr.id = tca.thread_comment_reply_id AND tcr.deleted_at IS NULL
+    JOIN thread t ON t.id = tca.thread_id AND t.deleted_at IS NULL
+) v
+WHERE v.action IN ('LIKE', 'DISLIKE')
+GROUP BY v.user_id, v.subthread_id, v.voted_at::date;
+
+-- required by REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS leaderboard_score_user_id_subthread_id_day_index ON leaderboard_score(user_id, subthread_id, day);
+CREATE INDEX IF NOT EXISTS leaderboard_score_day_index ON leaderboard_score(day);
+CREATE INDEX IF NOT EXISTS leaderboard_score_subthread_id_day_index ON leaderboard_score(subthread_id, day);
@@ -0,0 +1,85 @@
+-- owner_id is the user who created the subthread, their permissions come from the SUBTHREAD_OWNER role
+ALTER TABLE subthread ADD COLUMN owner_id UUID REFERENCES "user"(id);
+
+-- the creator was previously granted SUBTHREAD_MODERATOR, fall back to the earliest moderator for seeded subthreads
+UPDATE subthread st
+SET owner_id = COALESCE(
+    (SELECT u.id FROM "user" u WHERE u.email = st.created_by LIMIT 1),
+    (SELECT ur.user_id FROM user_role ur
+     WHERE ur.role_id = '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f03' AND ur.scope_id = st.id AND ur.deleted_at IS NULL
+     ORDER BY ur.created_at ASC LIMIT 1)
+);
+
+CREATE TABLE subthread_rule (
+    id UUID PRIMARY KEY NOT NULL,
+    subthread_id UUID NOT NULL REFERENCES subthread(id),
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    position INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100),
+    deleted_at TIMESTAMPTZ,
+    deleted_by VARCHAR(100)
+);
+
+CREATE INDEX IF NOT EXISTS subthread_rule_subthread_id_position_index ON subthread_rule(subthread_id, position) WHERE deleted_at IS NULL;
+
+-- expired_at is NULL for permanent bans, a lifted ban is soft deleted
+CREATE TABLE subthread_ban (
+    id UUID PRIMARY KEY NOT NULL,
+    subthread_id UUID NOT NULL REFERENCES subthread(id),
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    reason VARCHAR(255),
+    expired_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100),
+    deleted_at TIMESTAMPTZ,
+    deleted_by VARCHAR(100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS subthread_ban_active_index ON subthread_ban(subthread_id, user_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE thread ADD COLUMN is_pinned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE thread ADD COLUMN pinned_at TIMESTAMPTZ;
+ALTER TABLE thread ADD COLUMN pinned_by VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS thread_subthread_id_is_pinned_index ON thread(subthread_id) WHERE is_pinned = TRUE AND deleted_at IS NULL;
+
+INSERT INTO role (id, name, description, scope_type, created_by) VALUES
+('8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f05', 'SUBTHREAD_OWNER', 'Own a single subthread and manage its moderators', 'SUBTHREAD', 'SYSTEM');
+
+INSERT INTO permission (id, name, description, created_by) VALUES
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e07', 'DELETE_SUBTHREAD', 'Delete subthreads', 'SYSTEM'),
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e08', 'MANAGE_SUBTHREAD_MODERATOR', 'Add and remove subthread moderators', 'SYSTEM');
+
+-- BAN_USER granted through a scoped role only bans from posting in that subthread
+INSERT INTO role_permission (id, role_id, permission_id, created_by) VALUES
+-- ADMIN
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e12', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e07', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e13', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e08', 'SYSTEM'),
+-- SUBTHREAD_MODERATOR
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e14', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f03', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e02', 'SYSTEM'),
+-- SUBTHREAD_OWNER
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e15', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f05', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e02', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e16', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f05', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e03', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e17', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f05', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e04', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e18', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f05', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e07', 'SYSTEM'),
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e19', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f05', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e08', 'SYSTEM');
+
+-- the owners become SUBTHREAD_OWNER instead of moderators of their subthread
+UPDATE user_role ur
+SET deleted_at = NOW(), deleted_by = 'SYSTEM'
+FROM subthread st
+WHERE ur.role_id = '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f03'
+  AND ur.scope_id = st.id
+  AND ur.user_id = st.owner_id
+  AND ur.deleted_at IS NULL;
+
+INSERT INTO user_role (id, user_id, role_id, scope_type, scope_id, created_by)
+SELECT gen_random_uuid(), st.owner_id, '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f05', 'SUBTHREAD', st.id, 'SYSTEM'
+FROM subthread st
+WHERE st.owner_id IS NOT NULL;
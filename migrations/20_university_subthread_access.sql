@@ -0,0 +1,8 @@
+INSERT INTO permission (id, name, description, created_by) VALUES
+('5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e09', 'ACCESS_ALL_UNIVERSITIES', 'Read and post in the subthreads of every university', 'SYSTEM');
+
+INSERT INTO role_permission (id, role_id, permission_id, created_by) VALUES
+-- ADMIN
+('c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e20', '8a3c1f0e-5d2b-4c1a-9e7f-0b1d2c3e4f01', '5b7e2d1c-3a4f-4e6d-8c9b-1a2b3c4d5e09', 'SYSTEM');
+
+CREATE INDEX IF NOT EXISTS subthread_university_id_index ON subthread(university_id) WHERE is_university_subthread = TRUE;
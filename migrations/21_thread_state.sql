@@ -0,0 +1,21 @@
+-- a locked thread keeps being listed but takes no new comments or votes
+ALTER TABLE thread ADD COLUMN is_locked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE thread ADD COLUMN locked_at TIMESTAMPTZ;
+ALTER TABLE thread ADD COLUMN locked_by VARCHAR(100);
+
+-- an archived thread is read only like a locked thread, threads are archived by the thread archiver after a period of inactivity
+ALTER TABLE thread ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE thread ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE thread ADD COLUMN archived_by VARCHAR(100);
+
+-- last_activity_at is bumped by every new comment and reply
+ALTER TABLE thread ADD COLUMN last_activity_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE thread th
+SET last_activity_at = GREATEST(
+    th.created_at,
+    COALESCE((SELECT MAX(thc.created_at) FROM thread_comment thc WHERE thc.thread_id = th.id), th.created_at),
+    COALESCE((SELECT MAX(thcr.created_at) FROM thread_comment_reply thcr WHERE thcr.thread_id = th.id), th.created_at)
+);
+
+CREATE INDEX IF NOT EXISTS thread_last_activity_at_index ON thread(last_activity_at) WHERE is_archived = FALSE AND deleted_at IS NULL;
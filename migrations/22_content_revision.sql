@@ -0,0 +1,21 @@
+-- every edit of a thread, comment or reply stores the content it replaced, created_by is the editor
+CREATE TABLE content_revision (
+    id UUID PRIMARY KEY NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id UUID NOT NULL,
+    thread_id UUID NOT NULL REFERENCES thread(id),
+    title VARCHAR(100),
+    content VARCHAR(255) NOT NULL,
+    content_summary VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS content_revision_target_index ON content_revision(target_type, target_id, created_at);
+
+ALTER TABLE thread ADD COLUMN edited_at TIMESTAMPTZ;
+ALTER TABLE thread_comment ADD COLUMN edited_at TIMESTAMPTZ;
+ALTER TABLE thread_comment_reply ADD COLUMN edited_at TIMESTAMPTZ;
+
+-- the revisions are visible to the author and the moderators, is_revision_public opens them to everyone
+ALTER TABLE subthread ADD COLUMN is_revision_public BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,32 @@
+CREATE TABLE bookmark_collection (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100),
+    deleted_at TIMESTAMPTZ,
+    deleted_by VARCHAR(100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS bookmark_collection_user_id_name_index ON bookmark_collection(user_id, LOWER(name)) WHERE deleted_at IS NULL;
+
+-- target_id is a thread, comment or reply id, thread_id is the thread of the target. Bookmarks without a collection
+-- are only in the general saved list
+CREATE TABLE bookmark (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    target_type VARCHAR(50) NOT NULL,
+    target_id UUID NOT NULL,
+    thread_id UUID NOT NULL REFERENCES thread(id),
+    collection_id UUID REFERENCES bookmark_collection(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS bookmark_user_id_target_index ON bookmark(user_id, target_type, target_id);
+CREATE INDEX IF NOT EXISTS bookmark_user_id_created_at_index ON bookmark(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS bookmark_collection_id_index ON bookmark(collection_id) WHERE collection_id IS NOT NULL;
@@ -0,0 +1,16 @@
+-- user_id blocked or muted blocked_user_id. Both hide the content of blocked_user_id from user_id and stop their
+-- notifications, a block also stops blocked_user_id from commenting on or replying to user_id
+CREATE TABLE user_block (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    blocked_user_id UUID NOT NULL REFERENCES "user"(id),
+    type VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ,
+    updated_by VARCHAR(100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_block_user_id_blocked_user_id_index ON user_block(user_id, blocked_user_id);
+CREATE INDEX IF NOT EXISTS user_block_user_id_created_at_index ON user_block(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS user_block_blocked_user_id_index ON user_block(blocked_user_id);
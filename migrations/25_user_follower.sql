@@ -0,0 +1,15 @@
+-- follower_id follows user_id, an unfollow deletes the row
+CREATE TABLE user_follower (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id),
+    follower_id UUID NOT NULL REFERENCES "user"(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_follower_user_id_follower_id_index ON user_follower(user_id, follower_id);
+CREATE INDEX IF NOT EXISTS user_follower_user_id_created_at_index ON user_follower(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS user_follower_follower_id_created_at_index ON user_follower(follower_id, created_at DESC, id DESC);
+
+ALTER TABLE "user" ADD COLUMN followers_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "user" ADD COLUMN following_count INTEGER NOT NULL DEFAULT 0;
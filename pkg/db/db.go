@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
@@ -34,3 +35,10 @@ func InitDB(cfg config.Config) *bun.DB {
 
 	return pgdb
 }
+
+// IsUniqueViolation reports whether err was caused by a unique constraint or index of postgres
+func IsUniqueViolation(err error) bool {
+	var pgErr pgdriver.Error
+
+	return errors.As(err, &pgErr) && pgErr.Field('C') == "23505"
+}
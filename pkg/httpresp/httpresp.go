@@ -7,11 +7,13 @@ import (
 	"github.com/samber/oops"
 	"net/http"
 	"reflect"
+	"strconv"
 	"time"
 )
 
 const (
 	StatusCodeCtxKey = "httpStatusCode"
+	RetryAfterCtxKey = "retryAfterSecs"
 )
 
 type Meta struct {
@@ -61,6 +63,11 @@ func HttpRespError(c *gin.Context, err error) {
 		if exists {
 			statusCode = sc.(int)
 		}
+
+		ra, exists := errCtx[RetryAfterCtxKey]
+		if exists {
+			c.Header("Retry-After", strconv.Itoa(ra.(int)))
+		}
 	}
 
 	jsonErrResp := &HTTPErrResp{
@@ -0,0 +1,145 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const (
+	exifOrientationTag = 0x0112
+
+	orientationNormal = 1
+)
+
+// readJPEGOrientation returns the EXIF orientation of a jpeg, it falls back to the normal orientation
+// when the image has no EXIF segment or the segment is malformed
+func readJPEGOrientation(data []byte) int {
+
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return orientationNormal
+	}
+
+	offset := 2
+
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return orientationNormal
+		}
+
+		marker := data[offset+1]
+
+		// Start of scan, the metadata segments always come before the image data
+		if marker == 0xDA {
+			return orientationNormal
+		}
+
+		segmentLen := int(binary.BigEndian.Uint16(data[offset+2 : offset+4]))
+		if segmentLen < 2 || offset+2+segmentLen > len(data) {
+			return orientationNormal
+		}
+
+		segment := data[offset+4 : offset+2+segmentLen]
+
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return readTIFFOrientation(segment[6:])
+		}
+
+		offset += 2 + segmentLen
+	}
+
+	return orientationNormal
+}
+
+func readTIFFOrientation(tiff []byte) int {
+
+	if len(tiff) < 8 {
+		return orientationNormal
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset+2 > len(tiff) {
+		return orientationNormal
+	}
+
+	entryCount := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+
+	for i := 0; i < entryCount; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return orientationNormal
+		}
+
+		if order.Uint16(tiff[entry:entry+2]) != exifOrientationTag {
+			continue
+		}
+
+		orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if orientation < 1 || orientation > 8 {
+			return orientationNormal
+		}
+
+		return orientation
+	}
+
+	return orientationNormal
+}
+
+// applyOrientation transforms img so it is displayed upright once the EXIF orientation is stripped
+func applyOrientation(img *image.RGBA, orientation int) *image.RGBA {
+
+	if orientation <= orientationNormal || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+
+	dstW, dstH := w, h
+	// Orientations 5 to 8 swap the width and the height
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+
+			srcOffset := img.PixOffset(sx, sy)
+			dstOffset := dst.PixOffset(x, y)
+
+			copy(dst.Pix[dstOffset:dstOffset+4], img.Pix[srcOffset:srcOffset+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,210 @@
+package imageproc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gabriel-vasile/mimetype"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"sort"
+)
+
+const (
+	VariantOriginal = "original"
+
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeGIF  = "image/gif"
+)
+
+var (
+	ErrUnsupportedType  = errors.New("unsupported image type")
+	ErrInvalidImage     = errors.New("invalid image")
+	ErrDimensionTooLow  = errors.New("image dimension is too small")
+	ErrDimensionTooHigh = errors.New("image dimension is too large")
+)
+
+// allowedContentTypes are the sniffed types that can be decoded by the standard library
+var allowedContentTypes = map[string]bool{
+	ContentTypeJPEG: true,
+	ContentTypePNG:  true,
+	ContentTypeGIF:  true,
+}
+
+type Options struct {
+	MinWidth       int
+	MinHeight      int
+	MaxWidth       int
+	MaxHeight      int
+	MaxPixels      int
+	JPEGQuality    int
+	ThumbnailSizes []int
+}
+
+type Variant struct {
+	Name        string
+	Width       int
+	Height      int
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+type Result struct {
+	// Hash is the hex encoded sha256 of the re-encoded original, it is stable for the same source image and options
+	Hash        string
+	ContentType string
+	// Variants starts with the original followed by the thumbnails from the smallest size
+	Variants []Variant
+}
+
+// Process sniffs and validates the image, then re-encodes it with its EXIF orientation applied.
+// Re-encoding drops every metadata segment of the source such as EXIF GPS tags. A thumbnail is generated for
+// each size smaller than the longest side of the image, sizes bounding the longest side of the thumbnail.
+// Animated GIFs are flattened to their first frame
+func Process(data []byte, opts Options) (Result, error) {
+
+	var result Result
+
+	contentType := DetectContentType(data)
+	if !IsAllowedContentType(contentType) {
+		return result, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	// The header is checked before decoding so oversized images are rejected without allocating their pixels
+	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	err = validateDimensions(imgCfg.Width, imgCfg.Height, opts)
+	if err != nil {
+		return result, err
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	img := toRGBA(src)
+
+	if contentType == ContentTypeJPEG {
+		img = applyOrientation(img, readJPEGOrientation(data))
+	}
+
+	// Images with transparency are kept as png, everything else is served as jpeg
+	encode := func(img *image.RGBA) ([]byte, error) {
+		var buf bytes.Buffer
+
+		err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: opts.JPEGQuality})
+
+		return buf.Bytes(), err
+	}
+
+	result.ContentType = ContentTypeJPEG
+	extension := ".jpg"
+
+	if !img.Opaque() {
+		encode = func(img *image.RGBA) ([]byte, error) {
+			var buf bytes.Buffer
+
+			err := png.Encode(&buf, img)
+
+			return buf.Bytes(), err
+		}
+
+		result.ContentType = ContentTypePNG
+		extension = ".png"
+	}
+
+	originalData, err := encode(img)
+	if err != nil {
+		return result, err
+	}
+
+	hash := sha256.Sum256(originalData)
+	result.Hash = hex.EncodeToString(hash[:])
+
+	bounds := img.Bounds()
+
+	result.Variants = append(result.Variants, Variant{
+		Name:        VariantOriginal,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		ContentType: result.ContentType,
+		Extension:   extension,
+		Data:        originalData,
+	})
+
+	sizes := append([]int(nil), opts.ThumbnailSizes...)
+	sort.Ints(sizes)
+
+	for _, size := range sizes {
+		if size <= 0 || size >= max(bounds.Dx(), bounds.Dy()) {
+			continue
+		}
+
+		thumbnail := resize(img, size)
+
+		thumbnailData, err := encode(thumbnail)
+		if err != nil {
+			return result, err
+		}
+
+		result.Variants = append(result.Variants, Variant{
+			Name:        fmt.Sprintf("%d", size),
+			Width:       thumbnail.Bounds().Dx(),
+			Height:      thumbnail.Bounds().Dy(),
+			ContentType: result.ContentType,
+			Extension:   extension,
+			Data:        thumbnailData,
+		})
+	}
+
+	return result, nil
+}
+
+// DetectContentType sniffs the content type from the magic bytes, the declared type of the client is never trusted
+func DetectContentType(data []byte) string {
+
+	return mimetype.Detect(data).String()
+}
+
+func IsAllowedContentType(contentType string) bool {
+
+	return allowedContentTypes[contentType]
+}
+
+func validateDimensions(width int, height int, opts Options) error {
+
+	if width <= 0 || height <= 0 || width < opts.MinWidth || height < opts.MinHeight {
+		return fmt.Errorf("%w: %dx%d", ErrDimensionTooLow, width, height)
+	}
+
+	if (opts.MaxWidth > 0 && width > opts.MaxWidth) || (opts.MaxHeight > 0 && height > opts.MaxHeight) {
+		return fmt.Errorf("%w: %dx%d", ErrDimensionTooHigh, width, height)
+	}
+
+	if opts.MaxPixels > 0 && width*height > opts.MaxPixels {
+		return fmt.Errorf("%w: %dx%d", ErrDimensionTooHigh, width, height)
+	}
+
+	return nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+
+	return dst
+}
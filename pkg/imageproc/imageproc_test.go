@@ -0,0 +1,307 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// gpsMarker stands in for the GPS tags of a real EXIF segment, it must not survive the re-encoding
+const gpsMarker = "GPSLatitude"
+
+func newTestImage(width int, height int, alpha uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: alpha})
+		}
+	}
+
+	return img
+}
+
+func encodeTestJPEG(t *testing.T, width int, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	err := jpeg.Encode(&buf, newTestImage(width, height, 255), nil)
+	if err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func encodeTestPNG(t *testing.T, width int, height int, alpha uint8) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, newTestImage(width, height, alpha))
+	if err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// withEXIF inserts an APP1 segment holding the orientation and the gps marker right after the start of image
+func withEXIF(data []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	binary.Write(&tiff, binary.LittleEndian, uint16(exifOrientationTag))
+	binary.Write(&tiff, binary.LittleEndian, uint16(3))
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, orientation)
+	binary.Write(&tiff, binary.LittleEndian, uint16(0))
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+	tiff.WriteString(gpsMarker)
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	var out bytes.Buffer
+
+	out.Write(data[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(data[2:])
+
+	return out.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	opts := Options{
+		MinWidth:    4,
+		MinHeight:   4,
+		MaxWidth:    200,
+		MaxHeight:   200,
+		MaxPixels:   20000,
+		JPEGQuality: 90,
+	}
+
+	tests := []struct {
+		name            string
+		data            func(t *testing.T) []byte
+		opts            Options
+		wantErr         error
+		wantContentType string
+		wantWidth       int
+		wantHeight      int
+	}{
+		{
+			name:            "jpeg is re-encoded as jpeg",
+			data:            func(t *testing.T) []byte { return encodeTestJPEG(t, 40, 20) },
+			opts:            opts,
+			wantContentType: ContentTypeJPEG,
+			wantWidth:       40,
+			wantHeight:      20,
+		},
+		{
+			name:            "exif is stripped from a jpeg",
+			data:            func(t *testing.T) []byte { return withEXIF(encodeTestJPEG(t, 40, 20), orientationNormal) },
+			opts:            opts,
+			wantContentType: ContentTypeJPEG,
+			wantWidth:       40,
+			wantHeight:      20,
+		},
+		{
+			name:            "exif orientation is applied before it is stripped",
+			data:            func(t *testing.T) []byte { return withEXIF(encodeTestJPEG(t, 40, 20), 6) },
+			opts:            opts,
+			wantContentType: ContentTypeJPEG,
+			wantWidth:       20,
+			wantHeight:      40,
+		},
+		{
+			name:            "opaque png is re-encoded as jpeg",
+			data:            func(t *testing.T) []byte { return encodeTestPNG(t, 40, 20, 255) },
+			opts:            opts,
+			wantContentType: ContentTypeJPEG,
+			wantWidth:       40,
+			wantHeight:      20,
+		},
+		{
+			name:            "transparent png stays png",
+			data:            func(t *testing.T) []byte { return encodeTestPNG(t, 40, 20, 100) },
+			opts:            opts,
+			wantContentType: ContentTypePNG,
+			wantWidth:       40,
+			wantHeight:      20,
+		},
+		{
+			name:    "unsupported type is rejected",
+			data:    func(t *testing.T) []byte { return []byte("not an image at all") },
+			opts:    opts,
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:    "width under the minimum is rejected",
+			data:    func(t *testing.T) []byte { return encodeTestJPEG(t, 2, 20) },
+			opts:    opts,
+			wantErr: ErrDimensionTooLow,
+		},
+		{
+			name:    "height under the minimum is rejected",
+			data:    func(t *testing.T) []byte { return encodeTestJPEG(t, 20, 2) },
+			opts:    opts,
+			wantErr: ErrDimensionTooLow,
+		},
+		{
+			name:    "width over the maximum is rejected",
+			data:    func(t *testing.T) []byte { return encodeTestJPEG(t, 201, 20) },
+			opts:    opts,
+			wantErr: ErrDimensionTooHigh,
+		},
+		{
+			name:    "height over the maximum is rejected",
+			data:    func(t *testing.T) []byte { return encodeTestJPEG(t, 20, 201) },
+			opts:    opts,
+			wantErr: ErrDimensionTooHigh,
+		},
+		{
+			name:    "pixels over the cap are rejected even within the side limits",
+			data:    func(t *testing.T) []byte { return encodeTestJPEG(t, 150, 150) },
+			opts:    opts,
+			wantErr: ErrDimensionTooHigh,
+		},
+		{
+			name: "zero limits are not enforced",
+			data: func(t *testing.T) []byte { return encodeTestJPEG(t, 300, 300) },
+			opts: Options{
+				JPEGQuality: 90,
+			},
+			wantContentType: ContentTypeJPEG,
+			wantWidth:       300,
+			wantHeight:      300,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Process(tt.data(t), tt.opts)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Process() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+
+			if result.ContentType != tt.wantContentType {
+				t.Errorf("Process() content type = %s, want %s", result.ContentType, tt.wantContentType)
+			}
+
+			if len(result.Variants) == 0 {
+				t.Fatalf("Process() returned no variants")
+			}
+
+			original := result.Variants[0]
+
+			if original.Name != VariantOriginal {
+				t.Errorf("Process() first variant = %s, want %s", original.Name, VariantOriginal)
+			}
+
+			if original.Width != tt.wantWidth || original.Height != tt.wantHeight {
+				t.Errorf("Process() original = %dx%d, want %dx%d", original.Width, original.Height, tt.wantWidth, tt.wantHeight)
+			}
+
+			if bytes.Contains(original.Data, []byte("Exif")) || bytes.Contains(original.Data, []byte(gpsMarker)) {
+				t.Errorf("Process() kept the exif segment of the source")
+			}
+
+			if len(result.Hash) != 64 {
+				t.Errorf("Process() hash = %q, want a hex encoded sha256", result.Hash)
+			}
+		})
+	}
+}
+
+func TestProcessThumbnails(t *testing.T) {
+	tests := []struct {
+		name           string
+		thumbnailSizes []int
+		wantVariants   []string
+	}{
+		{
+			name:         "no thumbnail sizes keeps the original only",
+			wantVariants: []string{VariantOriginal},
+		},
+		{
+			name:           "thumbnails are sorted from the smallest size",
+			thumbnailSizes: []int{16, 8},
+			wantVariants:   []string{VariantOriginal, "8", "16"},
+		},
+		{
+			name:           "sizes not smaller than the longest side are skipped",
+			thumbnailSizes: []int{8, 40, 100},
+			wantVariants:   []string{VariantOriginal, "8"},
+		},
+		{
+			name:           "non positive sizes are skipped",
+			thumbnailSizes: []int{0, -8, 8},
+			wantVariants:   []string{VariantOriginal, "8"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Process(encodeTestJPEG(t, 40, 20), Options{
+				JPEGQuality:    90,
+				ThumbnailSizes: tt.thumbnailSizes,
+			})
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+
+			if len(result.Variants) != len(tt.wantVariants) {
+				t.Fatalf("Process() returned %d variants, want %d", len(result.Variants), len(tt.wantVariants))
+			}
+
+			for i, v := range result.Variants {
+				if v.Name != tt.wantVariants[i] {
+					t.Errorf("Process() variant %d = %s, want %s", i, v.Name, tt.wantVariants[i])
+				}
+
+				if i > 0 && max(v.Width, v.Height) > max(result.Variants[0].Width, result.Variants[0].Height) {
+					t.Errorf("Process() thumbnail %s = %dx%d is larger than the original", v.Name, v.Width, v.Height)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessHashIsStable(t *testing.T) {
+	data := encodeTestJPEG(t, 40, 20)
+	opts := Options{JPEGQuality: 90}
+
+	first, err := Process(data, opts)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	second, err := Process(data, opts)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if first.Hash != second.Hash {
+		t.Errorf("Process() hash = %s then %s, want the same hash for the same image", first.Hash, second.Hash)
+	}
+}
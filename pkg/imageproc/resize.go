@@ -0,0 +1,55 @@
+package imageproc
+
+import (
+	"image"
+)
+
+// resize scales img down so its longest side equals size. Each destination pixel averages the block of
+// source pixels it covers, which is enough for downscaling without the aliasing of nearest neighbour
+func resize(img *image.RGBA, size int) *image.RGBA {
+
+	srcW, srcH := img.Bounds().Dx(), img.Bounds().Dy()
+
+	dstW, dstH := size, size
+	if srcW >= srcH {
+		dstH = max(1, srcH*size/srcW)
+	} else {
+		dstW = max(1, srcW*size/srcH)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		sy0 := y * srcH / dstH
+		sy1 := max((y+1)*srcH/dstH, sy0+1)
+
+		for x := 0; x < dstW; x++ {
+			sx0 := x * srcW / dstW
+			sx1 := max((x+1)*srcW/dstW, sx0+1)
+
+			var r, g, b, a, n uint32
+
+			for sy := sy0; sy < sy1; sy++ {
+				offset := img.PixOffset(sx0, sy)
+
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint32(img.Pix[offset])
+					g += uint32(img.Pix[offset+1])
+					b += uint32(img.Pix[offset+2])
+					a += uint32(img.Pix[offset+3])
+					n++
+
+					offset += 4
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
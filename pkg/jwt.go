@@ -1,23 +1,32 @@
 package pkg
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
 	"github.com/andibalo/meowhasiswa-be/internal/response"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/samber/oops"
 	"github.com/spf13/viper"
 	"log"
 	"net/http"
+	"time"
 )
 
-func GenerateToken(user *model.User) (tokenString string, err error) {
+func GenerateToken(user *model.User, sessionID string, expiredAt time.Time) (tokenString string, err error) {
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"id":       user.ID,
 		"username": user.Username,
 		"email":    user.Email,
 		"role":     user.Role,
+		"sid":      sessionID,
+		"jti":      uuid.NewString(),
+		"iat":      time.Now().Unix(),
+		"exp":      expiredAt.Unix(),
 	})
 
 	tokenString, err = token.SignedString([]byte(viper.GetString("JWT_SECRET")))
@@ -29,3 +38,21 @@ func GenerateToken(user *model.User) (tokenString string, err error) {
 
 	return tokenString, nil
 }
+
+// GenerateRefreshToken returns an opaque random token, only its hash should be persisted
+func GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
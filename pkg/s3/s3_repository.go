@@ -2,18 +2,27 @@ package s3
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
 	"github.com/andibalo/meowhasiswa-be/internal/model"
 	"github.com/andibalo/meowhasiswa-be/pkg"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"io"
+	"strings"
+	"time"
 )
 
 type S3Repository struct {
-	cfg      config.Config
-	client   *s3.Client
-	uploader *manager.Uploader
+	cfg           config.Config
+	client        *s3.Client
+	uploader      *manager.Uploader
+	presignClient *s3.PresignClient
 }
 
 func NewS3Repository(cfg config.Config, client *s3.Client) *S3Repository {
@@ -21,9 +30,10 @@ func NewS3Repository(cfg config.Config, client *s3.Client) *S3Repository {
 	uploader := manager.NewUploader(client)
 
 	return &S3Repository{
-		cfg:      cfg,
-		client:   client,
-		uploader: uploader,
+		cfg:           cfg,
+		client:        client,
+		uploader:      uploader,
+		presignClient: s3.NewPresignClient(client),
 	}
 }
 
@@ -31,11 +41,17 @@ func (r *S3Repository) Upload(ctx context.Context, uploadFileData model.UploadFi
 
 	var resp model.UploadFileOutputDTO
 
-	uploadResp, err := r.uploader.Upload(ctx, &s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Bucket: aws.String(uploadFileData.Bucket),
 		Key:    aws.String(uploadFileData.Name),
 		Body:   uploadFileData.File,
-	})
+	}
+
+	if uploadFileData.ContentType != "" {
+		input.ContentType = aws.String(uploadFileData.ContentType)
+	}
+
+	uploadResp, err := r.uploader.Upload(ctx, input)
 
 	if err != nil {
 		return resp, err
@@ -53,3 +69,127 @@ func (r *S3Repository) Upload(ctx context.Context, uploadFileData model.UploadFi
 
 	return resp, nil
 }
+
+func (r *S3Repository) Delete(ctx context.Context, bucket string, name string) error {
+
+	_, err := r.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *S3Repository) Stat(ctx context.Context, bucket string, name string) (model.FileInfoDTO, error) {
+
+	var resp model.FileInfoDTO
+
+	headResp, err := r.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return resp, mapNotFoundErr(err)
+	}
+
+	resp = model.FileInfoDTO{
+		Size:        aws.ToInt64(headResp.ContentLength),
+		ContentType: aws.ToString(headResp.ContentType),
+	}
+
+	return resp, nil
+}
+
+func (r *S3Repository) Download(ctx context.Context, bucket string, name string) (io.ReadCloser, error) {
+
+	getResp, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return nil, mapNotFoundErr(err)
+	}
+
+	return getResp.Body, nil
+}
+
+func (r *S3Repository) PresignUpload(ctx context.Context, bucket string, name string, contentType string, sizeBytes int64, checksumSHA256 string, expiry time.Duration) (model.PresignedURLDTO, error) {
+
+	var resp model.PresignedURLDTO
+
+	checksum, err := hex.DecodeString(checksumSHA256)
+	if err != nil {
+		return resp, err
+	}
+
+	// Both are signed so S3 rejects any other body, the checksum header is the base64 of the raw digest
+	input := &s3.PutObjectInput{
+		Bucket:         aws.String(bucket),
+		Key:            aws.String(name),
+		ContentLength:  aws.Int64(sizeBytes),
+		ChecksumSHA256: aws.String(base64.StdEncoding.EncodeToString(checksum)),
+	}
+
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	presignResp, err := r.presignClient.PresignPutObject(ctx, input, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return resp, err
+	}
+
+	return toPresignedURLDTO(presignResp, expiry), nil
+}
+
+func (r *S3Repository) PresignDownload(ctx context.Context, bucket string, name string, expiry time.Duration) (model.PresignedURLDTO, error) {
+
+	var resp model.PresignedURLDTO
+
+	presignResp, err := r.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(name),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return resp, err
+	}
+
+	return toPresignedURLDTO(presignResp, expiry), nil
+}
+
+// toPresignedURLDTO keeps the signed headers the client has to send, the host header is set by the http client itself
+func toPresignedURLDTO(req *v4.PresignedHTTPRequest, expiry time.Duration) model.PresignedURLDTO {
+
+	headers := map[string]string{}
+
+	for k := range req.SignedHeader {
+		if strings.EqualFold(k, "Host") {
+			continue
+		}
+
+		headers[k] = req.SignedHeader.Get(k)
+	}
+
+	return model.PresignedURLDTO{
+		URL:       req.URL,
+		Method:    req.Method,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expiry),
+	}
+}
+
+// mapNotFoundErr maps the missing object errors, HeadObject has no body so it reports NotFound instead of NoSuchKey
+func mapNotFoundErr(err error) error {
+
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return model.ErrFileNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"errors"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+type localStore struct {
+	dir string
+}
+
+// NewLocalRepository stores the files under the configured local directory, one sub directory per bucket
+func NewLocalRepository(cfg config.Config) (*Repository, error) {
+
+	dir := cfg.GetStorageCfg().LocalDir
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRepository(cfg, &localStore{dir: dir}), nil
+}
+
+// write stores the file through a temporary file so a reader never sees a partially written object
+func (s *localStore) write(bucket string, key string, r io.Reader, contentType string) (int64, error) {
+
+	filePath := s.filePath(bucket, key)
+
+	err := os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return n, err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return n, err
+	}
+
+	return n, os.Rename(tmp.Name(), filePath)
+}
+
+// read infers the content type from the key extension since the local disk keeps no object metadata
+func (s *localStore) read(bucket string, key string) (object, error) {
+
+	f, err := os.Open(s.filePath(bucket, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return object{}, model.ErrFileNotFound
+	}
+
+	if err != nil {
+		return object{}, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return object{}, err
+	}
+
+	if info.IsDir() {
+		f.Close()
+		return object{}, model.ErrFileNotFound
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return object{
+		Body:        f,
+		Size:        info.Size(),
+		ContentType: contentType,
+	}, nil
+}
+
+func (s *localStore) remove(bucket string, key string) error {
+
+	err := os.Remove(s.filePath(bucket, key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *localStore) filePath(bucket string, key string) string {
+
+	return filepath.Join(s.dir, bucket, filepath.FromSlash(key))
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"bytes"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"io"
+	"sync"
+)
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+}
+
+type memoryStore struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+// NewMemoryRepository keeps the files in memory, they are lost when the process exits
+func NewMemoryRepository(cfg config.Config) *Repository {
+
+	return newRepository(cfg, &memoryStore{
+		objects: map[string]memoryObject{},
+	})
+}
+
+func (s *memoryStore) write(bucket string, key string, r io.Reader, contentType string) (int64, error) {
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[bucket+"/"+key] = memoryObject{
+		data:        data,
+		contentType: contentType,
+	}
+
+	return int64(len(data)), nil
+}
+
+func (s *memoryStore) read(bucket string, key string) (object, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	obj, ok := s.objects[bucket+"/"+key]
+	if !ok {
+		return object{}, model.ErrFileNotFound
+	}
+
+	contentType := obj.contentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return object{
+		Body:        io.NopCloser(bytes.NewReader(obj.data)),
+		Size:        int64(len(obj.data)),
+		ContentType: contentType,
+	}, nil
+}
+
+func (s *memoryStore) remove(bucket string, key string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, bucket+"/"+key)
+
+	return nil
+}
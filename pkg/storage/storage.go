@@ -0,0 +1,341 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/model"
+	"github.com/andibalo/meowhasiswa-be/internal/response"
+	"github.com/andibalo/meowhasiswa-be/pkg/apperr"
+	"github.com/andibalo/meowhasiswa-be/pkg/httpresp"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const FilesRoutePrefix = "/files"
+
+var ErrInvalidKey = errors.New("invalid object key")
+
+type object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// objectStore keeps the bytes of the objects, the repository handles urls, signatures and the file routes on top of it
+type objectStore interface {
+	write(bucket string, key string, r io.Reader, contentType string) (int64, error)
+	read(bucket string, key string) (object, error)
+	remove(bucket string, key string) error
+}
+
+// Repository is a FileRepository that stores files on the local disk or in memory and serves them under
+// FilesRoutePrefix. It is meant for development and tests where S3 is not available
+type Repository struct {
+	cfg   config.Config
+	store objectStore
+}
+
+func newRepository(cfg config.Config, store objectStore) *Repository {
+
+	return &Repository{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+func (r *Repository) Upload(ctx context.Context, uploadFileData model.UploadFileDTO) (model.UploadFileOutputDTO, error) {
+
+	var resp model.UploadFileOutputDTO
+
+	err := validateObjectPath(uploadFileData.Bucket, uploadFileData.Name)
+	if err != nil {
+		return resp, err
+	}
+
+	// The md5 of the content mirrors the ETag S3 returns for single part uploads
+	hash := md5.New()
+
+	_, err = r.store.write(uploadFileData.Bucket, uploadFileData.Name, io.TeeReader(uploadFileData.File, hash), uploadFileData.ContentType)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = model.UploadFileOutputDTO{
+		Name:        uploadFileData.Name,
+		URL:         r.objectURL(uploadFileData.Bucket, uploadFileData.Name, nil),
+		ETag:        hex.EncodeToString(hash.Sum(nil)),
+		MegaBytes:   uploadFileData.MegaBytes,
+		ContentType: uploadFileData.ContentType,
+		Bucket:      uploadFileData.Bucket,
+	}
+
+	return resp, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, bucket string, name string) error {
+
+	err := validateObjectPath(bucket, name)
+	if err != nil {
+		return err
+	}
+
+	return r.store.remove(bucket, name)
+}
+
+func (r *Repository) Stat(ctx context.Context, bucket string, name string) (model.FileInfoDTO, error) {
+
+	var resp model.FileInfoDTO
+
+	err := validateObjectPath(bucket, name)
+	if err != nil {
+		return resp, err
+	}
+
+	obj, err := r.store.read(bucket, name)
+	if err != nil {
+		return resp, err
+	}
+
+	defer obj.Body.Close()
+
+	resp = model.FileInfoDTO{
+		Size:        obj.Size,
+		ContentType: obj.ContentType,
+	}
+
+	return resp, nil
+}
+
+func (r *Repository) Download(ctx context.Context, bucket string, name string) (io.ReadCloser, error) {
+
+	err := validateObjectPath(bucket, name)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := r.store.read(bucket, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj.Body, nil
+}
+
+func (r *Repository) PresignUpload(ctx context.Context, bucket string, name string, contentType string, sizeBytes int64, checksumSHA256 string, expiry time.Duration) (model.PresignedURLDTO, error) {
+
+	return r.presign(http.MethodPut, bucket, name, contentType, strconv.FormatInt(sizeBytes, 10), checksumSHA256, expiry)
+}
+
+func (r *Repository) PresignDownload(ctx context.Context, bucket string, name string, expiry time.Duration) (model.PresignedURLDTO, error) {
+
+	return r.presign(http.MethodGet, bucket, name, "", "", "", expiry)
+}
+
+// presign signs the size and checksum along with the request, they are sent back as query params so putFile can enforce them
+func (r *Repository) presign(method string, bucket string, name string, contentType string, size string, checksum string, expiry time.Duration) (model.PresignedURLDTO, error) {
+
+	var resp model.PresignedURLDTO
+
+	err := validateObjectPath(bucket, name)
+	if err != nil {
+		return resp, err
+	}
+
+	expiresAt := time.Now().Add(expiry)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", r.sign(method, bucket, name, contentType, size, checksum, expires))
+
+	if size != "" {
+		query.Set("size", size)
+	}
+
+	if checksum != "" {
+		query.Set("checksum", checksum)
+	}
+
+	resp = model.PresignedURLDTO{
+		URL:       r.objectURL(bucket, name, query),
+		Method:    method,
+		Headers:   map[string]string{},
+		ExpiresAt: expiresAt,
+	}
+
+	if contentType != "" {
+		resp.Headers["Content-Type"] = contentType
+	}
+
+	return resp, nil
+}
+
+// AddRoutes serves the stored files and accepts the presigned uploads
+func (r *Repository) AddRoutes(g *gin.Engine) {
+	fr := g.Group(FilesRoutePrefix)
+
+	fr.GET("/:bucket/*key", r.getFile)
+	fr.PUT("/:bucket/*key", r.putFile)
+}
+
+// getFile serves any stored file, a presigned download is only checked when the signature is present since
+// the local backends have no private objects
+func (r *Repository) getFile(c *gin.Context) {
+
+	bucket, key := c.Param("bucket"), strings.TrimPrefix(c.Param("key"), "/")
+
+	if c.Query("signature") != "" && !r.isValidSignature(c, http.MethodGet, bucket, key, "") {
+		httpresp.HttpRespError(c, oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf(apperr.ErrPermissionDenied))
+		return
+	}
+
+	obj, err := r.read(bucket, key)
+	if err != nil {
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	defer obj.Body.Close()
+
+	c.DataFromReader(http.StatusOK, obj.Size, obj.ContentType, obj.Body, nil)
+}
+
+func (r *Repository) putFile(c *gin.Context) {
+
+	bucket, key := c.Param("bucket"), strings.TrimPrefix(c.Param("key"), "/")
+	contentType := c.GetHeader("Content-Type")
+
+	if !r.isValidSignature(c, http.MethodPut, bucket, key, contentType) {
+		httpresp.HttpRespError(c, oops.Code(response.Forbidden.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusForbidden).Errorf(apperr.ErrPermissionDenied))
+		return
+	}
+
+	err := validateObjectPath(bucket, key)
+	if err != nil {
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest))
+		return
+	}
+
+	maxFileSize := int64(r.cfg.GetStorageCfg().DirectUploadMaxSizeMB * 1024 * 1024)
+
+	size, err := strconv.ParseInt(c.Query("size"), 10, 64)
+	if err != nil || size > maxFileSize || c.Request.ContentLength != size {
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("Content length does not match the presigned size"))
+		return
+	}
+
+	hash := md5.New()
+	checksum := sha256.New()
+
+	written, err := r.store.write(bucket, key, io.TeeReader(http.MaxBytesReader(c.Writer, c.Request.Body, size), io.MultiWriter(hash, checksum)), contentType)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusRequestEntityTooLarge).Errorf("File size exceeds %d mb", r.cfg.GetStorageCfg().DirectUploadMaxSizeMB))
+			return
+		}
+
+		httpresp.HttpRespError(c, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError))
+		return
+	}
+
+	if written != size || hex.EncodeToString(checksum.Sum(nil)) != c.Query("checksum") {
+		r.store.remove(bucket, key)
+
+		httpresp.HttpRespError(c, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf("File does not match the presigned checksum"))
+		return
+	}
+
+	c.Header("ETag", hex.EncodeToString(hash.Sum(nil)))
+	c.Status(http.StatusOK)
+}
+
+func (r *Repository) read(bucket string, key string) (object, error) {
+
+	err := validateObjectPath(bucket, key)
+	if err != nil {
+		return object{}, oops.Code(response.BadRequest.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusBadRequest).Errorf(apperr.ErrBadRequest)
+	}
+
+	obj, err := r.store.read(bucket, key)
+	if errors.Is(err, model.ErrFileNotFound) {
+		return obj, oops.Code(response.NotFound.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusNotFound).Errorf(apperr.ErrNotFound)
+	}
+
+	if err != nil {
+		return obj, oops.Code(response.ServerError.AsString()).With(httpresp.StatusCodeCtxKey, http.StatusInternalServerError).Errorf(apperr.ErrInternalServerError)
+	}
+
+	return obj, nil
+}
+
+func (r *Repository) isValidSignature(c *gin.Context, method string, bucket string, key string, contentType string) bool {
+
+	expires := c.Query("expires")
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	expected := r.sign(method, bucket, key, contentType, c.Query("size"), c.Query("checksum"), expires)
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(c.Query("signature"))) == 1
+}
+
+func (r *Repository) sign(method string, bucket string, key string, contentType string, size string, checksum string, expires string) string {
+
+	mac := hmac.New(sha256.New, []byte(r.cfg.GetStorageCfg().SigningSecret))
+	mac.Write([]byte(strings.Join([]string{method, bucket, key, contentType, size, checksum, expires}, "\n")))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (r *Repository) objectURL(bucket string, key string, query url.Values) string {
+
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+
+	u := fmt.Sprintf("%s%s/%s/%s", strings.TrimSuffix(r.cfg.GetStorageCfg().PublicBaseURL, "/"), FilesRoutePrefix, url.PathEscape(bucket), strings.Join(segments, "/"))
+
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	return u
+}
+
+// validateObjectPath rejects the keys that could escape the bucket once mapped to a file path
+func validateObjectPath(bucket string, key string) error {
+
+	if bucket == "" || bucket == "." || bucket == ".." || strings.ContainsAny(bucket, "/\\") {
+		return ErrInvalidKey
+	}
+
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
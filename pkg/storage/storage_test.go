@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const testChecksum = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+func newTestRepository(secret string) *Repository {
+	return NewMemoryRepository(&config.AppConfig{
+		Storage: config.Storage{
+			PublicBaseURL: "http://localhost:8080",
+			SigningSecret: secret,
+		},
+	})
+}
+
+func TestValidateObjectPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		bucket  string
+		key     string
+		wantErr bool
+	}{
+		{name: "plain key", bucket: "bucket", key: "file.jpg"},
+		{name: "nested key", bucket: "bucket", key: "images/abc/original.jpg"},
+		{name: "dots inside a segment", bucket: "bucket", key: "images/a..b/file.jpg"},
+		{name: "empty bucket", bucket: "", key: "file.jpg", wantErr: true},
+		{name: "dot bucket", bucket: ".", key: "file.jpg", wantErr: true},
+		{name: "parent bucket", bucket: "..", key: "file.jpg", wantErr: true},
+		{name: "bucket with a slash", bucket: "a/b", key: "file.jpg", wantErr: true},
+		{name: "bucket with a backslash", bucket: "a\\b", key: "file.jpg", wantErr: true},
+		{name: "empty key", bucket: "bucket", key: "", wantErr: true},
+		{name: "absolute key", bucket: "bucket", key: "/etc/passwd", wantErr: true},
+		{name: "key with a backslash", bucket: "bucket", key: "a\\b.jpg", wantErr: true},
+		{name: "parent segment", bucket: "bucket", key: "../other/file.jpg", wantErr: true},
+		{name: "nested parent segment", bucket: "bucket", key: "images/../../file.jpg", wantErr: true},
+		{name: "dot segment", bucket: "bucket", key: "images/./file.jpg", wantErr: true},
+		{name: "empty segment", bucket: "bucket", key: "images//file.jpg", wantErr: true},
+		{name: "trailing slash", bucket: "bucket", key: "images/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateObjectPath(tt.bucket, tt.key)
+
+			if tt.wantErr && !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("validateObjectPath(%q, %q) error = %v, want %v", tt.bucket, tt.key, err, ErrInvalidKey)
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateObjectPath(%q, %q) error = %v, want nil", tt.bucket, tt.key, err)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	r := newTestRepository("secret")
+	base := r.sign(http.MethodPut, "bucket", "key", "image/png", "10", testChecksum, "100")
+
+	tests := []struct {
+		name      string
+		repo      *Repository
+		method    string
+		key       string
+		size      string
+		checksum  string
+		expires   string
+		wantEqual bool
+	}{
+		{name: "same input", repo: r, method: http.MethodPut, key: "key", size: "10", checksum: testChecksum, expires: "100", wantEqual: true},
+		{name: "other secret", repo: newTestRepository("other"), method: http.MethodPut, key: "key", size: "10", checksum: testChecksum, expires: "100"},
+		{name: "other method", repo: r, method: http.MethodGet, key: "key", size: "10", checksum: testChecksum, expires: "100"},
+		{name: "other key", repo: r, method: http.MethodPut, key: "other", size: "10", checksum: testChecksum, expires: "100"},
+		{name: "other size", repo: r, method: http.MethodPut, key: "key", size: "11", checksum: testChecksum, expires: "100"},
+		{name: "other checksum", repo: r, method: http.MethodPut, key: "key", size: "10", checksum: "", expires: "100"},
+		{name: "other expiry", repo: r, method: http.MethodPut, key: "key", size: "10", checksum: testChecksum, expires: "101"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.repo.sign(tt.method, "bucket", tt.key, "image/png", tt.size, tt.checksum, tt.expires)
+
+			if (got == base) != tt.wantEqual {
+				t.Errorf("sign() equal = %v, want %v", got == base, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestIsValidSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := newTestRepository("secret")
+
+	presignUpload := func(t *testing.T, expiry time.Duration) *url.URL {
+		presigned, err := r.PresignUpload(context.Background(), "bucket", "uploads/file", "image/png", 10, testChecksum, expiry)
+		if err != nil {
+			t.Fatalf("PresignUpload() error = %v", err)
+		}
+
+		u, err := url.Parse(presigned.URL)
+		if err != nil {
+			t.Fatalf("url.Parse() error = %v", err)
+		}
+
+		return u
+	}
+
+	tests := []struct {
+		name        string
+		expiry      time.Duration
+		method      string
+		key         string
+		contentType string
+		tamper      func(q url.Values)
+		want        bool
+	}{
+		{
+			name:        "presigned upload",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/png",
+			want:        true,
+		},
+		{
+			name:        "expired url",
+			expiry:      -time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/png",
+		},
+		{
+			name:        "other method",
+			expiry:      time.Minute,
+			method:      http.MethodGet,
+			key:         "uploads/file",
+			contentType: "image/png",
+		},
+		{
+			name:        "other key",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/other",
+			contentType: "image/png",
+		},
+		{
+			name:        "other content type",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/jpeg",
+		},
+		{
+			name:        "tampered signature",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/png",
+			tamper: func(q url.Values) {
+				signature := []byte(q.Get("signature"))
+				signature[0] ^= 1
+				q.Set("signature", string(signature))
+			},
+		},
+		{
+			name:        "extended expiry",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/png",
+			tamper:      func(q url.Values) { q.Set("expires", "99999999999") },
+		},
+		{
+			name:        "malformed expiry",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/png",
+			tamper:      func(q url.Values) { q.Set("expires", "soon") },
+		},
+		{
+			name:        "larger size",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/png",
+			tamper:      func(q url.Values) { q.Set("size", "1000000") },
+		},
+		{
+			name:        "other checksum",
+			expiry:      time.Minute,
+			method:      http.MethodPut,
+			key:         "uploads/file",
+			contentType: "image/png",
+			tamper:      func(q url.Values) { q.Del("checksum") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := presignUpload(t, tt.expiry)
+
+			q := u.Query()
+			if tt.tamper != nil {
+				tt.tamper(q)
+			}
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(tt.method, u.Path+"?"+q.Encode(), nil)
+
+			if got := r.isValidSignature(c, tt.method, "bucket", tt.key, tt.contentType); got != tt.want {
+				t.Errorf("isValidSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -49,6 +49,14 @@ func NullStrToStr(s *string) string {
 	return ""
 }
 
+func EmptyStrToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
 func GenRandNumber(n int) string {
 
 	minLimit := int(math.Pow10(n))
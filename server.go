@@ -2,35 +2,57 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/andibalo/meowhasiswa-be/internal/api"
 	v1 "github.com/andibalo/meowhasiswa-be/internal/api/v1"
+	"github.com/andibalo/meowhasiswa-be/internal/attachment"
 	"github.com/andibalo/meowhasiswa-be/internal/config"
+	"github.com/andibalo/meowhasiswa-be/internal/constants"
+	"github.com/andibalo/meowhasiswa-be/internal/leaderboard"
 	"github.com/andibalo/meowhasiswa-be/internal/middleware"
+	"github.com/andibalo/meowhasiswa-be/internal/outbox"
+	"github.com/andibalo/meowhasiswa-be/internal/ratelimit"
+	"github.com/andibalo/meowhasiswa-be/internal/realtime"
 	"github.com/andibalo/meowhasiswa-be/internal/repository"
 	"github.com/andibalo/meowhasiswa-be/internal/service"
+	"github.com/andibalo/meowhasiswa-be/internal/thread"
+	"github.com/andibalo/meowhasiswa-be/internal/worker"
 	"github.com/andibalo/meowhasiswa-be/pkg/httpclient"
 	"github.com/andibalo/meowhasiswa-be/pkg/integration/notifsvc"
 	"github.com/andibalo/meowhasiswa-be/pkg/mailer"
 	s3Repository "github.com/andibalo/meowhasiswa-be/pkg/s3"
+	"github.com/andibalo/meowhasiswa-be/pkg/storage"
 	"github.com/andibalo/meowhasiswa-be/pkg/trace"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	brevo "github.com/getbrevo/brevo-go/lib"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/uptrace/bun"
+	"go.uber.org/zap"
 
 	"net/http"
+	"sync"
 )
 
 type Server struct {
-	gin *gin.Engine
-	srv *http.Server
+	gin         *gin.Engine
+	srv         *http.Server
+	realtimeHub *realtime.Hub
+	// workers run the periodic background jobs
+	workers []*worker.Worker
 }
 
 func NewServer(cfg config.Config, tracer *trace.Tracer, db *bun.DB, s3Client *s3.Client) *Server {
 
 	router := gin.New()
 
+	// Without trusted proxies the client IP is the remote address, X-Forwarded-For could be spoofed otherwise
+	err := router.SetTrustedProxies(cfg.HttpTrustedProxies())
+	if err != nil {
+		cfg.Logger().Fatal("Failed to set trusted proxies", zap.Error(err))
+	}
+
 	router.Use(middleware.LogPreReq(cfg.Logger()))
 
 	if cfg.GetFlags().EnableTracer {
@@ -44,11 +66,26 @@ func NewServer(cfg config.Config, tracer *trace.Tracer, db *bun.DB, s3Client *s3
 
 	hc := httpclient.Init(httpclient.Options{Config: cfg})
 
-	s3Repo := s3Repository.NewS3Repository(cfg, s3Client)
+	fileRepo := newFileRepository(cfg, s3Client)
 	universityRepo := repository.NewUniversityRepository(db)
 	subThreadRepo := repository.NewSubThreadRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	threadRepo := repository.NewThreadRepository(db)
+	threadPollRepo := repository.NewThreadPollRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	imageUploadRepo := repository.NewImageUploadRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	reputationRepo := repository.NewReputationRepository(db)
+	badgeRepo := repository.NewBadgeRepository(db)
+	leaderboardRepo := repository.NewLeaderboardRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	userBlockRepo := repository.NewUserBlockRepository(db)
+	userFollowerRepo := repository.NewUserFollowerRepository(db)
 
 	brevoCfg := brevo.NewConfiguration()
 	brevoCfg.AddDefaultHeader("api-key", cfg.GetBrevoSvcCfg().APIKey)
@@ -57,26 +94,54 @@ func NewServer(cfg config.Config, tracer *trace.Tracer, db *bun.DB, s3Client *s3
 	brevoSvc := mailer.NewBrevoService(cfg, brevoCl)
 	notifCl := notifsvc.NewNotificationService(cfg, hc)
 
-	notifSvc := service.NewNotificationService(cfg, notifCl)
-	imageSvc := service.NewImageService(cfg, s3Repo)
-	universitySvc := service.NewUniversityService(cfg, universityRepo, userRepo, db)
-	authSvc := service.NewAuthService(cfg, userRepo, universityRepo, db, brevoSvc)
-	userSvc := service.NewUserService(cfg, userRepo, universityRepo)
-	subThreadSvc := service.NewSubThreadService(cfg, subThreadRepo, db)
-	threadSvc := service.NewThreadService(cfg, threadRepo, userRepo, notifCl, db)
-
-	ic := v1.NewImageController(cfg, imageSvc)
-	uc := v1.NewUserController(cfg, userSvc)
-	ac := v1.NewAuthController(cfg, authSvc)
-	stc := v1.NewSubThreadController(cfg, subThreadSvc)
-	tc := v1.NewThreadController(cfg, threadSvc)
-	unc := v1.NewUniversityController(cfg, universitySvc)
-	nc := v1.NewNotificationController(cfg, notifSvc)
-
-	registerHandlers(router, &api.HealthCheck{}, uc, ac, stc, tc, unc, ic, nc)
+	realtimeHub := realtime.NewHub(cfg, db)
+
+	notifSvc := service.NewNotificationService(cfg, notifCl, notificationRepo)
+	imageSvc := service.NewImageService(cfg, fileRepo, attachmentRepo, imageUploadRepo, db)
+	universitySvc := service.NewUniversityService(cfg, universityRepo, userRepo, badgeRepo, notificationRepo, db)
+	authSvc := service.NewAuthService(cfg, userRepo, universityRepo, outboxRepo, reputationRepo, db)
+	subThreadSvc := service.NewSubThreadService(cfg, subThreadRepo, roleRepo, userRepo, attachmentRepo, db)
+	threadSvc := service.NewThreadService(cfg, threadRepo, threadPollRepo, attachmentRepo, userRepo, subThreadRepo, roleRepo, reputationRepo, badgeRepo, notificationRepo, outboxRepo, revisionRepo, userBlockRepo, realtimeHub, db)
+	userSvc := service.NewUserService(cfg, userRepo, universityRepo, attachmentRepo, userBlockRepo, userFollowerRepo, threadSvc, db)
+	roleSvc := service.NewRoleService(cfg, roleRepo, userRepo, subThreadRepo, universityRepo)
+	moderationSvc := service.NewModerationService(cfg, reportRepo, threadRepo, universityRepo, userRepo, roleRepo, userSvc, realtimeHub, db)
+	searchSvc := service.NewSearchService(cfg, searchRepo, userRepo, roleRepo)
+	reputationSvc := service.NewReputationService(cfg, reputationRepo, userRepo)
+	leaderboardSvc := service.NewLeaderboardService(cfg, leaderboardRepo, subThreadRepo, userRepo, roleRepo)
+	bookmarkSvc := service.NewBookmarkService(cfg, bookmarkRepo, threadRepo, subThreadRepo, userRepo, roleRepo, db)
+
+	mw := middleware.NewMiddleware(cfg, userRepo, roleRepo, newRateLimitStore(cfg, db))
+
+	ic := v1.NewImageController(cfg, mw, imageSvc)
+	uc := v1.NewUserController(cfg, mw, userSvc)
+	ac := v1.NewAuthController(cfg, mw, authSvc)
+	stc := v1.NewSubThreadController(cfg, mw, subThreadSvc)
+	tc := v1.NewThreadController(cfg, mw, threadSvc)
+	unc := v1.NewUniversityController(cfg, mw, universitySvc)
+	nc := v1.NewNotificationController(cfg, mw, notifSvc)
+	rc := v1.NewRoleController(cfg, mw, roleSvc)
+	mc := v1.NewModerationController(cfg, mw, moderationSvc)
+	sc := v1.NewSearchController(cfg, mw, searchSvc)
+	repc := v1.NewReputationController(cfg, mw, reputationSvc)
+	lc := v1.NewLeaderboardController(cfg, mw, leaderboardSvc)
+	bc := v1.NewBookmarkController(cfg, mw, bookmarkSvc)
+
+	registerHandlers(router, &api.HealthCheck{}, uc, ac, stc, tc, unc, ic, nc, rc, mc, sc, repc, lc, bc)
+
+	// The local and memory storages serve their own files
+	if fh, ok := fileRepo.(api.Handler); ok {
+		registerHandlers(router, fh)
+	}
 
 	return &Server{
-		gin: router,
+		gin:         router,
+		realtimeHub: realtimeHub,
+		workers: []*worker.Worker{
+			outbox.NewDispatcher(cfg, outboxRepo, notificationRepo, userFollowerRepo, notifCl, brevoSvc, db),
+			attachment.NewCollector(cfg, attachmentRepo, imageUploadRepo, fileRepo, db),
+			leaderboard.NewRefresher(cfg, leaderboardRepo),
+			thread.NewArchiver(cfg, threadRepo),
+		},
 	}
 }
 
@@ -91,6 +156,47 @@ func (s *Server) Start(addr string) error {
 	return srv.ListenAndServe()
 }
 
+func (s *Server) StartWorkers() {
+	for _, w := range s.workers {
+		w.Start()
+	}
+}
+
+// ShutdownWorkers shuts the workers down concurrently so each of them has the whole ctx to finish its task
+func (s *Server) ShutdownWorkers(ctx context.Context) error {
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(s.workers))
+	)
+
+	for i, w := range s.workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := w.Shutdown(ctx)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", w.Name(), err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) StartRealtimeHub() {
+	s.realtimeHub.Start()
+}
+
+func (s *Server) ShutdownRealtimeHub() error {
+
+	return s.realtimeHub.Shutdown()
+}
+
 func (s *Server) GetGin() *gin.Engine {
 
 	return s.gin
@@ -101,6 +207,40 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.srv.Shutdown(ctx)
 }
 
+func newRateLimitStore(cfg config.Config, db *bun.DB) ratelimit.Store {
+	if cfg.GetRateLimitCfg().Store == constants.RATE_LIMIT_STORE_POSTGRES {
+		return ratelimit.NewPostgresStore(cfg, db)
+	}
+
+	return ratelimit.NewMemoryStore()
+}
+
+func newFileRepository(cfg config.Config, s3Client *s3.Client) repository.FileRepository {
+	storageCfg := cfg.GetStorageCfg()
+
+	if storageCfg.Driver != constants.STORAGE_DRIVER_S3 && storageCfg.SigningSecret == "" {
+		cfg.Logger().Fatal("STORAGE_SIGNING_SECRET is required by the local and memory file storages")
+	}
+
+	if storageCfg.SigningSecret != "" && storageCfg.SigningSecret == cfg.GetAuthCfg().JWTSecret {
+		cfg.Logger().Fatal("STORAGE_SIGNING_SECRET must not be the same as JWT_SECRET")
+	}
+
+	switch storageCfg.Driver {
+	case constants.STORAGE_DRIVER_LOCAL:
+		localRepo, err := storage.NewLocalRepository(cfg)
+		if err != nil {
+			cfg.Logger().Fatal("Failed to init local file storage", zap.Error(err))
+		}
+
+		return localRepo
+	case constants.STORAGE_DRIVER_MEMORY:
+		return storage.NewMemoryRepository(cfg)
+	default:
+		return s3Repository.NewS3Repository(cfg, s3Client)
+	}
+}
+
 func registerHandlers(g *gin.Engine, handlers ...api.Handler) {
 	for _, handler := range handlers {
 		handler.AddRoutes(g)